@@ -0,0 +1,198 @@
+// Package address validates the Japanese postal addresses shared by the
+// gateway and the services that store them, so every service accepts and
+// stores addresses in the same normalized form.
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrRequired 必須項目が入力されていない
+	ErrRequired = errors.New("postal code, prefecture, city and address line 1 are required")
+	// ErrInvalidPostalCode 郵便番号が7桁の数字ではない
+	ErrInvalidPostalCode = errors.New("postal code must be 7 digits such as 100-0001")
+	// ErrInvalidPrefecture 都道府県名が47都道府県のいずれでもない
+	ErrInvalidPrefecture = errors.New("prefecture must be one of the 47 prefectures of Japan")
+	// ErrInvalidPhoneNumber 電話番号が日本の電話番号の形式ではない
+	ErrInvalidPhoneNumber = errors.New("phone number must be a Japanese number such as 03-1234-5678 or +819012345678")
+)
+
+// Address 日本国内の住所の値オブジェクト
+// 郵便番号は 100-0001 の形式、都道府県は漢字の正式名称で保持する
+type Address struct {
+	postalCode   string
+	prefecture   string
+	city         string
+	addressLine1 string
+	addressLine2 string
+	phoneNumber  string
+}
+
+// New validates and normalizes an address.
+// AddressLine2 and PhoneNumber are optional. The postal code may be written
+// with or without the hyphen and in full-width digits, and the prefecture
+// may be written in romaji such as "Tokyo".
+func New(postalCode, prefecture, city, addressLine1, addressLine2, phoneNumber string) (Address, error) {
+	postalCode = strings.TrimSpace(postalCode)
+	prefecture = strings.TrimSpace(prefecture)
+	city = strings.TrimSpace(city)
+	addressLine1 = strings.TrimSpace(addressLine1)
+	addressLine2 = strings.TrimSpace(addressLine2)
+	phoneNumber = strings.TrimSpace(phoneNumber)
+
+	if postalCode == "" || prefecture == "" || city == "" || addressLine1 == "" {
+		return Address{}, ErrRequired
+	}
+
+	normalizedPostalCode, err := NormalizePostalCode(postalCode)
+	if err != nil {
+		return Address{}, err
+	}
+
+	normalizedPrefecture, ok := NormalizePrefecture(prefecture)
+	if !ok {
+		return Address{}, ErrInvalidPrefecture
+	}
+
+	if phoneNumber != "" && !isJapanesePhoneNumber(phoneNumber) {
+		return Address{}, ErrInvalidPhoneNumber
+	}
+
+	return Address{
+		postalCode:   normalizedPostalCode,
+		prefecture:   normalizedPrefecture,
+		city:         city,
+		addressLine1: addressLine1,
+		addressLine2: addressLine2,
+		phoneNumber:  phoneNumber,
+	}, nil
+}
+
+func (a Address) PostalCode() string   { return a.postalCode }
+func (a Address) Prefecture() string   { return a.prefecture }
+func (a Address) City() string         { return a.city }
+func (a Address) AddressLine1() string { return a.addressLine1 }
+func (a Address) AddressLine2() string { return a.addressLine2 }
+func (a Address) PhoneNumber() string  { return a.phoneNumber }
+
+// IsZero reports whether the address was not set
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// NormalizePostalCode returns a postal code in the 100-0001 form.
+// A leading 〒 and full-width digits are accepted.
+func NormalizePostalCode(postalCode string) (string, error) {
+	postalCode = strings.TrimPrefix(strings.TrimSpace(postalCode), "〒")
+
+	digits := make([]rune, 0, 7)
+	for i, r := range []rune(postalCode) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, r)
+		case r >= '０' && r <= '９':
+			digits = append(digits, '0'+(r-'０'))
+		case (r == '-' || r == '－') && i == 3:
+			// 上3桁と下4桁の区切り
+		default:
+			return "", ErrInvalidPostalCode
+		}
+	}
+	if len(digits) != 7 {
+		return "", ErrInvalidPostalCode
+	}
+
+	return string(digits[:3]) + "-" + string(digits[3:]), nil
+}
+
+// NormalizePrefecture returns the official name of a prefecture written
+// in kanji or romaji, and false if it is not a prefecture of Japan
+func NormalizePrefecture(prefecture string) (string, bool) {
+	prefecture = strings.TrimSpace(prefecture)
+	for _, p := range prefectures {
+		if prefecture == p.name || strings.EqualFold(prefecture, p.romaji) {
+			return p.name, true
+		}
+	}
+	return "", false
+}
+
+// isJapanesePhoneNumber reports whether a phone number is a Japanese number:
+// 10 or 11 digits starting with 0, optionally separated by hyphens, or the
+// same number in E.164 form such as +819012345678
+func isJapanesePhoneNumber(phoneNumber string) bool {
+	if national, ok := strings.CutPrefix(phoneNumber, "+81"); ok {
+		phoneNumber = "0" + national
+	}
+
+	digits := 0
+	for i, r := range phoneNumber {
+		switch {
+		case r >= '0' && r <= '9':
+			if digits == 0 && r != '0' {
+				return false
+			}
+			digits++
+		case r == '-' && i > 0 && i < len(phoneNumber)-1:
+		default:
+			return false
+		}
+	}
+	return digits == 10 || digits == 11
+}
+
+// prefectures 都道府県コード順の47都道府県
+var prefectures = []struct {
+	name   string
+	romaji string
+}{
+	{"北海道", "Hokkaido"},
+	{"青森県", "Aomori"},
+	{"岩手県", "Iwate"},
+	{"宮城県", "Miyagi"},
+	{"秋田県", "Akita"},
+	{"山形県", "Yamagata"},
+	{"福島県", "Fukushima"},
+	{"茨城県", "Ibaraki"},
+	{"栃木県", "Tochigi"},
+	{"群馬県", "Gunma"},
+	{"埼玉県", "Saitama"},
+	{"千葉県", "Chiba"},
+	{"東京都", "Tokyo"},
+	{"神奈川県", "Kanagawa"},
+	{"新潟県", "Niigata"},
+	{"富山県", "Toyama"},
+	{"石川県", "Ishikawa"},
+	{"福井県", "Fukui"},
+	{"山梨県", "Yamanashi"},
+	{"長野県", "Nagano"},
+	{"岐阜県", "Gifu"},
+	{"静岡県", "Shizuoka"},
+	{"愛知県", "Aichi"},
+	{"三重県", "Mie"},
+	{"滋賀県", "Shiga"},
+	{"京都府", "Kyoto"},
+	{"大阪府", "Osaka"},
+	{"兵庫県", "Hyogo"},
+	{"奈良県", "Nara"},
+	{"和歌山県", "Wakayama"},
+	{"鳥取県", "Tottori"},
+	{"島根県", "Shimane"},
+	{"岡山県", "Okayama"},
+	{"広島県", "Hiroshima"},
+	{"山口県", "Yamaguchi"},
+	{"徳島県", "Tokushima"},
+	{"香川県", "Kagawa"},
+	{"愛媛県", "Ehime"},
+	{"高知県", "Kochi"},
+	{"福岡県", "Fukuoka"},
+	{"佐賀県", "Saga"},
+	{"長崎県", "Nagasaki"},
+	{"熊本県", "Kumamoto"},
+	{"大分県", "Oita"},
+	{"宮崎県", "Miyazaki"},
+	{"鹿児島県", "Kagoshima"},
+	{"沖縄県", "Okinawa"},
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tokenRefreshMargin is how long before expiry a cached service token is replaced
+const tokenRefreshMargin = time.Minute
+
+// TokenSource obtains a service token and its expiry, typically through the
+// auth service's client credentials grant
+type TokenSource interface {
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (string, time.Time, error)
+
+// Token calls f
+func (f TokenSourceFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return f(ctx)
+}
+
+// ClientCredentials implements grpc.PerRPCCredentials for calls between
+// backend services. It attaches a service token to every call and fetches a
+// new one from the TokenSource shortly before the cached token expires.
+type ClientCredentials struct {
+	source TokenSource
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewClientCredentials creates ClientCredentials that obtain tokens from source.
+// Pass it to grpc.WithPerRPCCredentials when dialing another service.
+func NewClientCredentials(source TokenSource) *ClientCredentials {
+	return &ClientCredentials{source: source}
+}
+
+// GetRequestMetadata returns the authorization metadata of a call
+func (c *ClientCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{AuthorizationMetadataKey: "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity reports whether the credentials require TLS.
+// Services talk over the cluster network without TLS, like the rest of the system.
+func (c *ClientCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// currentToken returns the cached token, fetching a new one when it is about to expire
+func (c *ClientCredentials) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenRefreshMargin).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token, expiresAt, err := c.source.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+	c.expiresAt = expiresAt
+	return token, nil
+}
+
+type accessTokenContextKey struct{}
+
+// WithAccessToken returns a copy of ctx carrying the access token of the end
+// user, for UnaryClientInterceptor to forward
+func WithAccessToken(ctx context.Context, accessToken string) context.Context {
+	return context.WithValue(ctx, accessTokenContextKey{}, accessToken)
+}
+
+// AccessTokenFromContext returns the access token stored by WithAccessToken
+func AccessTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(accessTokenContextKey{}).(string)
+	return token, ok && token != ""
+}
+
+// UnaryClientInterceptor forwards the end user's access token stored in the
+// context as a bearer token, so the called service authorizes the call as
+// that user. Calls without a token are sent unauthenticated.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if token, ok := AccessTokenFromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, AuthorizationMetadataKey, "Bearer "+token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import "fmt"
+
+// EmailVerificationPolicy tells which actions require a verified email address
+type EmailVerificationPolicy string
+
+const (
+	// EmailVerificationOptional never requires a verified email address
+	EmailVerificationOptional EmailVerificationPolicy = "optional"
+	// EmailVerificationRequiredForOrders lets users log in but not place orders until verified
+	EmailVerificationRequiredForOrders EmailVerificationPolicy = "orders"
+	// EmailVerificationRequiredForLogin rejects logins until the email address is verified
+	EmailVerificationRequiredForLogin EmailVerificationPolicy = "login"
+)
+
+// ParseEmailVerificationPolicy parses a policy name. An empty name is the optional policy.
+func ParseEmailVerificationPolicy(name string) (EmailVerificationPolicy, error) {
+	switch policy := EmailVerificationPolicy(name); policy {
+	case "":
+		return EmailVerificationOptional, nil
+	case EmailVerificationOptional, EmailVerificationRequiredForOrders, EmailVerificationRequiredForLogin:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown email verification policy %q", name)
+	}
+}
+
+// RequiredForLogin reports whether unverified users are rejected at login
+func (p EmailVerificationPolicy) RequiredForLogin() bool {
+	return p == EmailVerificationRequiredForLogin
+}
+
+// RequiredForOrders reports whether unverified users are rejected when placing orders.
+// Users who cannot log in cannot place orders either.
+func (p EmailVerificationPolicy) RequiredForOrders() bool {
+	return p == EmailVerificationRequiredForOrders || p == EmailVerificationRequiredForLogin
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public part of every key in the ring
+func (r *KeyRing) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range r.Keys() {
+		jwk, err := key.JWK()
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// JWK returns the public part of the key in JSON Web Key format
+func (k *Key) JWK() (JWK, error) {
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Method.Alg(),
+	}
+
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", k.PublicKey)
+	}
+
+	return jwk, nil
+}
+
+// Key converts a JSON Web Key to a verification-only Key
+func (j JWK) Key() (*Key, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return NewPublicKey(j.KeyID, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		})
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key size %d", len(x))
+		}
+		return NewPublicKey(j.KeyID, ed25519.PublicKey(x))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.KeyType)
+	}
+}
+
+// NewKeyRingFromJWKS creates a verification-only KeyRing from a JSON Web Key Set
+func NewKeyRingFromJWKS(set JWKS) (*KeyRing, error) {
+	keys := make([]*Key, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.Key()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %q: %w", jwk.KeyID, err)
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyRing("", keys...)
+}
+
+// FetchKeyRing downloads a JSON Web Key Set, such as the gateway's
+// /.well-known/jwks.json, and creates a verification-only KeyRing from it
+func FetchKeyRing(ctx context.Context, client *http.Client, url string) (*KeyRing, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	return NewKeyRingFromJWKS(set)
+}
+
+// RemoteKeySet verifies tokens with the keys published at a JWKS URL.
+// The keys are fetched on first use and fetched again when a token names an
+// unknown key, so services pick up rotated keys without restarting.
+type RemoteKeySet struct {
+	client      *http.Client
+	url         string
+	minInterval time.Duration
+
+	mu        sync.Mutex
+	ring      *KeyRing
+	fetchedAt time.Time
+}
+
+// NewRemoteKeySet creates a RemoteKeySet for the given JWKS URL.
+// The keys are fetched at most once per minInterval.
+func NewRemoteKeySet(client *http.Client, url string, minInterval time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		client:      client,
+		url:         url,
+		minInterval: minInterval,
+	}
+}
+
+// Key returns the key with the given ID, fetching the key set when the ID is unknown
+func (s *RemoteKeySet) Key(id string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ring != nil {
+		if key, err := s.ring.Key(id); err == nil {
+			return key, nil
+		}
+	}
+
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < s.minInterval {
+		return nil, ErrUnknownKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s.fetchedAt = time.Now()
+	ring, err := FetchKeyRing(ctx, s.client, s.url)
+	if err != nil {
+		return nil, err
+	}
+	s.ring = ring
+
+	return ring.Key(id)
+}
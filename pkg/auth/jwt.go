@@ -1,60 +1,222 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("expired token")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrExpiredToken   = errors.New("expired token")
+	ErrWrongTokenType = errors.New("wrong token type")
+)
+
+// TokenType tells what a token may be used for
+type TokenType string
+
+const (
+	// TokenTypeAccess is used to call APIs on behalf of a user
+	TokenTypeAccess TokenType = "access"
+	// TokenTypeRefresh is only accepted by the auth service to obtain new tokens
+	TokenTypeRefresh TokenType = "refresh"
+	// TokenTypeMFAChallenge proves the password was verified and is exchanged
+	// for real tokens once the second factor is verified
+	TokenTypeMFAChallenge TokenType = "mfa_challenge"
+	// TokenTypeEmailVerification is sent to a user's email address to prove they own it
+	TokenTypeEmailVerification TokenType = "email_verification"
+	// TokenTypeService is issued to backend services by the client credentials
+	// grant and authorizes calls between services by scope
+	TokenTypeService TokenType = "service"
+)
+
+const (
+	// DefaultIssuer is the issuer of tokens minted by the auth service
+	DefaultIssuer = "kube-ec-auth"
+	// AudienceAPI is the audience of access tokens accepted by the API services
+	AudienceAPI = "kube-ec-api"
+	// AudienceAuth is the audience of refresh tokens, accepted only by the auth service
+	AudienceAuth = "kube-ec-auth"
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenType TokenType `json:"typ"`
+	// Roles are only carried by access tokens
+	Roles []string `json:"roles,omitempty"`
+	// ClientID and Scope are only carried by service tokens.
+	// Scope is a space-separated list as in RFC 9068.
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Scopes returns the scopes granted to a service token
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope reports whether a service token was granted the scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// JWTManager signs tokens with either a shared HS256 secret or the active key
+// of a KeyRing. Tokens signed with a KeyRing carry the key ID in the kid header
+// so they can be verified offline with the published public keys.
 type JWTManager struct {
-	secretKey     string
+	secretKey string
+	keyRing   *KeyRing
+	// keys looks up verification keys by ID. It is nil when tokens are signed with the secret.
+	keys          keySource
+	issuer        string
 	tokenDuration time.Duration
 }
 
-func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
+// keySource looks up the keys that verify tokens by key ID
+type keySource interface {
+	Key(id string) (*Key, error)
+}
+
+func NewJWTManager(secretKey, issuer string, tokenDuration time.Duration) *JWTManager {
 	return &JWTManager{
 		secretKey:     secretKey,
+		issuer:        issuer,
+		tokenDuration: tokenDuration,
+	}
+}
+
+func NewJWTManagerWithKeyRing(keyRing *KeyRing, issuer string, tokenDuration time.Duration) *JWTManager {
+	return &JWTManager{
+		keyRing:       keyRing,
+		keys:          keyRing,
+		issuer:        issuer,
 		tokenDuration: tokenDuration,
 	}
 }
 
-func (m *JWTManager) Generate(userID, email string) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+// NewJWTManagerWithRemoteKeys creates a JWTManager that only verifies tokens,
+// with the public keys published by the issuer
+func NewJWTManagerWithRemoteKeys(keys *RemoteKeySet, issuer string) *JWTManager {
+	return &JWTManager{
+		keys:   keys,
+		issuer: issuer,
+	}
+}
+
+// NewVerifier creates a JWTManager for services that only verify access
+// tokens. Tokens are verified with the keys published at jwksURL when it is
+// set, otherwise with the shared HS256 secret.
+func NewVerifier(jwksURL, secretKey string) (*JWTManager, error) {
+	if jwksURL != "" {
+		keys := NewRemoteKeySet(&http.Client{Timeout: 5 * time.Second}, jwksURL, time.Minute)
+		return NewJWTManagerWithRemoteKeys(keys, DefaultIssuer), nil
+	}
+	if secretKey == "" {
+		return nil, errors.New("a JWKS URL or a secret is required")
+	}
+	return NewJWTManager(secretKey, DefaultIssuer, 0), nil
+}
+
+func (m *JWTManager) Generate(userID, email string, tokenType TokenType, audience ...string) (string, error) {
+	token, _, err := m.GenerateWithClaims(userID, email, tokenType, audience...)
+	return token, err
+}
+
+// GenerateWithClaims generates a token and also returns the claims it carries,
+// so callers can keep track of the token ID (jti)
+func (m *JWTManager) GenerateWithClaims(userID, email string, tokenType TokenType, audience ...string) (string, *Claims, error) {
+	return m.GenerateWithRoles(userID, email, nil, tokenType, audience...)
+}
+
+// GenerateWithRoles generates a token carrying the roles of the user and also
+// returns its claims
+func (m *JWTManager) GenerateWithRoles(userID, email string, roles []string, tokenType TokenType, audience ...string) (string, *Claims, error) {
+	return m.generate(&Claims{
+		UserID:    userID,
+		Email:     email,
+		TokenType: tokenType,
+		Roles:     roles,
+	}, userID, audience)
+}
+
+// GenerateServiceToken generates a service token granting scopes to a
+// backend service and also returns its claims
+func (m *JWTManager) GenerateServiceToken(clientID string, scopes []string, audience ...string) (string, *Claims, error) {
+	return m.generate(&Claims{
+		TokenType: TokenTypeService,
+		ClientID:  clientID,
+		Scope:     strings.Join(scopes, " "),
+	}, clientID, audience)
+}
+
+// generate fills in the registered claims and signs the token
+func (m *JWTManager) generate(claims *Claims, subject string, audience []string) (string, *Claims, error) {
+	// A unique token ID keeps two tokens issued in the same second distinct
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        tokenID,
+		Issuer:    m.issuer,
+		Subject:   subject,
+		Audience:  audience,
+		ExpiresAt: jwt.NewNumericDate(now.Add(m.tokenDuration)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	signed, err := m.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+func (m *JWTManager) sign(claims *Claims) (string, error) {
+	if m.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.secretKey))
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	if m.keyRing == nil {
+		return "", ErrNoSigningKey
+	}
+
+	key, err := m.keyRing.Active()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
 }
 
-func (m *JWTManager) Verify(tokenString string) (*Claims, error) {
+// Verify validates a token and checks that it is of the expected type and was
+// issued for the expected audience
+func (m *JWTManager) Verify(tokenString string, expectedType TokenType, expectedAudience string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&Claims{},
-		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, ErrInvalidToken
-			}
-			return []byte(m.secretKey), nil
-		},
+		m.verificationKey,
+		jwt.WithIssuer(m.issuer),
+		jwt.WithAudience(expectedAudience),
+		jwt.WithExpirationRequired(),
 	)
 
 	if err != nil {
@@ -70,5 +232,45 @@ func (m *JWTManager) Verify(tokenString string) (*Claims, error) {
 		return nil, ErrExpiredToken
 	}
 
+	if claims.TokenType != expectedType {
+		return nil, ErrWrongTokenType
+	}
+
 	return claims, nil
 }
+
+// verificationKey selects the key to verify a token with.
+// Tokens signed with a KeyRing are matched by their kid header, and the
+// algorithm must match the key so a public key can never be used as an HMAC secret.
+func (m *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(m.secretKey), nil
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := m.keys.Key(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+
+	return key.PublicKey, nil
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
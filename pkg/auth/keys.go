@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key")
+	ErrUnknownKey   = errors.New("unknown key id")
+)
+
+// Key is an asymmetric key used to sign or verify tokens.
+// Keys without a private part can only verify.
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// CanSign reports whether the key has a private part
+func (k *Key) CanSign() bool {
+	return k.PrivateKey != nil
+}
+
+// KeyRing holds the key used to sign new tokens together with older keys that
+// are still accepted for verification, which allows rotating keys without
+// invalidating tokens already issued
+type KeyRing struct {
+	activeID string
+	keys     map[string]*Key
+}
+
+// NewKeyRing creates a KeyRing. activeID names the key used for signing and
+// may be empty for a verification-only key ring.
+func NewKeyRing(activeID string, keys ...*Key) (*KeyRing, error) {
+	ring := &KeyRing{
+		activeID: activeID,
+		keys:     make(map[string]*Key, len(keys)),
+	}
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key id is required")
+		}
+		if _, exists := ring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ring.keys[key.ID] = key
+	}
+
+	if activeID != "" {
+		active, ok := ring.keys[activeID]
+		if !ok {
+			return nil, fmt.Errorf("active key %q not found", activeID)
+		}
+		if !active.CanSign() {
+			return nil, fmt.Errorf("active key %q has no private key", activeID)
+		}
+	}
+
+	return ring, nil
+}
+
+// LoadKeyRing loads every *.pem file in dir, such as a mounted Kubernetes
+// secret. The file name without extension is used as the key ID.
+func LoadKeyRing(dir, activeID string) (*KeyRing, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no PEM files found in %s", dir)
+	}
+
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", path, err)
+		}
+
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := ParseKeyPEM(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeyRing(activeID, keys...)
+}
+
+// Active returns the key used to sign new tokens
+func (r *KeyRing) Active() (*Key, error) {
+	key, ok := r.keys[r.activeID]
+	if !ok {
+		return nil, ErrNoSigningKey
+	}
+	return key, nil
+}
+
+// Key returns the key with the given ID
+func (r *KeyRing) Key(id string) (*Key, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// Keys returns every key sorted by ID
+func (r *KeyRing) Keys() []*Key {
+	keys := make([]*Key, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// ParseKeyPEM parses an RSA or Ed25519 key in PEM format.
+// Private keys may be PKCS#8 or PKCS#1, public keys PKIX or PKCS#1.
+func ParseKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPrivateKey(id, parsed)
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newPrivateKey(id, parsed)
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKey(id, parsed)
+	case "RSA PUBLIC KEY":
+		parsed, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return NewPublicKey(id, parsed)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// NewPrivateKey creates a signing Key from an RSA or Ed25519 private key
+func NewPrivateKey(id string, privateKey crypto.Signer) (*Key, error) {
+	return newPrivateKey(id, privateKey)
+}
+
+// NewPublicKey creates a verification-only Key from an RSA or Ed25519 public key
+func NewPublicKey(id string, publicKey crypto.PublicKey) (*Key, error) {
+	method, err := signingMethodFor(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, Method: method, PublicKey: publicKey}, nil
+}
+
+func newPrivateKey(id string, parsed any) (*Key, error) {
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+
+	key, err := NewPublicKey(id, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.PrivateKey = signer
+	return key, nil
+}
+
+// signingMethodFor returns the JWT signing method for a public key
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+)
+
+// Roles granted to users. Deployments may define custom roles as well;
+// they are only meaningful where a permission table names them.
+const (
+	// RoleCustomer is granted to every user on registration
+	RoleCustomer = "customer"
+	// RoleStaff manages the catalog and fulfils orders
+	RoleStaff = "staff"
+	// RoleAdmin can do anything, including managing roles
+	RoleAdmin = "admin"
+)
+
+// Permissions maps an operation to the roles allowed to call it.
+// gRPC operations are keyed by their full method name and HTTP operations by
+// "METHOD /route/path". An empty list lets any authenticated caller through,
+// and operations missing from the table are public.
+type Permissions map[string][]string
+
+// Requires reports whether the operation needs an authenticated caller
+func (p Permissions) Requires(operation string) bool {
+	_, ok := p[operation]
+	return ok
+}
+
+// Allows reports whether a caller with the given roles may call the operation.
+// Admins may call every operation.
+func (p Permissions) Allows(operation string, roles []string) bool {
+	allowed, ok := p[operation]
+	if !ok || len(allowed) == 0 {
+		return true
+	}
+	return HasAnyRole(roles, append(allowed, RoleAdmin)...)
+}
+
+// HasAnyRole reports whether roles contains any of the allowed roles
+func HasAnyRole(roles []string, allowed ...string) bool {
+	for _, role := range roles {
+		for _, a := range allowed {
+			if role == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type claimsContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the claims of the authenticated caller
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// FromContext returns the claims of the authenticated caller, if any
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,18 @@
+package auth
+
+// Scopes granted to backend services through the client credentials grant.
+// They are named <service>:<action> after the service that checks them.
+const (
+	// ScopeProductStock allows reserving and updating stock
+	ScopeProductStock = "product:stock"
+	// ScopeOrderCreate allows placing orders on behalf of users
+	ScopeOrderCreate = "order:create"
+	// ScopeOrderStatus allows moving orders through their statuses
+	ScopeOrderStatus = "order:status"
+	// ScopePaymentProcess allows creating and processing payments
+	ScopePaymentProcess = "payment:process"
+	// ScopePaymentRefund allows refunding payments
+	ScopePaymentRefund = "payment:refund"
+	// ScopePaymentRead allows reading payments
+	ScopePaymentRead = "payment:read"
+)
@@ -0,0 +1,69 @@
+// Package clientip carries the IP address of the end user from the gateway to
+// the services it calls, so they can apply per-IP limits.
+package clientip
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MetadataKey is the gRPC metadata key holding the client IP
+const MetadataKey = "x-client-ip"
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the client IP
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromContext returns the client IP stored by NewContext
+func FromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(contextKey{}).(string)
+	return ip, ok && ip != ""
+}
+
+// UnaryClientInterceptor forwards the client IP stored in the context as
+// outgoing gRPC metadata
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if ip, ok := FromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, ip)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// FromIncomingContext returns the client IP forwarded in the metadata of an
+// incoming gRPC call. Calls that were not forwarded, or carry a value that is
+// not an IP address, fall back to the address of the caller.
+func FromIncomingContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			if ip := net.ParseIP(values[len(values)-1]); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
@@ -8,14 +8,17 @@ import (
 )
 
 var (
-	ErrNotFound          = New(codes.NotFound, "resource not found")
-	ErrAlreadyExists     = New(codes.AlreadyExists, "resource already exists")
-	ErrInvalidArgument   = New(codes.InvalidArgument, "invalid argument")
-	ErrUnauthenticated   = New(codes.Unauthenticated, "unauthenticated")
-	ErrPermissionDenied  = New(codes.PermissionDenied, "permission denied")
-	ErrInternal          = New(codes.Internal, "internal server error")
-	ErrUnavailable       = New(codes.Unavailable, "service unavailable")
-	ErrInsufficientStock = New(codes.FailedPrecondition, "insufficient stock")
+	ErrNotFound           = New(codes.NotFound, "resource not found")
+	ErrAlreadyExists      = New(codes.AlreadyExists, "resource already exists")
+	ErrInvalidArgument    = New(codes.InvalidArgument, "invalid argument")
+	ErrUnauthenticated    = New(codes.Unauthenticated, "unauthenticated")
+	ErrPermissionDenied   = New(codes.PermissionDenied, "permission denied")
+	ErrInternal           = New(codes.Internal, "internal server error")
+	ErrUnavailable        = New(codes.Unavailable, "service unavailable")
+	ErrFailedPrecondition = New(codes.FailedPrecondition, "failed precondition")
+	ErrInsufficientStock  = New(codes.FailedPrecondition, "insufficient stock")
+	ErrEmailNotVerified   = New(codes.FailedPrecondition, "email not verified")
+	ErrTooManyAttempts    = New(codes.ResourceExhausted, "too many attempts, try again later")
 )
 
 type Error struct {
@@ -0,0 +1,538 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.0
+// source: proto/checkout/checkout.proto
+
+package checkout
+
+import (
+	common "github.com/Riku-KANO/kube-ec/proto/common"
+	payment "github.com/Riku-KANO/kube-ec/proto/payment"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CheckoutStatus int32
+
+const (
+	CheckoutStatus_CHECKOUT_STATUS_UNSPECIFIED CheckoutStatus = 0
+	CheckoutStatus_CHECKOUT_STATUS_IN_PROGRESS CheckoutStatus = 1
+	CheckoutStatus_CHECKOUT_STATUS_COMPLETED   CheckoutStatus = 2
+	CheckoutStatus_CHECKOUT_STATUS_FAILED      CheckoutStatus = 3
+)
+
+// Enum value maps for CheckoutStatus.
+var (
+	CheckoutStatus_name = map[int32]string{
+		0: "CHECKOUT_STATUS_UNSPECIFIED",
+		1: "CHECKOUT_STATUS_IN_PROGRESS",
+		2: "CHECKOUT_STATUS_COMPLETED",
+		3: "CHECKOUT_STATUS_FAILED",
+	}
+	CheckoutStatus_value = map[string]int32{
+		"CHECKOUT_STATUS_UNSPECIFIED": 0,
+		"CHECKOUT_STATUS_IN_PROGRESS": 1,
+		"CHECKOUT_STATUS_COMPLETED":   2,
+		"CHECKOUT_STATUS_FAILED":      3,
+	}
+)
+
+func (x CheckoutStatus) Enum() *CheckoutStatus {
+	p := new(CheckoutStatus)
+	*p = x
+	return p
+}
+
+func (x CheckoutStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CheckoutStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_checkout_checkout_proto_enumTypes[0].Descriptor()
+}
+
+func (CheckoutStatus) Type() protoreflect.EnumType {
+	return &file_proto_checkout_checkout_proto_enumTypes[0]
+}
+
+func (x CheckoutStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CheckoutStatus.Descriptor instead.
+func (CheckoutStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{0}
+}
+
+// CheckoutFailure tells why a checkout failed
+type CheckoutFailure int32
+
+const (
+	CheckoutFailure_CHECKOUT_FAILURE_UNSPECIFIED CheckoutFailure = 0
+	// A product does not exist, is not for sale or is priced in another currency
+	CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE CheckoutFailure = 1
+	CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK        CheckoutFailure = 2
+	CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED    CheckoutFailure = 3
+	// A service failed, or the coordinator stopped before the payment was processed
+	CheckoutFailure_CHECKOUT_FAILURE_INTERNAL CheckoutFailure = 4
+)
+
+// Enum value maps for CheckoutFailure.
+var (
+	CheckoutFailure_name = map[int32]string{
+		0: "CHECKOUT_FAILURE_UNSPECIFIED",
+		1: "CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE",
+		2: "CHECKOUT_FAILURE_OUT_OF_STOCK",
+		3: "CHECKOUT_FAILURE_PAYMENT_DECLINED",
+		4: "CHECKOUT_FAILURE_INTERNAL",
+	}
+	CheckoutFailure_value = map[string]int32{
+		"CHECKOUT_FAILURE_UNSPECIFIED":         0,
+		"CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE": 1,
+		"CHECKOUT_FAILURE_OUT_OF_STOCK":        2,
+		"CHECKOUT_FAILURE_PAYMENT_DECLINED":    3,
+		"CHECKOUT_FAILURE_INTERNAL":            4,
+	}
+)
+
+func (x CheckoutFailure) Enum() *CheckoutFailure {
+	p := new(CheckoutFailure)
+	*p = x
+	return p
+}
+
+func (x CheckoutFailure) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CheckoutFailure) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_checkout_checkout_proto_enumTypes[1].Descriptor()
+}
+
+func (CheckoutFailure) Type() protoreflect.EnumType {
+	return &file_proto_checkout_checkout_proto_enumTypes[1]
+}
+
+func (x CheckoutFailure) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CheckoutFailure.Descriptor instead.
+func (CheckoutFailure) EnumDescriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{1}
+}
+
+type CheckoutItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutItem) Reset() {
+	*x = CheckoutItem{}
+	mi := &file_proto_checkout_checkout_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutItem) ProtoMessage() {}
+
+func (x *CheckoutItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_checkout_checkout_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutItem.ProtoReflect.Descriptor instead.
+func (*CheckoutItem) Descriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckoutItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CheckoutItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CheckoutRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items           []*CheckoutItem        `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	ShippingAddress *common.Address        `protobuf:"bytes,3,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	PaymentMethod   payment.PaymentMethod  `protobuf:"varint,4,opt,name=payment_method,json=paymentMethod,proto3,enum=payment.PaymentMethod" json:"payment_method,omitempty"`
+	PaymentToken    string                 `protobuf:"bytes,5,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"` // 決済トークン（カード情報など）。保存されない
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CheckoutRequest) Reset() {
+	*x = CheckoutRequest{}
+	mi := &file_proto_checkout_checkout_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutRequest) ProtoMessage() {}
+
+func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_checkout_checkout_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckoutRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckoutRequest) GetItems() []*CheckoutItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CheckoutRequest) GetShippingAddress() *common.Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *CheckoutRequest) GetPaymentMethod() payment.PaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return payment.PaymentMethod(0)
+}
+
+func (x *CheckoutRequest) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+type GetCheckoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutRequest) Reset() {
+	*x = GetCheckoutRequest{}
+	mi := &file_proto_checkout_checkout_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutRequest) ProtoMessage() {}
+
+func (x *GetCheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_checkout_checkout_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCheckoutRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CheckoutResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status         CheckoutStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=checkout.CheckoutStatus" json:"status,omitempty"`
+	OrderId        string                 `protobuf:"bytes,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PaymentId      string                 `protobuf:"bytes,5,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	TransactionId  string                 `protobuf:"bytes,6,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Failure        CheckoutFailure        `protobuf:"varint,7,opt,name=failure,proto3,enum=checkout.CheckoutFailure" json:"failure,omitempty"`
+	FailureMessage string                 `protobuf:"bytes,8,opt,name=failure_message,json=failureMessage,proto3" json:"failure_message,omitempty"`
+	CreatedAt      *common.Timestamp      `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *common.Timestamp      `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CheckoutResponse) Reset() {
+	*x = CheckoutResponse{}
+	mi := &file_proto_checkout_checkout_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutResponse) ProtoMessage() {}
+
+func (x *CheckoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_checkout_checkout_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutResponse.ProtoReflect.Descriptor instead.
+func (*CheckoutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_checkout_checkout_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CheckoutResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetStatus() CheckoutStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CheckoutStatus_CHECKOUT_STATUS_UNSPECIFIED
+}
+
+func (x *CheckoutResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetFailure() CheckoutFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return CheckoutFailure_CHECKOUT_FAILURE_UNSPECIFIED
+}
+
+func (x *CheckoutResponse) GetFailureMessage() string {
+	if x != nil {
+		return x.FailureMessage
+	}
+	return ""
+}
+
+func (x *CheckoutResponse) GetCreatedAt() *common.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CheckoutResponse) GetUpdatedAt() *common.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_proto_checkout_checkout_proto protoreflect.FileDescriptor
+
+const file_proto_checkout_checkout_proto_rawDesc = "" +
+	"\n" +
+	"\x1dproto/checkout/checkout.proto\x12\bcheckout\x1a\x19proto/common/common.proto\x1a\x1bproto/payment/payment.proto\"I\n" +
+	"\fCheckoutItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\xf8\x01\n" +
+	"\x0fCheckoutRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12,\n" +
+	"\x05items\x18\x02 \x03(\v2\x16.checkout.CheckoutItemR\x05items\x12:\n" +
+	"\x10shipping_address\x18\x03 \x01(\v2\x0f.common.AddressR\x0fshippingAddress\x12=\n" +
+	"\x0epayment_method\x18\x04 \x01(\x0e2\x16.payment.PaymentMethodR\rpaymentMethod\x12#\n" +
+	"\rpayment_token\x18\x05 \x01(\tR\fpaymentToken\"$\n" +
+	"\x12GetCheckoutRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x90\x03\n" +
+	"\x10CheckoutResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x120\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x18.checkout.CheckoutStatusR\x06status\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x05 \x01(\tR\tpaymentId\x12%\n" +
+	"\x0etransaction_id\x18\x06 \x01(\tR\rtransactionId\x123\n" +
+	"\afailure\x18\a \x01(\x0e2\x19.checkout.CheckoutFailureR\afailure\x12'\n" +
+	"\x0ffailure_message\x18\b \x01(\tR\x0efailureMessage\x120\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x11.common.TimestampR\tupdatedAt*\x8d\x01\n" +
+	"\x0eCheckoutStatus\x12\x1f\n" +
+	"\x1bCHECKOUT_STATUS_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bCHECKOUT_STATUS_IN_PROGRESS\x10\x01\x12\x1d\n" +
+	"\x19CHECKOUT_STATUS_COMPLETED\x10\x02\x12\x1a\n" +
+	"\x16CHECKOUT_STATUS_FAILED\x10\x03*\xc6\x01\n" +
+	"\x0fCheckoutFailure\x12 \n" +
+	"\x1cCHECKOUT_FAILURE_UNSPECIFIED\x10\x00\x12(\n" +
+	"$CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE\x10\x01\x12!\n" +
+	"\x1dCHECKOUT_FAILURE_OUT_OF_STOCK\x10\x02\x12%\n" +
+	"!CHECKOUT_FAILURE_PAYMENT_DECLINED\x10\x03\x12\x1d\n" +
+	"\x19CHECKOUT_FAILURE_INTERNAL\x10\x042\x9d\x01\n" +
+	"\x0fCheckoutService\x12A\n" +
+	"\bCheckout\x12\x19.checkout.CheckoutRequest\x1a\x1a.checkout.CheckoutResponse\x12G\n" +
+	"\vGetCheckout\x12\x1c.checkout.GetCheckoutRequest\x1a\x1a.checkout.CheckoutResponseB-Z+github.com/Riku-KANO/kube-ec/proto/checkoutb\x06proto3"
+
+var (
+	file_proto_checkout_checkout_proto_rawDescOnce sync.Once
+	file_proto_checkout_checkout_proto_rawDescData []byte
+)
+
+func file_proto_checkout_checkout_proto_rawDescGZIP() []byte {
+	file_proto_checkout_checkout_proto_rawDescOnce.Do(func() {
+		file_proto_checkout_checkout_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_checkout_checkout_proto_rawDesc), len(file_proto_checkout_checkout_proto_rawDesc)))
+	})
+	return file_proto_checkout_checkout_proto_rawDescData
+}
+
+var file_proto_checkout_checkout_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_checkout_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_checkout_checkout_proto_goTypes = []any{
+	(CheckoutStatus)(0),        // 0: checkout.CheckoutStatus
+	(CheckoutFailure)(0),       // 1: checkout.CheckoutFailure
+	(*CheckoutItem)(nil),       // 2: checkout.CheckoutItem
+	(*CheckoutRequest)(nil),    // 3: checkout.CheckoutRequest
+	(*GetCheckoutRequest)(nil), // 4: checkout.GetCheckoutRequest
+	(*CheckoutResponse)(nil),   // 5: checkout.CheckoutResponse
+	(*common.Address)(nil),     // 6: common.Address
+	(payment.PaymentMethod)(0), // 7: payment.PaymentMethod
+	(*common.Timestamp)(nil),   // 8: common.Timestamp
+}
+var file_proto_checkout_checkout_proto_depIdxs = []int32{
+	2, // 0: checkout.CheckoutRequest.items:type_name -> checkout.CheckoutItem
+	6, // 1: checkout.CheckoutRequest.shipping_address:type_name -> common.Address
+	7, // 2: checkout.CheckoutRequest.payment_method:type_name -> payment.PaymentMethod
+	0, // 3: checkout.CheckoutResponse.status:type_name -> checkout.CheckoutStatus
+	1, // 4: checkout.CheckoutResponse.failure:type_name -> checkout.CheckoutFailure
+	8, // 5: checkout.CheckoutResponse.created_at:type_name -> common.Timestamp
+	8, // 6: checkout.CheckoutResponse.updated_at:type_name -> common.Timestamp
+	3, // 7: checkout.CheckoutService.Checkout:input_type -> checkout.CheckoutRequest
+	4, // 8: checkout.CheckoutService.GetCheckout:input_type -> checkout.GetCheckoutRequest
+	5, // 9: checkout.CheckoutService.Checkout:output_type -> checkout.CheckoutResponse
+	5, // 10: checkout.CheckoutService.GetCheckout:output_type -> checkout.CheckoutResponse
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_checkout_checkout_proto_init() }
+func file_proto_checkout_checkout_proto_init() {
+	if File_proto_checkout_checkout_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_checkout_checkout_proto_rawDesc), len(file_proto_checkout_checkout_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_checkout_checkout_proto_goTypes,
+		DependencyIndexes: file_proto_checkout_checkout_proto_depIdxs,
+		EnumInfos:         file_proto_checkout_checkout_proto_enumTypes,
+		MessageInfos:      file_proto_checkout_checkout_proto_msgTypes,
+	}.Build()
+	File_proto_checkout_checkout_proto = out.File
+	file_proto_checkout_checkout_proto_goTypes = nil
+	file_proto_checkout_checkout_proto_depIdxs = nil
+}
@@ -0,0 +1,173 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.0
+// source: proto/checkout/checkout.proto
+
+package checkout
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CheckoutService_Checkout_FullMethodName    = "/checkout.CheckoutService/Checkout"
+	CheckoutService_GetCheckout_FullMethodName = "/checkout.CheckoutService/GetCheckout"
+)
+
+// CheckoutServiceClient is the client API for CheckoutService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CheckoutService places an order and pays for it as one saga across the
+// order, product and payment services. Failed steps are compensated by
+// releasing stock, cancelling the order and refunding the payment.
+type CheckoutServiceClient interface {
+	// Checkout runs a checkout for the caller and returns once it completed or
+	// was compensated. It requires a customer access token.
+	Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error)
+	// GetCheckout returns the state of a checkout of the caller
+	GetCheckout(ctx context.Context, in *GetCheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error)
+}
+
+type checkoutServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCheckoutServiceClient(cc grpc.ClientConnInterface) CheckoutServiceClient {
+	return &checkoutServiceClient{cc}
+}
+
+func (c *checkoutServiceClient) Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckoutResponse)
+	err := c.cc.Invoke(ctx, CheckoutService_Checkout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutServiceClient) GetCheckout(ctx context.Context, in *GetCheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckoutResponse)
+	err := c.cc.Invoke(ctx, CheckoutService_GetCheckout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CheckoutServiceServer is the server API for CheckoutService service.
+// All implementations must embed UnimplementedCheckoutServiceServer
+// for forward compatibility.
+//
+// CheckoutService places an order and pays for it as one saga across the
+// order, product and payment services. Failed steps are compensated by
+// releasing stock, cancelling the order and refunding the payment.
+type CheckoutServiceServer interface {
+	// Checkout runs a checkout for the caller and returns once it completed or
+	// was compensated. It requires a customer access token.
+	Checkout(context.Context, *CheckoutRequest) (*CheckoutResponse, error)
+	// GetCheckout returns the state of a checkout of the caller
+	GetCheckout(context.Context, *GetCheckoutRequest) (*CheckoutResponse, error)
+	mustEmbedUnimplementedCheckoutServiceServer()
+}
+
+// UnimplementedCheckoutServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCheckoutServiceServer struct{}
+
+func (UnimplementedCheckoutServiceServer) Checkout(context.Context, *CheckoutRequest) (*CheckoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkout not implemented")
+}
+func (UnimplementedCheckoutServiceServer) GetCheckout(context.Context, *GetCheckoutRequest) (*CheckoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCheckout not implemented")
+}
+func (UnimplementedCheckoutServiceServer) mustEmbedUnimplementedCheckoutServiceServer() {}
+func (UnimplementedCheckoutServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeCheckoutServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CheckoutServiceServer will
+// result in compilation errors.
+type UnsafeCheckoutServiceServer interface {
+	mustEmbedUnimplementedCheckoutServiceServer()
+}
+
+func RegisterCheckoutServiceServer(s grpc.ServiceRegistrar, srv CheckoutServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCheckoutServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CheckoutService_ServiceDesc, srv)
+}
+
+func _CheckoutService_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServiceServer).Checkout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CheckoutService_Checkout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServiceServer).Checkout(ctx, req.(*CheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CheckoutService_GetCheckout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServiceServer).GetCheckout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CheckoutService_GetCheckout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServiceServer).GetCheckout(ctx, req.(*GetCheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CheckoutService_ServiceDesc is the grpc.ServiceDesc for CheckoutService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CheckoutService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "checkout.CheckoutService",
+	HandlerType: (*CheckoutServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Checkout",
+			Handler:    _CheckoutService_Checkout_Handler,
+		},
+		{
+			MethodName: "GetCheckout",
+			Handler:    _CheckoutService_GetCheckout_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/checkout/checkout.proto",
+}
@@ -170,6 +170,7 @@ type Order struct {
 	PaymentId       string                 `protobuf:"bytes,7,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
 	CreatedAt       *common.Timestamp      `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       *common.Timestamp      `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	BillingAddress  *common.Address        `protobuf:"bytes,10,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"` // 未指定の場合は配送先と同じ
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -267,13 +268,23 @@ func (x *Order) GetUpdatedAt() *common.Timestamp {
 	return nil
 }
 
+func (x *Order) GetBillingAddress() *common.Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
 type CreateOrderRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Items           []*OrderItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
 	ShippingAddress *common.Address        `protobuf:"bytes,3,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	BillingAddress  *common.Address        `protobuf:"bytes,4,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"` // 未指定の場合は配送先と同じ
+	// 同じユーザーが同じキーで再送した場合は作成済みの注文を返す（チェックアウトのサガIDなど）
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
@@ -327,6 +338,20 @@ func (x *CreateOrderRequest) GetShippingAddress() *common.Address {
 	return nil
 }
 
+func (x *CreateOrderRequest) GetBillingAddress() *common.Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -371,6 +396,58 @@ func (x *GetOrderRequest) GetId() string {
 	return ""
 }
 
+type GetOrderByIdempotencyKeyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetOrderByIdempotencyKeyRequest) Reset() {
+	*x = GetOrderByIdempotencyKeyRequest{}
+	mi := &file_proto_order_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderByIdempotencyKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderByIdempotencyKeyRequest) ProtoMessage() {}
+
+func (x *GetOrderByIdempotencyKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderByIdempotencyKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderByIdempotencyKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetOrderByIdempotencyKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrderByIdempotencyKeyRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type ListOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -382,7 +459,7 @@ type ListOrdersRequest struct {
 
 func (x *ListOrdersRequest) Reset() {
 	*x = ListOrdersRequest{}
-	mi := &file_proto_order_order_proto_msgTypes[4]
+	mi := &file_proto_order_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -394,7 +471,7 @@ func (x *ListOrdersRequest) String() string {
 func (*ListOrdersRequest) ProtoMessage() {}
 
 func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_order_proto_msgTypes[4]
+	mi := &file_proto_order_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,7 +484,7 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
 func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_order_proto_rawDescGZIP(), []int{4}
+	return file_proto_order_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListOrdersRequest) GetUserId() string {
@@ -441,7 +518,7 @@ type ListOrdersResponse struct {
 
 func (x *ListOrdersResponse) Reset() {
 	*x = ListOrdersResponse{}
-	mi := &file_proto_order_order_proto_msgTypes[5]
+	mi := &file_proto_order_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -453,7 +530,7 @@ func (x *ListOrdersResponse) String() string {
 func (*ListOrdersResponse) ProtoMessage() {}
 
 func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_order_proto_msgTypes[5]
+	mi := &file_proto_order_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +543,7 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_order_order_proto_rawDescGZIP(), []int{5}
+	return file_proto_order_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListOrdersResponse) GetOrders() []*Order {
@@ -487,13 +564,15 @@ type UpdateOrderStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"` // 決済完了で注文を確定する場合の決済ID
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                        // 履歴に記録する変更理由
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateOrderStatusRequest) Reset() {
 	*x = UpdateOrderStatusRequest{}
-	mi := &file_proto_order_order_proto_msgTypes[6]
+	mi := &file_proto_order_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -505,7 +584,7 @@ func (x *UpdateOrderStatusRequest) String() string {
 func (*UpdateOrderStatusRequest) ProtoMessage() {}
 
 func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_order_proto_msgTypes[6]
+	mi := &file_proto_order_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -518,7 +597,7 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_order_proto_rawDescGZIP(), []int{6}
+	return file_proto_order_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateOrderStatusRequest) GetId() string {
@@ -535,6 +614,20 @@ func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
 	return OrderStatus_ORDER_STATUS_UNSPECIFIED
 }
 
+func (x *UpdateOrderStatusRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type CancelOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -545,7 +638,7 @@ type CancelOrderRequest struct {
 
 func (x *CancelOrderRequest) Reset() {
 	*x = CancelOrderRequest{}
-	mi := &file_proto_order_order_proto_msgTypes[7]
+	mi := &file_proto_order_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -557,7 +650,7 @@ func (x *CancelOrderRequest) String() string {
 func (*CancelOrderRequest) ProtoMessage() {}
 
 func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_order_order_proto_msgTypes[7]
+	mi := &file_proto_order_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -570,7 +663,7 @@ func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
 func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
-	return file_proto_order_order_proto_rawDescGZIP(), []int{7}
+	return file_proto_order_order_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CancelOrderRequest) GetId() string {
@@ -587,6 +680,242 @@ func (x *CancelOrderRequest) GetReason() string {
 	return ""
 }
 
+// OrderStatusChange is an entry of the status history of an order.
+// The first entry records the creation of the order with no from_status.
+type OrderStatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromStatus    OrderStatus            `protobuf:"varint,1,opt,name=from_status,json=fromStatus,proto3,enum=order.OrderStatus" json:"from_status,omitempty"`
+	ToStatus      OrderStatus            `protobuf:"varint,2,opt,name=to_status,json=toStatus,proto3,enum=order.OrderStatus" json:"to_status,omitempty"`
+	ActorType     string                 `protobuf:"bytes,3,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"` // user or service
+	ActorId       string                 `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`       // ユーザーID、またはサービスのクライアントID
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     *common.Timestamp      `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_proto_order_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChange) ProtoMessage() {}
+
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_proto_order_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderStatusChange) GetFromStatus() OrderStatus {
+	if x != nil {
+		return x.FromStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusChange) GetToStatus() OrderStatus {
+	if x != nil {
+		return x.ToStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderStatusChange) GetActorType() string {
+	if x != nil {
+		return x.ActorType
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetChangedAt() *common.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type GetOrderHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderHistoryRequest) Reset() {
+	*x = GetOrderHistoryRequest{}
+	mi := &file_proto_order_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderHistoryRequest) ProtoMessage() {}
+
+func (x *GetOrderHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderHistoryRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*OrderStatusChange   `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"` // 古い順
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderHistoryResponse) Reset() {
+	*x = GetOrderHistoryResponse{}
+	mi := &file_proto_order_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderHistoryResponse) ProtoMessage() {}
+
+func (x *GetOrderHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_order_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOrderHistoryResponse) GetChanges() []*OrderStatusChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// UpdateOrderAddressRequest replaces the addresses of an order.
+// Addresses can be changed only while the order is PENDING or CONFIRMED.
+type UpdateOrderAddressRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShippingAddress *common.Address        `protobuf:"bytes,2,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	BillingAddress  *common.Address        `protobuf:"bytes,3,opt,name=billing_address,json=billingAddress,proto3" json:"billing_address,omitempty"` // 未指定の場合は配送先と同じ
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateOrderAddressRequest) Reset() {
+	*x = UpdateOrderAddressRequest{}
+	mi := &file_proto_order_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderAddressRequest) ProtoMessage() {}
+
+func (x *UpdateOrderAddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_order_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderAddressRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderAddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_order_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateOrderAddressRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateOrderAddressRequest) GetShippingAddress() *common.Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *UpdateOrderAddressRequest) GetBillingAddress() *common.Address {
+	if x != nil {
+		return x.BillingAddress
+	}
+	return nil
+}
+
 var File_proto_order_order_proto protoreflect.FileDescriptor
 
 const file_proto_order_order_proto_rawDesc = "" +
@@ -599,7 +928,7 @@ const file_proto_order_order_proto_rawDesc = "" +
 	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12,\n" +
 	"\n" +
 	"unit_price\x18\x04 \x01(\v2\r.common.MoneyR\tunitPrice\x12)\n" +
-	"\bsubtotal\x18\x05 \x01(\v2\r.common.MoneyR\bsubtotal\"\xf5\x02\n" +
+	"\bsubtotal\x18\x05 \x01(\v2\r.common.MoneyR\bsubtotal\"\xaf\x03\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
@@ -612,13 +941,20 @@ const file_proto_order_order_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\b \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
 	"\n" +
-	"updated_at\x18\t \x01(\v2\x11.common.TimestampR\tupdatedAt\"\x91\x01\n" +
+	"updated_at\x18\t \x01(\v2\x11.common.TimestampR\tupdatedAt\x128\n" +
+	"\x0fbilling_address\x18\n" +
+	" \x01(\v2\x0f.common.AddressR\x0ebillingAddress\"\xf4\x01\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12&\n" +
 	"\x05items\x18\x02 \x03(\v2\x10.order.OrderItemR\x05items\x12:\n" +
-	"\x10shipping_address\x18\x03 \x01(\v2\x0f.common.AddressR\x0fshippingAddress\"!\n" +
+	"\x10shipping_address\x18\x03 \x01(\v2\x0f.common.AddressR\x0fshippingAddress\x128\n" +
+	"\x0fbilling_address\x18\x04 \x01(\v2\x0f.common.AddressR\x0ebillingAddress\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\"!\n" +
 	"\x0fGetOrderRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x8c\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"c\n" +
+	"\x1fGetOrderByIdempotencyKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"\x8c\x01\n" +
 	"\x11ListOrdersRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x122\n" +
 	"\n" +
@@ -629,13 +965,34 @@ const file_proto_order_order_proto_rawDesc = "" +
 	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12:\n" +
 	"\n" +
 	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
-	"pagination\"V\n" +
+	"pagination\"\x8d\x01\n" +
 	"\x18UpdateOrderStatusRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\"<\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"<\n" +
 	"\x12CancelOrderRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
-	"\x06reason\x18\x02 \x01(\tR\x06reason*\xd0\x01\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xfd\x01\n" +
+	"\x11OrderStatusChange\x123\n" +
+	"\vfrom_status\x18\x01 \x01(\x0e2\x12.order.OrderStatusR\n" +
+	"fromStatus\x12/\n" +
+	"\tto_status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\btoStatus\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\x03 \x01(\tR\tactorType\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x120\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\v2\x11.common.TimestampR\tchangedAt\"3\n" +
+	"\x16GetOrderHistoryRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"M\n" +
+	"\x17GetOrderHistoryResponse\x122\n" +
+	"\achanges\x18\x01 \x03(\v2\x18.order.OrderStatusChangeR\achanges\"\xa1\x01\n" +
+	"\x19UpdateOrderAddressRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\x10shipping_address\x18\x02 \x01(\v2\x0f.common.AddressR\x0fshippingAddress\x128\n" +
+	"\x0fbilling_address\x18\x03 \x01(\v2\x0f.common.AddressR\x0ebillingAddress*\xd0\x01\n" +
 	"\vOrderStatus\x12\x1c\n" +
 	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14ORDER_STATUS_PENDING\x10\x01\x12\x1a\n" +
@@ -643,14 +1000,17 @@ const file_proto_order_order_proto_rawDesc = "" +
 	"\x17ORDER_STATUS_PROCESSING\x10\x03\x12\x18\n" +
 	"\x14ORDER_STATUS_SHIPPED\x10\x04\x12\x1a\n" +
 	"\x16ORDER_STATUS_DELIVERED\x10\x05\x12\x1a\n" +
-	"\x16ORDER_STATUS_CANCELLED\x10\x062\xb7\x02\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x062\xa1\x04\n" +
 	"\fOrderService\x126\n" +
 	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\f.order.Order\x120\n" +
-	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\f.order.Order\x12A\n" +
+	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\f.order.Order\x12P\n" +
+	"\x18GetOrderByIdempotencyKey\x12&.order.GetOrderByIdempotencyKeyRequest\x1a\f.order.Order\x12A\n" +
 	"\n" +
 	"ListOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponse\x12B\n" +
 	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\f.order.Order\x126\n" +
-	"\vCancelOrder\x12\x19.order.CancelOrderRequest\x1a\f.order.OrderB*Z(github.com/Riku-KANO/kube-ec/proto/orderb\x06proto3"
+	"\vCancelOrder\x12\x19.order.CancelOrderRequest\x1a\f.order.Order\x12P\n" +
+	"\x0fGetOrderHistory\x12\x1d.order.GetOrderHistoryRequest\x1a\x1e.order.GetOrderHistoryResponse\x12D\n" +
+	"\x12UpdateOrderAddress\x12 .order.UpdateOrderAddressRequest\x1a\f.order.OrderB*Z(github.com/Riku-KANO/kube-ec/proto/orderb\x06proto3"
 
 var (
 	file_proto_order_order_proto_rawDescOnce sync.Once
@@ -665,54 +1025,73 @@ func file_proto_order_order_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_order_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_order_order_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_order_order_proto_goTypes = []any{
-	(OrderStatus)(0),                  // 0: order.OrderStatus
-	(*OrderItem)(nil),                 // 1: order.OrderItem
-	(*Order)(nil),                     // 2: order.Order
-	(*CreateOrderRequest)(nil),        // 3: order.CreateOrderRequest
-	(*GetOrderRequest)(nil),           // 4: order.GetOrderRequest
-	(*ListOrdersRequest)(nil),         // 5: order.ListOrdersRequest
-	(*ListOrdersResponse)(nil),        // 6: order.ListOrdersResponse
-	(*UpdateOrderStatusRequest)(nil),  // 7: order.UpdateOrderStatusRequest
-	(*CancelOrderRequest)(nil),        // 8: order.CancelOrderRequest
-	(*common.Money)(nil),              // 9: common.Money
-	(*common.Address)(nil),            // 10: common.Address
-	(*common.Timestamp)(nil),          // 11: common.Timestamp
-	(*common.Pagination)(nil),         // 12: common.Pagination
-	(*common.PaginationResponse)(nil), // 13: common.PaginationResponse
+	(OrderStatus)(0),                        // 0: order.OrderStatus
+	(*OrderItem)(nil),                       // 1: order.OrderItem
+	(*Order)(nil),                           // 2: order.Order
+	(*CreateOrderRequest)(nil),              // 3: order.CreateOrderRequest
+	(*GetOrderRequest)(nil),                 // 4: order.GetOrderRequest
+	(*GetOrderByIdempotencyKeyRequest)(nil), // 5: order.GetOrderByIdempotencyKeyRequest
+	(*ListOrdersRequest)(nil),               // 6: order.ListOrdersRequest
+	(*ListOrdersResponse)(nil),              // 7: order.ListOrdersResponse
+	(*UpdateOrderStatusRequest)(nil),        // 8: order.UpdateOrderStatusRequest
+	(*CancelOrderRequest)(nil),              // 9: order.CancelOrderRequest
+	(*OrderStatusChange)(nil),               // 10: order.OrderStatusChange
+	(*GetOrderHistoryRequest)(nil),          // 11: order.GetOrderHistoryRequest
+	(*GetOrderHistoryResponse)(nil),         // 12: order.GetOrderHistoryResponse
+	(*UpdateOrderAddressRequest)(nil),       // 13: order.UpdateOrderAddressRequest
+	(*common.Money)(nil),                    // 14: common.Money
+	(*common.Address)(nil),                  // 15: common.Address
+	(*common.Timestamp)(nil),                // 16: common.Timestamp
+	(*common.Pagination)(nil),               // 17: common.Pagination
+	(*common.PaginationResponse)(nil),       // 18: common.PaginationResponse
 }
 var file_proto_order_order_proto_depIdxs = []int32{
-	9,  // 0: order.OrderItem.unit_price:type_name -> common.Money
-	9,  // 1: order.OrderItem.subtotal:type_name -> common.Money
+	14, // 0: order.OrderItem.unit_price:type_name -> common.Money
+	14, // 1: order.OrderItem.subtotal:type_name -> common.Money
 	1,  // 2: order.Order.items:type_name -> order.OrderItem
-	9,  // 3: order.Order.total_amount:type_name -> common.Money
+	14, // 3: order.Order.total_amount:type_name -> common.Money
 	0,  // 4: order.Order.status:type_name -> order.OrderStatus
-	10, // 5: order.Order.shipping_address:type_name -> common.Address
-	11, // 6: order.Order.created_at:type_name -> common.Timestamp
-	11, // 7: order.Order.updated_at:type_name -> common.Timestamp
-	1,  // 8: order.CreateOrderRequest.items:type_name -> order.OrderItem
-	10, // 9: order.CreateOrderRequest.shipping_address:type_name -> common.Address
-	12, // 10: order.ListOrdersRequest.pagination:type_name -> common.Pagination
-	0,  // 11: order.ListOrdersRequest.status:type_name -> order.OrderStatus
-	2,  // 12: order.ListOrdersResponse.orders:type_name -> order.Order
-	13, // 13: order.ListOrdersResponse.pagination:type_name -> common.PaginationResponse
-	0,  // 14: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
-	3,  // 15: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	4,  // 16: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
-	5,  // 17: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
-	7,  // 18: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
-	8,  // 19: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
-	2,  // 20: order.OrderService.CreateOrder:output_type -> order.Order
-	2,  // 21: order.OrderService.GetOrder:output_type -> order.Order
-	6,  // 22: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
-	2,  // 23: order.OrderService.UpdateOrderStatus:output_type -> order.Order
-	2,  // 24: order.OrderService.CancelOrder:output_type -> order.Order
-	20, // [20:25] is the sub-list for method output_type
-	15, // [15:20] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	15, // 5: order.Order.shipping_address:type_name -> common.Address
+	16, // 6: order.Order.created_at:type_name -> common.Timestamp
+	16, // 7: order.Order.updated_at:type_name -> common.Timestamp
+	15, // 8: order.Order.billing_address:type_name -> common.Address
+	1,  // 9: order.CreateOrderRequest.items:type_name -> order.OrderItem
+	15, // 10: order.CreateOrderRequest.shipping_address:type_name -> common.Address
+	15, // 11: order.CreateOrderRequest.billing_address:type_name -> common.Address
+	17, // 12: order.ListOrdersRequest.pagination:type_name -> common.Pagination
+	0,  // 13: order.ListOrdersRequest.status:type_name -> order.OrderStatus
+	2,  // 14: order.ListOrdersResponse.orders:type_name -> order.Order
+	18, // 15: order.ListOrdersResponse.pagination:type_name -> common.PaginationResponse
+	0,  // 16: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
+	0,  // 17: order.OrderStatusChange.from_status:type_name -> order.OrderStatus
+	0,  // 18: order.OrderStatusChange.to_status:type_name -> order.OrderStatus
+	16, // 19: order.OrderStatusChange.changed_at:type_name -> common.Timestamp
+	10, // 20: order.GetOrderHistoryResponse.changes:type_name -> order.OrderStatusChange
+	15, // 21: order.UpdateOrderAddressRequest.shipping_address:type_name -> common.Address
+	15, // 22: order.UpdateOrderAddressRequest.billing_address:type_name -> common.Address
+	3,  // 23: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	4,  // 24: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	5,  // 25: order.OrderService.GetOrderByIdempotencyKey:input_type -> order.GetOrderByIdempotencyKeyRequest
+	6,  // 26: order.OrderService.ListOrders:input_type -> order.ListOrdersRequest
+	8,  // 27: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	9,  // 28: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
+	11, // 29: order.OrderService.GetOrderHistory:input_type -> order.GetOrderHistoryRequest
+	13, // 30: order.OrderService.UpdateOrderAddress:input_type -> order.UpdateOrderAddressRequest
+	2,  // 31: order.OrderService.CreateOrder:output_type -> order.Order
+	2,  // 32: order.OrderService.GetOrder:output_type -> order.Order
+	2,  // 33: order.OrderService.GetOrderByIdempotencyKey:output_type -> order.Order
+	7,  // 34: order.OrderService.ListOrders:output_type -> order.ListOrdersResponse
+	2,  // 35: order.OrderService.UpdateOrderStatus:output_type -> order.Order
+	2,  // 36: order.OrderService.CancelOrder:output_type -> order.Order
+	12, // 37: order.OrderService.GetOrderHistory:output_type -> order.GetOrderHistoryResponse
+	2,  // 38: order.OrderService.UpdateOrderAddress:output_type -> order.Order
+	31, // [31:39] is the sub-list for method output_type
+	23, // [23:31] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_proto_order_order_proto_init() }
@@ -726,7 +1105,7 @@ func file_proto_order_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_order_order_proto_rawDesc), len(file_proto_order_order_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,116 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ReservationStatus int32
+
+const (
+	ReservationStatus_RESERVATION_STATUS_UNSPECIFIED ReservationStatus = 0
+	ReservationStatus_RESERVATION_STATUS_HELD        ReservationStatus = 1 // 確保中
+	ReservationStatus_RESERVATION_STATUS_COMMITTED   ReservationStatus = 2 // 確定済み。在庫数から差し引かれた
+	ReservationStatus_RESERVATION_STATUS_RELEASED    ReservationStatus = 3 // 解放済み
+	ReservationStatus_RESERVATION_STATUS_EXPIRED     ReservationStatus = 4 // 期限切れで解放済み
+)
+
+// Enum value maps for ReservationStatus.
+var (
+	ReservationStatus_name = map[int32]string{
+		0: "RESERVATION_STATUS_UNSPECIFIED",
+		1: "RESERVATION_STATUS_HELD",
+		2: "RESERVATION_STATUS_COMMITTED",
+		3: "RESERVATION_STATUS_RELEASED",
+		4: "RESERVATION_STATUS_EXPIRED",
+	}
+	ReservationStatus_value = map[string]int32{
+		"RESERVATION_STATUS_UNSPECIFIED": 0,
+		"RESERVATION_STATUS_HELD":        1,
+		"RESERVATION_STATUS_COMMITTED":   2,
+		"RESERVATION_STATUS_RELEASED":    3,
+		"RESERVATION_STATUS_EXPIRED":     4,
+	}
+)
+
+func (x ReservationStatus) Enum() *ReservationStatus {
+	p := new(ReservationStatus)
+	*p = x
+	return p
+}
+
+func (x ReservationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReservationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_product_product_proto_enumTypes[0].Descriptor()
+}
+
+func (ReservationStatus) Type() protoreflect.EnumType {
+	return &file_proto_product_product_proto_enumTypes[0]
+}
+
+func (x ReservationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReservationStatus.Descriptor instead.
+func (ReservationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{0}
+}
+
+type InventoryMovementReason int32
+
+const (
+	InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED InventoryMovementReason = 0
+	InventoryMovementReason_INVENTORY_MOVEMENT_REASON_SALE        InventoryMovementReason = 1 // 販売。在庫確保の確定で記録される
+	InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK     InventoryMovementReason = 2 // 入荷
+	InventoryMovementReason_INVENTORY_MOVEMENT_REASON_ADJUSTMENT  InventoryMovementReason = 3 // 棚卸しなどによる調整
+	InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RETURN      InventoryMovementReason = 4 // 返品・販売の取り消し
+)
+
+// Enum value maps for InventoryMovementReason.
+var (
+	InventoryMovementReason_name = map[int32]string{
+		0: "INVENTORY_MOVEMENT_REASON_UNSPECIFIED",
+		1: "INVENTORY_MOVEMENT_REASON_SALE",
+		2: "INVENTORY_MOVEMENT_REASON_RESTOCK",
+		3: "INVENTORY_MOVEMENT_REASON_ADJUSTMENT",
+		4: "INVENTORY_MOVEMENT_REASON_RETURN",
+	}
+	InventoryMovementReason_value = map[string]int32{
+		"INVENTORY_MOVEMENT_REASON_UNSPECIFIED": 0,
+		"INVENTORY_MOVEMENT_REASON_SALE":        1,
+		"INVENTORY_MOVEMENT_REASON_RESTOCK":     2,
+		"INVENTORY_MOVEMENT_REASON_ADJUSTMENT":  3,
+		"INVENTORY_MOVEMENT_REASON_RETURN":      4,
+	}
+)
+
+func (x InventoryMovementReason) Enum() *InventoryMovementReason {
+	p := new(InventoryMovementReason)
+	*p = x
+	return p
+}
+
+func (x InventoryMovementReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InventoryMovementReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_product_product_proto_enumTypes[1].Descriptor()
+}
+
+func (InventoryMovementReason) Type() protoreflect.EnumType {
+	return &file_proto_product_product_proto_enumTypes[1]
+}
+
+func (x InventoryMovementReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InventoryMovementReason.Descriptor instead.
+func (InventoryMovementReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{1}
+}
+
 type Product struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -30,11 +140,12 @@ type Product struct {
 	Price         *common.Money          `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
 	StockQuantity int32                  `protobuf:"varint,5,opt,name=stock_quantity,json=stockQuantity,proto3" json:"stock_quantity,omitempty"`
 	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
-	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"` // images の URL を表示順に並べたもの
 	Sku           string                 `protobuf:"bytes,8,opt,name=sku,proto3" json:"sku,omitempty"`
 	IsActive      bool                   `protobuf:"varint,9,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
 	CreatedAt     *common.Timestamp      `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *common.Timestamp      `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Images        []*ProductImage        `protobuf:"bytes,12,rep,name=images,proto3" json:"images,omitempty"` // 表示順
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -146,6 +257,81 @@ func (x *Product) GetUpdatedAt() *common.Timestamp {
 	return nil
 }
 
+func (x *Product) GetImages() []*ProductImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type ProductImage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	AltText       string                 `protobuf:"bytes,3,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`
+	Position      int32                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"` // 0 から始まる表示順
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductImage) Reset() {
+	*x = ProductImage{}
+	mi := &file_proto_product_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductImage) ProtoMessage() {}
+
+func (x *ProductImage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductImage.ProtoReflect.Descriptor instead.
+func (*ProductImage) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductImage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductImage) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ProductImage) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+func (x *ProductImage) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
 type CreateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -154,14 +340,14 @@ type CreateProductRequest struct {
 	StockQuantity int32                  `protobuf:"varint,4,opt,name=stock_quantity,json=stockQuantity,proto3" json:"stock_quantity,omitempty"`
 	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
 	ImageUrls     []string               `protobuf:"bytes,6,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
-	Sku           string                 `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"`
+	Sku           string                 `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"` // 空でなければ商品間で一意
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateProductRequest) Reset() {
 	*x = CreateProductRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[1]
+	mi := &file_proto_product_product_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -173,7 +359,7 @@ func (x *CreateProductRequest) String() string {
 func (*CreateProductRequest) ProtoMessage() {}
 
 func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[1]
+	mi := &file_proto_product_product_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -186,7 +372,7 @@ func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
 func (*CreateProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{1}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateProductRequest) GetName() string {
@@ -247,7 +433,7 @@ type GetProductRequest struct {
 
 func (x *GetProductRequest) Reset() {
 	*x = GetProductRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[2]
+	mi := &file_proto_product_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -259,7 +445,7 @@ func (x *GetProductRequest) String() string {
 func (*GetProductRequest) ProtoMessage() {}
 
 func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[2]
+	mi := &file_proto_product_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -272,7 +458,7 @@ func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
 func (*GetProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{2}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetProductRequest) GetId() string {
@@ -282,18 +468,158 @@ func (x *GetProductRequest) GetId() string {
 	return ""
 }
 
+type GetProductBySkuRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductBySkuRequest) Reset() {
+	*x = GetProductBySkuRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductBySkuRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductBySkuRequest) ProtoMessage() {}
+
+func (x *GetProductBySkuRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductBySkuRequest.ProtoReflect.Descriptor instead.
+func (*GetProductBySkuRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetProductBySkuRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+type BatchGetProductsBySkuRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skus          []string               `protobuf:"bytes,1,rep,name=skus,proto3" json:"skus,omitempty"` // 最大100件
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetProductsBySkuRequest) Reset() {
+	*x = BatchGetProductsBySkuRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductsBySkuRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductsBySkuRequest) ProtoMessage() {}
+
+func (x *BatchGetProductsBySkuRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductsBySkuRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetProductsBySkuRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchGetProductsBySkuRequest) GetSkus() []string {
+	if x != nil {
+		return x.Skus
+	}
+	return nil
+}
+
+type BatchGetProductsBySkuResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"` // skus の順。存在しない SKU は含まない
+	MissingSkus   []string               `protobuf:"bytes,2,rep,name=missing_skus,json=missingSkus,proto3" json:"missing_skus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetProductsBySkuResponse) Reset() {
+	*x = BatchGetProductsBySkuResponse{}
+	mi := &file_proto_product_product_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetProductsBySkuResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetProductsBySkuResponse) ProtoMessage() {}
+
+func (x *BatchGetProductsBySkuResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetProductsBySkuResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetProductsBySkuResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchGetProductsBySkuResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *BatchGetProductsBySkuResponse) GetMissingSkus() []string {
+	if x != nil {
+		return x.MissingSkus
+	}
+	return nil
+}
+
 type ListProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pagination    *common.Pagination     `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
-	SearchQuery   string                 `protobuf:"bytes,3,opt,name=search_query,json=searchQuery,proto3" json:"search_query,omitempty"`
+	SearchQuery   string                 `protobuf:"bytes,3,opt,name=search_query,json=searchQuery,proto3" json:"search_query,omitempty"` // 商品名・SKU・カテゴリ・説明を全文検索し、関連度順に並べる
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[3]
+	mi := &file_proto_product_product_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -305,7 +631,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[3]
+	mi := &file_proto_product_product_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -318,7 +644,7 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{3}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListProductsRequest) GetPagination() *common.Pagination {
@@ -352,7 +678,7 @@ type ListProductsResponse struct {
 
 func (x *ListProductsResponse) Reset() {
 	*x = ListProductsResponse{}
-	mi := &file_proto_product_product_proto_msgTypes[4]
+	mi := &file_proto_product_product_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -364,7 +690,7 @@ func (x *ListProductsResponse) String() string {
 func (*ListProductsResponse) ProtoMessage() {}
 
 func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[4]
+	mi := &file_proto_product_product_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -377,7 +703,7 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
 func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{4}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListProductsResponse) GetProducts() []*Product {
@@ -394,35 +720,30 @@ func (x *ListProductsResponse) GetPagination() *common.PaginationResponse {
 	return nil
 }
 
-type UpdateProductRequest struct {
+type SearchProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         *common.Money          `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
-	StockQuantity int32                  `protobuf:"varint,5,opt,name=stock_quantity,json=stockQuantity,proto3" json:"stock_quantity,omitempty"`
-	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
-	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
-	IsActive      bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Pagination    *common.Pagination     `protobuf:"bytes,3,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateProductRequest) Reset() {
-	*x = UpdateProductRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[5]
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateProductRequest) String() string {
+func (x *SearchProductsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateProductRequest) ProtoMessage() {}
+func (*SearchProductsRequest) ProtoMessage() {}
 
-func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[5]
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -433,46 +754,234 @@ func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
-func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UpdateProductRequest) GetId() string {
+func (x *SearchProductsRequest) GetQuery() string {
 	if x != nil {
-		return x.Id
+		return x.Query
 	}
 	return ""
 }
 
-func (x *UpdateProductRequest) GetName() string {
+func (x *SearchProductsRequest) GetCategory() string {
 	if x != nil {
-		return x.Name
+		return x.Category
 	}
 	return ""
 }
 
-func (x *UpdateProductRequest) GetDescription() string {
+func (x *SearchProductsRequest) GetPagination() *common.Pagination {
 	if x != nil {
-		return x.Description
+		return x.Pagination
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateProductRequest) GetPrice() *common.Money {
+// 検索に一致した商品
+// highlighted_name と snippet は HTML エスケープ済みで、一致した語を <mark> で囲む
+type ProductSearchHit struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Product         *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Rank            float64                `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	HighlightedName string                 `protobuf:"bytes,3,opt,name=highlighted_name,json=highlightedName,proto3" json:"highlighted_name,omitempty"`
+	Snippet         string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"` // 説明のうち一致した語を含む部分
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProductSearchHit) Reset() {
+	*x = ProductSearchHit{}
+	mi := &file_proto_product_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchHit) ProtoMessage() {}
+
+func (x *ProductSearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[10]
 	if x != nil {
-		return x.Price
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchHit.ProtoReflect.Descriptor instead.
+func (*ProductSearchHit) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProductSearchHit) GetProduct() *Product {
+	if x != nil {
+		return x.Product
 	}
 	return nil
 }
 
-func (x *UpdateProductRequest) GetStockQuantity() int32 {
+func (x *ProductSearchHit) GetRank() float64 {
 	if x != nil {
-		return x.StockQuantity
+		return x.Rank
 	}
 	return 0
 }
 
+func (x *ProductSearchHit) GetHighlightedName() string {
+	if x != nil {
+		return x.HighlightedName
+	}
+	return ""
+}
+
+func (x *ProductSearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchProductsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Hits          []*ProductSearchHit        `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	Pagination    *common.PaginationResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Suggestion    string                     `protobuf:"bytes,3,opt,name=suggestion,proto3" json:"suggestion,omitempty"` // 一致する商品がない場合の修正候補。候補がなければ空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_proto_product_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchProductsResponse) GetHits() []*ProductSearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetPagination() *common.PaginationResponse {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetSuggestion() string {
+	if x != nil {
+		return x.Suggestion
+	}
+	return ""
+}
+
+type UpdateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price         *common.Money          `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Category      string                 `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	ImageUrls     []string               `protobuf:"bytes,7,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"` // 画像を置き換える。残る URL の画像は代替テキストを保持する
+	IsActive      bool                   `protobuf:"varint,8,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Sku           string                 `protobuf:"bytes,9,opt,name=sku,proto3" json:"sku,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProductRequest) Reset() {
+	*x = UpdateProductRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProductRequest) ProtoMessage() {}
+
+func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetPrice() *common.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
 func (x *UpdateProductRequest) GetCategory() string {
 	if x != nil {
 		return x.Category
@@ -494,6 +1003,13 @@ func (x *UpdateProductRequest) GetIsActive() bool {
 	return false
 }
 
+func (x *UpdateProductRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
 type DeleteProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -503,7 +1019,7 @@ type DeleteProductRequest struct {
 
 func (x *DeleteProductRequest) Reset() {
 	*x = DeleteProductRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[6]
+	mi := &file_proto_product_product_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -515,7 +1031,7 @@ func (x *DeleteProductRequest) String() string {
 func (*DeleteProductRequest) ProtoMessage() {}
 
 func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[6]
+	mi := &file_proto_product_product_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -528,7 +1044,7 @@ func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
 func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{6}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DeleteProductRequest) GetId() string {
@@ -547,7 +1063,7 @@ type DeleteProductResponse struct {
 
 func (x *DeleteProductResponse) Reset() {
 	*x = DeleteProductResponse{}
-	mi := &file_proto_product_product_proto_msgTypes[7]
+	mi := &file_proto_product_product_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -559,7 +1075,7 @@ func (x *DeleteProductResponse) String() string {
 func (*DeleteProductResponse) ProtoMessage() {}
 
 func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[7]
+	mi := &file_proto_product_product_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -572,7 +1088,7 @@ func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductResponse.ProtoReflect.Descriptor instead.
 func (*DeleteProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{7}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeleteProductResponse) GetSuccess() bool {
@@ -583,16 +1099,18 @@ func (x *DeleteProductResponse) GetSuccess() bool {
 }
 
 type UpdateStockRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ProductId      string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	QuantityChange int32                  `protobuf:"varint,2,opt,name=quantity_change,json=quantityChange,proto3" json:"quantity_change,omitempty"` // 正の値で増加、負の値で減少
+	state          protoimpl.MessageState  `protogen:"open.v1"`
+	ProductId      string                  `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	QuantityChange int32                   `protobuf:"varint,2,opt,name=quantity_change,json=quantityChange,proto3" json:"quantity_change,omitempty"` // 正の値で増加、負の値で減少
+	Reason         InventoryMovementReason `protobuf:"varint,3,opt,name=reason,proto3,enum=product.InventoryMovementReason" json:"reason,omitempty"`  // 未指定の場合は調整
+	ReferenceId    string                  `protobuf:"bytes,4,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`           // 注文IDや入荷伝票番号など
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *UpdateStockRequest) Reset() {
 	*x = UpdateStockRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[8]
+	mi := &file_proto_product_product_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -604,7 +1122,7 @@ func (x *UpdateStockRequest) String() string {
 func (*UpdateStockRequest) ProtoMessage() {}
 
 func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[8]
+	mi := &file_proto_product_product_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -617,7 +1135,7 @@ func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStockRequest.ProtoReflect.Descriptor instead.
 func (*UpdateStockRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{8}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UpdateStockRequest) GetProductId() string {
@@ -634,6 +1152,20 @@ func (x *UpdateStockRequest) GetQuantityChange() int32 {
 	return 0
 }
 
+func (x *UpdateStockRequest) GetReason() InventoryMovementReason {
+	if x != nil {
+		return x.Reason
+	}
+	return InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED
+}
+
+func (x *UpdateStockRequest) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
 type CheckStockRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	ProductId        string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -644,7 +1176,7 @@ type CheckStockRequest struct {
 
 func (x *CheckStockRequest) Reset() {
 	*x = CheckStockRequest{}
-	mi := &file_proto_product_product_proto_msgTypes[9]
+	mi := &file_proto_product_product_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -656,7 +1188,7 @@ func (x *CheckStockRequest) String() string {
 func (*CheckStockRequest) ProtoMessage() {}
 
 func (x *CheckStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[9]
+	mi := &file_proto_product_product_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -669,7 +1201,7 @@ func (x *CheckStockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckStockRequest.ProtoReflect.Descriptor instead.
 func (*CheckStockRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{9}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *CheckStockRequest) GetProductId() string {
@@ -687,16 +1219,18 @@ func (x *CheckStockRequest) GetRequiredQuantity() int32 {
 }
 
 type CheckStockResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Available     bool                   `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
-	CurrentStock  int32                  `protobuf:"varint,2,opt,name=current_stock,json=currentStock,proto3" json:"current_stock,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Available         bool                   `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`                                          // required_quantity を販売可能か
+	CurrentStock      int32                  `protobuf:"varint,2,opt,name=current_stock,json=currentStock,proto3" json:"current_stock,omitempty"`                // 在庫数（確保中の数量を含む）
+	ReservedQuantity  int32                  `protobuf:"varint,3,opt,name=reserved_quantity,json=reservedQuantity,proto3" json:"reserved_quantity,omitempty"`    // 確保中の数量
+	AvailableQuantity int32                  `protobuf:"varint,4,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"` // 販売可能数（在庫数 - 確保中の数量）
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CheckStockResponse) Reset() {
 	*x = CheckStockResponse{}
-	mi := &file_proto_product_product_proto_msgTypes[10]
+	mi := &file_proto_product_product_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -708,7 +1242,7 @@ func (x *CheckStockResponse) String() string {
 func (*CheckStockResponse) ProtoMessage() {}
 
 func (x *CheckStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_product_proto_msgTypes[10]
+	mi := &file_proto_product_product_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,7 +1255,7 @@ func (x *CheckStockResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckStockResponse.ProtoReflect.Descriptor instead.
 func (*CheckStockResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_product_proto_rawDescGZIP(), []int{10}
+	return file_proto_product_product_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CheckStockResponse) GetAvailable() bool {
@@ -738,84 +1272,880 @@ func (x *CheckStockResponse) GetCurrentStock() int32 {
 	return 0
 }
 
-var File_proto_product_product_proto protoreflect.FileDescriptor
+func (x *CheckStockResponse) GetReservedQuantity() int32 {
+	if x != nil {
+		return x.ReservedQuantity
+	}
+	return 0
+}
 
-const file_proto_product_product_proto_rawDesc = "" +
-	"\n" +
-	"\x1bproto/product/product.proto\x12\aproduct\x1a\x19proto/common/common.proto\"\xe9\x02\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
-	"\x05price\x18\x04 \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
-	"\x0estock_quantity\x18\x05 \x01(\x05R\rstockQuantity\x12\x1a\n" +
-	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1d\n" +
-	"\n" +
-	"image_urls\x18\a \x03(\tR\timageUrls\x12\x10\n" +
-	"\x03sku\x18\b \x01(\tR\x03sku\x12\x1b\n" +
-	"\tis_active\x18\t \x01(\bR\bisActive\x120\n" +
-	"\n" +
-	"created_at\x18\n" +
-	" \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
-	"\n" +
-	"updated_at\x18\v \x01(\v2\x11.common.TimestampR\tupdatedAt\"\xe5\x01\n" +
-	"\x14CreateProductRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12#\n" +
-	"\x05price\x18\x03 \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
-	"\x0estock_quantity\x18\x04 \x01(\x05R\rstockQuantity\x12\x1a\n" +
-	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x1d\n" +
-	"\n" +
-	"image_urls\x18\x06 \x03(\tR\timageUrls\x12\x10\n" +
-	"\x03sku\x18\a \x01(\tR\x03sku\"#\n" +
-	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x88\x01\n" +
-	"\x13ListProductsRequest\x122\n" +
-	"\n" +
-	"pagination\x18\x01 \x01(\v2\x12.common.PaginationR\n" +
-	"pagination\x12\x1a\n" +
-	"\bcategory\x18\x02 \x01(\tR\bcategory\x12!\n" +
-	"\fsearch_query\x18\x03 \x01(\tR\vsearchQuery\"\x80\x01\n" +
-	"\x14ListProductsResponse\x12,\n" +
-	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\x12:\n" +
-	"\n" +
-	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
-	"pagination\"\x80\x02\n" +
-	"\x14UpdateProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
-	"\x05price\x18\x04 \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
-	"\x0estock_quantity\x18\x05 \x01(\x05R\rstockQuantity\x12\x1a\n" +
-	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1d\n" +
-	"\n" +
-	"image_urls\x18\a \x03(\tR\timageUrls\x12\x1b\n" +
-	"\tis_active\x18\b \x01(\bR\bisActive\"&\n" +
-	"\x14DeleteProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
-	"\x15DeleteProductResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\\\n" +
-	"\x12UpdateStockRequest\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\x12'\n" +
-	"\x0fquantity_change\x18\x02 \x01(\x05R\x0equantityChange\"_\n" +
-	"\x11CheckStockRequest\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\x12+\n" +
-	"\x11required_quantity\x18\x02 \x01(\x05R\x10requiredQuantity\"W\n" +
-	"\x12CheckStockResponse\x12\x1c\n" +
-	"\tavailable\x18\x01 \x01(\bR\tavailable\x12#\n" +
-	"\rcurrent_stock\x18\x02 \x01(\x05R\fcurrentStock2\xf2\x03\n" +
+func (x *CheckStockResponse) GetAvailableQuantity() int32 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
+type StockHold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockHold) Reset() {
+	*x = StockHold{}
+	mi := &file_proto_product_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockHold) ProtoMessage() {}
+
+func (x *StockHold) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockHold.ProtoReflect.Descriptor instead.
+func (*StockHold) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StockHold) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockHold) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Reservation holds stock for an order until it is committed, released,
+// or expires. An order has at most one reservation.
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        ReservationStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=product.ReservationStatus" json:"status,omitempty"`
+	Items         []*StockHold           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	ExpiresAt     *common.Timestamp      `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_proto_product_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Reservation) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Reservation) GetStatus() ReservationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReservationStatus_RESERVATION_STATUS_UNSPECIFIED
+}
+
+func (x *Reservation) GetItems() []*StockHold {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Reservation) GetExpiresAt() *common.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Items         []*StockHold           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	TtlSeconds    int32                  `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 確保の有効期間。0 の場合は既定値
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReserveStockRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockHold {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CommitReservationRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReleaseReservationRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+// InventoryMovement is an entry of the inventory ledger. Every change of
+// stock_quantity is recorded, so the movements of a product add up to its stock.
+type InventoryMovement struct {
+	state          protoimpl.MessageState  `protogen:"open.v1"`
+	Id             int64                   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId      string                  `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	QuantityChange int32                   `protobuf:"varint,3,opt,name=quantity_change,json=quantityChange,proto3" json:"quantity_change,omitempty"`
+	StockAfter     int32                   `protobuf:"varint,4,opt,name=stock_after,json=stockAfter,proto3" json:"stock_after,omitempty"` // 変更後の在庫数
+	Reason         InventoryMovementReason `protobuf:"varint,5,opt,name=reason,proto3,enum=product.InventoryMovementReason" json:"reason,omitempty"`
+	ReferenceId    string                  `protobuf:"bytes,6,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	ActorType      string                  `protobuf:"bytes,7,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"` // user or service
+	ActorId        string                  `protobuf:"bytes,8,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`       // ユーザーID、またはサービスのクライアントID
+	CreatedAt      *common.Timestamp       `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *InventoryMovement) Reset() {
+	*x = InventoryMovement{}
+	mi := &file_proto_product_product_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryMovement) ProtoMessage() {}
+
+func (x *InventoryMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryMovement.ProtoReflect.Descriptor instead.
+func (*InventoryMovement) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *InventoryMovement) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InventoryMovement) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *InventoryMovement) GetQuantityChange() int32 {
+	if x != nil {
+		return x.QuantityChange
+	}
+	return 0
+}
+
+func (x *InventoryMovement) GetStockAfter() int32 {
+	if x != nil {
+		return x.StockAfter
+	}
+	return 0
+}
+
+func (x *InventoryMovement) GetReason() InventoryMovementReason {
+	if x != nil {
+		return x.Reason
+	}
+	return InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED
+}
+
+func (x *InventoryMovement) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+func (x *InventoryMovement) GetActorType() string {
+	if x != nil {
+		return x.ActorType
+	}
+	return ""
+}
+
+func (x *InventoryMovement) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *InventoryMovement) GetCreatedAt() *common.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListInventoryMovementsRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Pagination    *common.Pagination      `protobuf:"bytes,1,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	ProductId     string                  `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`                // 空の場合は全商品
+	Reason        InventoryMovementReason `protobuf:"varint,3,opt,name=reason,proto3,enum=product.InventoryMovementReason" json:"reason,omitempty"` // 未指定の場合は全理由
+	ReferenceId   string                  `protobuf:"bytes,4,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInventoryMovementsRequest) Reset() {
+	*x = ListInventoryMovementsRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryMovementsRequest) ProtoMessage() {}
+
+func (x *ListInventoryMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryMovementsRequest.ProtoReflect.Descriptor instead.
+func (*ListInventoryMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListInventoryMovementsRequest) GetPagination() *common.Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *ListInventoryMovementsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListInventoryMovementsRequest) GetReason() InventoryMovementReason {
+	if x != nil {
+		return x.Reason
+	}
+	return InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED
+}
+
+func (x *ListInventoryMovementsRequest) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+type ListInventoryMovementsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Movements     []*InventoryMovement       `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
+	Pagination    *common.PaginationResponse `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInventoryMovementsResponse) Reset() {
+	*x = ListInventoryMovementsResponse{}
+	mi := &file_proto_product_product_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryMovementsResponse) ProtoMessage() {}
+
+func (x *ListInventoryMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryMovementsResponse.ProtoReflect.Descriptor instead.
+func (*ListInventoryMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListInventoryMovementsResponse) GetMovements() []*InventoryMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+func (x *ListInventoryMovementsResponse) GetPagination() *common.PaginationResponse {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type AddProductImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	AltText       string                 `protobuf:"bytes,3,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductImageRequest) Reset() {
+	*x = AddProductImageRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductImageRequest) ProtoMessage() {}
+
+func (x *AddProductImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductImageRequest.ProtoReflect.Descriptor instead.
+func (*AddProductImageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AddProductImageRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddProductImageRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AddProductImageRequest) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+type ReorderProductImagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ImageIds      []string               `protobuf:"bytes,2,rep,name=image_ids,json=imageIds,proto3" json:"image_ids,omitempty"` // 商品のすべての画像IDを新しい表示順に並べたもの
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderProductImagesRequest) Reset() {
+	*x = ReorderProductImagesRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderProductImagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderProductImagesRequest) ProtoMessage() {}
+
+func (x *ReorderProductImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderProductImagesRequest.ProtoReflect.Descriptor instead.
+func (*ReorderProductImagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ReorderProductImagesRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ReorderProductImagesRequest) GetImageIds() []string {
+	if x != nil {
+		return x.ImageIds
+	}
+	return nil
+}
+
+type RemoveProductImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ImageId       string                 `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveProductImageRequest) Reset() {
+	*x = RemoveProductImageRequest{}
+	mi := &file_proto_product_product_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveProductImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveProductImageRequest) ProtoMessage() {}
+
+func (x *RemoveProductImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_product_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveProductImageRequest.ProtoReflect.Descriptor instead.
+func (*RemoveProductImageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_product_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RemoveProductImageRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RemoveProductImageRequest) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+var File_proto_product_product_proto protoreflect.FileDescriptor
+
+const file_proto_product_product_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/product/product.proto\x12\aproduct\x1a\x19proto/common/common.proto\"\x98\x03\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\x05price\x18\x04 \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
+	"\x0estock_quantity\x18\x05 \x01(\x05R\rstockQuantity\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\a \x03(\tR\timageUrls\x12\x10\n" +
+	"\x03sku\x18\b \x01(\tR\x03sku\x12\x1b\n" +
+	"\tis_active\x18\t \x01(\bR\bisActive\x120\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x11.common.TimestampR\tcreatedAt\x120\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x11.common.TimestampR\tupdatedAt\x12-\n" +
+	"\x06images\x18\f \x03(\v2\x15.product.ProductImageR\x06images\"g\n" +
+	"\fProductImage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x19\n" +
+	"\balt_text\x18\x03 \x01(\tR\aaltText\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\"\xe5\x01\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12#\n" +
+	"\x05price\x18\x03 \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
+	"\x0estock_quantity\x18\x04 \x01(\x05R\rstockQuantity\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\x06 \x03(\tR\timageUrls\x12\x10\n" +
+	"\x03sku\x18\a \x01(\tR\x03sku\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x16GetProductBySkuRequest\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\"2\n" +
+	"\x1cBatchGetProductsBySkuRequest\x12\x12\n" +
+	"\x04skus\x18\x01 \x03(\tR\x04skus\"p\n" +
+	"\x1dBatchGetProductsBySkuResponse\x12,\n" +
+	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\x12!\n" +
+	"\fmissing_skus\x18\x02 \x03(\tR\vmissingSkus\"\x88\x01\n" +
+	"\x13ListProductsRequest\x122\n" +
+	"\n" +
+	"pagination\x18\x01 \x01(\v2\x12.common.PaginationR\n" +
+	"pagination\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x12!\n" +
+	"\fsearch_query\x18\x03 \x01(\tR\vsearchQuery\"\x80\x01\n" +
+	"\x14ListProductsResponse\x12,\n" +
+	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\x12:\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
+	"pagination\"}\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1a\n" +
+	"\bcategory\x18\x02 \x01(\tR\bcategory\x122\n" +
+	"\n" +
+	"pagination\x18\x03 \x01(\v2\x12.common.PaginationR\n" +
+	"pagination\"\x97\x01\n" +
+	"\x10ProductSearchHit\x12*\n" +
+	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12)\n" +
+	"\x10highlighted_name\x18\x03 \x01(\tR\x0fhighlightedName\x12\x18\n" +
+	"\asnippet\x18\x04 \x01(\tR\asnippet\"\xa3\x01\n" +
+	"\x16SearchProductsResponse\x12-\n" +
+	"\x04hits\x18\x01 \x03(\v2\x19.product.ProductSearchHitR\x04hits\x12:\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
+	"pagination\x12\x1e\n" +
+	"\n" +
+	"suggestion\x18\x03 \x01(\tR\n" +
+	"suggestion\"\xf1\x01\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\x05price\x18\x04 \x01(\v2\r.common.MoneyR\x05price\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\a \x03(\tR\timageUrls\x12\x1b\n" +
+	"\tis_active\x18\b \x01(\bR\bisActive\x12\x10\n" +
+	"\x03sku\x18\t \x01(\tR\x03skuJ\x04\b\x05\x10\x06\"&\n" +
+	"\x14DeleteProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
+	"\x15DeleteProductResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xb9\x01\n" +
+	"\x12UpdateStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12'\n" +
+	"\x0fquantity_change\x18\x02 \x01(\x05R\x0equantityChange\x128\n" +
+	"\x06reason\x18\x03 \x01(\x0e2 .product.InventoryMovementReasonR\x06reason\x12!\n" +
+	"\freference_id\x18\x04 \x01(\tR\vreferenceId\"_\n" +
+	"\x11CheckStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12+\n" +
+	"\x11required_quantity\x18\x02 \x01(\x05R\x10requiredQuantity\"\xb3\x01\n" +
+	"\x12CheckStockResponse\x12\x1c\n" +
+	"\tavailable\x18\x01 \x01(\bR\tavailable\x12#\n" +
+	"\rcurrent_stock\x18\x02 \x01(\x05R\fcurrentStock\x12+\n" +
+	"\x11reserved_quantity\x18\x03 \x01(\x05R\x10reservedQuantity\x12-\n" +
+	"\x12available_quantity\x18\x04 \x01(\x05R\x11availableQuantity\"F\n" +
+	"\tStockHold\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\xb8\x01\n" +
+	"\vReservation\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.product.ReservationStatusR\x06status\x12(\n" +
+	"\x05items\x18\x03 \x03(\v2\x12.product.StockHoldR\x05items\x120\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x11.common.TimestampR\texpiresAt\"{\n" +
+	"\x13ReserveStockRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.product.StockHoldR\x05items\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x05R\n" +
+	"ttlSeconds\"5\n" +
+	"\x18CommitReservationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"6\n" +
+	"\x19ReleaseReservationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\xd5\x02\n" +
+	"\x11InventoryMovement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12'\n" +
+	"\x0fquantity_change\x18\x03 \x01(\x05R\x0equantityChange\x12\x1f\n" +
+	"\vstock_after\x18\x04 \x01(\x05R\n" +
+	"stockAfter\x128\n" +
+	"\x06reason\x18\x05 \x01(\x0e2 .product.InventoryMovementReasonR\x06reason\x12!\n" +
+	"\freference_id\x18\x06 \x01(\tR\vreferenceId\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\a \x01(\tR\tactorType\x12\x19\n" +
+	"\bactor_id\x18\b \x01(\tR\aactorId\x120\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x11.common.TimestampR\tcreatedAt\"\xcf\x01\n" +
+	"\x1dListInventoryMovementsRequest\x122\n" +
+	"\n" +
+	"pagination\x18\x01 \x01(\v2\x12.common.PaginationR\n" +
+	"pagination\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x128\n" +
+	"\x06reason\x18\x03 \x01(\x0e2 .product.InventoryMovementReasonR\x06reason\x12!\n" +
+	"\freference_id\x18\x04 \x01(\tR\vreferenceId\"\x96\x01\n" +
+	"\x1eListInventoryMovementsResponse\x128\n" +
+	"\tmovements\x18\x01 \x03(\v2\x1a.product.InventoryMovementR\tmovements\x12:\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1a.common.PaginationResponseR\n" +
+	"pagination\"d\n" +
+	"\x16AddProductImageRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x19\n" +
+	"\balt_text\x18\x03 \x01(\tR\aaltText\"Y\n" +
+	"\x1bReorderProductImagesRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1b\n" +
+	"\timage_ids\x18\x02 \x03(\tR\bimageIds\"U\n" +
+	"\x19RemoveProductImageRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x19\n" +
+	"\bimage_id\x18\x02 \x01(\tR\aimageId*\xb7\x01\n" +
+	"\x11ReservationStatus\x12\"\n" +
+	"\x1eRESERVATION_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17RESERVATION_STATUS_HELD\x10\x01\x12 \n" +
+	"\x1cRESERVATION_STATUS_COMMITTED\x10\x02\x12\x1f\n" +
+	"\x1bRESERVATION_STATUS_RELEASED\x10\x03\x12\x1e\n" +
+	"\x1aRESERVATION_STATUS_EXPIRED\x10\x04*\xdf\x01\n" +
+	"\x17InventoryMovementReason\x12)\n" +
+	"%INVENTORY_MOVEMENT_REASON_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eINVENTORY_MOVEMENT_REASON_SALE\x10\x01\x12%\n" +
+	"!INVENTORY_MOVEMENT_REASON_RESTOCK\x10\x02\x12(\n" +
+	"$INVENTORY_MOVEMENT_REASON_ADJUSTMENT\x10\x03\x12$\n" +
+	" INVENTORY_MOVEMENT_REASON_RETURN\x10\x042\xa2\n" +
+	"\n" +
 	"\x0eProductService\x12@\n" +
 	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x10.product.Product\x12:\n" +
 	"\n" +
-	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x10.product.Product\x12K\n" +
-	"\fListProducts\x12\x1c.product.ListProductsRequest\x1a\x1d.product.ListProductsResponse\x12@\n" +
+	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x10.product.Product\x12D\n" +
+	"\x0fGetProductBySku\x12\x1f.product.GetProductBySkuRequest\x1a\x10.product.Product\x12f\n" +
+	"\x15BatchGetProductsBySku\x12%.product.BatchGetProductsBySkuRequest\x1a&.product.BatchGetProductsBySkuResponse\x12K\n" +
+	"\fListProducts\x12\x1c.product.ListProductsRequest\x1a\x1d.product.ListProductsResponse\x12Q\n" +
+	"\x0eSearchProducts\x12\x1e.product.SearchProductsRequest\x1a\x1f.product.SearchProductsResponse\x12@\n" +
 	"\rUpdateProduct\x12\x1d.product.UpdateProductRequest\x1a\x10.product.Product\x12N\n" +
 	"\rDeleteProduct\x12\x1d.product.DeleteProductRequest\x1a\x1e.product.DeleteProductResponse\x12<\n" +
 	"\vUpdateStock\x12\x1b.product.UpdateStockRequest\x1a\x10.product.Product\x12E\n" +
 	"\n" +
-	"CheckStock\x12\x1a.product.CheckStockRequest\x1a\x1b.product.CheckStockResponseB,Z*github.com/Riku-KANO/kube-ec/proto/productb\x06proto3"
+	"CheckStock\x12\x1a.product.CheckStockRequest\x1a\x1b.product.CheckStockResponse\x12B\n" +
+	"\fReserveStock\x12\x1c.product.ReserveStockRequest\x1a\x14.product.Reservation\x12L\n" +
+	"\x11CommitReservation\x12!.product.CommitReservationRequest\x1a\x14.product.Reservation\x12N\n" +
+	"\x12ReleaseReservation\x12\".product.ReleaseReservationRequest\x1a\x14.product.Reservation\x12i\n" +
+	"\x16ListInventoryMovements\x12&.product.ListInventoryMovementsRequest\x1a'.product.ListInventoryMovementsResponse\x12D\n" +
+	"\x0fAddProductImage\x12\x1f.product.AddProductImageRequest\x1a\x10.product.Product\x12N\n" +
+	"\x14ReorderProductImages\x12$.product.ReorderProductImagesRequest\x1a\x10.product.Product\x12J\n" +
+	"\x12RemoveProductImage\x12\".product.RemoveProductImageRequest\x1a\x10.product.ProductB,Z*github.com/Riku-KANO/kube-ec/proto/productb\x06proto3"
 
 var (
 	file_proto_product_product_proto_rawDescOnce sync.Once
@@ -829,52 +2159,110 @@ func file_proto_product_product_proto_rawDescGZIP() []byte {
 	return file_proto_product_product_proto_rawDescData
 }
 
-var file_proto_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_product_product_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_proto_product_product_proto_goTypes = []any{
-	(*Product)(nil),                   // 0: product.Product
-	(*CreateProductRequest)(nil),      // 1: product.CreateProductRequest
-	(*GetProductRequest)(nil),         // 2: product.GetProductRequest
-	(*ListProductsRequest)(nil),       // 3: product.ListProductsRequest
-	(*ListProductsResponse)(nil),      // 4: product.ListProductsResponse
-	(*UpdateProductRequest)(nil),      // 5: product.UpdateProductRequest
-	(*DeleteProductRequest)(nil),      // 6: product.DeleteProductRequest
-	(*DeleteProductResponse)(nil),     // 7: product.DeleteProductResponse
-	(*UpdateStockRequest)(nil),        // 8: product.UpdateStockRequest
-	(*CheckStockRequest)(nil),         // 9: product.CheckStockRequest
-	(*CheckStockResponse)(nil),        // 10: product.CheckStockResponse
-	(*common.Money)(nil),              // 11: common.Money
-	(*common.Timestamp)(nil),          // 12: common.Timestamp
-	(*common.Pagination)(nil),         // 13: common.Pagination
-	(*common.PaginationResponse)(nil), // 14: common.PaginationResponse
+	(ReservationStatus)(0),                 // 0: product.ReservationStatus
+	(InventoryMovementReason)(0),           // 1: product.InventoryMovementReason
+	(*Product)(nil),                        // 2: product.Product
+	(*ProductImage)(nil),                   // 3: product.ProductImage
+	(*CreateProductRequest)(nil),           // 4: product.CreateProductRequest
+	(*GetProductRequest)(nil),              // 5: product.GetProductRequest
+	(*GetProductBySkuRequest)(nil),         // 6: product.GetProductBySkuRequest
+	(*BatchGetProductsBySkuRequest)(nil),   // 7: product.BatchGetProductsBySkuRequest
+	(*BatchGetProductsBySkuResponse)(nil),  // 8: product.BatchGetProductsBySkuResponse
+	(*ListProductsRequest)(nil),            // 9: product.ListProductsRequest
+	(*ListProductsResponse)(nil),           // 10: product.ListProductsResponse
+	(*SearchProductsRequest)(nil),          // 11: product.SearchProductsRequest
+	(*ProductSearchHit)(nil),               // 12: product.ProductSearchHit
+	(*SearchProductsResponse)(nil),         // 13: product.SearchProductsResponse
+	(*UpdateProductRequest)(nil),           // 14: product.UpdateProductRequest
+	(*DeleteProductRequest)(nil),           // 15: product.DeleteProductRequest
+	(*DeleteProductResponse)(nil),          // 16: product.DeleteProductResponse
+	(*UpdateStockRequest)(nil),             // 17: product.UpdateStockRequest
+	(*CheckStockRequest)(nil),              // 18: product.CheckStockRequest
+	(*CheckStockResponse)(nil),             // 19: product.CheckStockResponse
+	(*StockHold)(nil),                      // 20: product.StockHold
+	(*Reservation)(nil),                    // 21: product.Reservation
+	(*ReserveStockRequest)(nil),            // 22: product.ReserveStockRequest
+	(*CommitReservationRequest)(nil),       // 23: product.CommitReservationRequest
+	(*ReleaseReservationRequest)(nil),      // 24: product.ReleaseReservationRequest
+	(*InventoryMovement)(nil),              // 25: product.InventoryMovement
+	(*ListInventoryMovementsRequest)(nil),  // 26: product.ListInventoryMovementsRequest
+	(*ListInventoryMovementsResponse)(nil), // 27: product.ListInventoryMovementsResponse
+	(*AddProductImageRequest)(nil),         // 28: product.AddProductImageRequest
+	(*ReorderProductImagesRequest)(nil),    // 29: product.ReorderProductImagesRequest
+	(*RemoveProductImageRequest)(nil),      // 30: product.RemoveProductImageRequest
+	(*common.Money)(nil),                   // 31: common.Money
+	(*common.Timestamp)(nil),               // 32: common.Timestamp
+	(*common.Pagination)(nil),              // 33: common.Pagination
+	(*common.PaginationResponse)(nil),      // 34: common.PaginationResponse
 }
 var file_proto_product_product_proto_depIdxs = []int32{
-	11, // 0: product.Product.price:type_name -> common.Money
-	12, // 1: product.Product.created_at:type_name -> common.Timestamp
-	12, // 2: product.Product.updated_at:type_name -> common.Timestamp
-	11, // 3: product.CreateProductRequest.price:type_name -> common.Money
-	13, // 4: product.ListProductsRequest.pagination:type_name -> common.Pagination
-	0,  // 5: product.ListProductsResponse.products:type_name -> product.Product
-	14, // 6: product.ListProductsResponse.pagination:type_name -> common.PaginationResponse
-	11, // 7: product.UpdateProductRequest.price:type_name -> common.Money
-	1,  // 8: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
-	2,  // 9: product.ProductService.GetProduct:input_type -> product.GetProductRequest
-	3,  // 10: product.ProductService.ListProducts:input_type -> product.ListProductsRequest
-	5,  // 11: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
-	6,  // 12: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
-	8,  // 13: product.ProductService.UpdateStock:input_type -> product.UpdateStockRequest
-	9,  // 14: product.ProductService.CheckStock:input_type -> product.CheckStockRequest
-	0,  // 15: product.ProductService.CreateProduct:output_type -> product.Product
-	0,  // 16: product.ProductService.GetProduct:output_type -> product.Product
-	4,  // 17: product.ProductService.ListProducts:output_type -> product.ListProductsResponse
-	0,  // 18: product.ProductService.UpdateProduct:output_type -> product.Product
-	7,  // 19: product.ProductService.DeleteProduct:output_type -> product.DeleteProductResponse
-	0,  // 20: product.ProductService.UpdateStock:output_type -> product.Product
-	10, // 21: product.ProductService.CheckStock:output_type -> product.CheckStockResponse
-	15, // [15:22] is the sub-list for method output_type
-	8,  // [8:15] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	31, // 0: product.Product.price:type_name -> common.Money
+	32, // 1: product.Product.created_at:type_name -> common.Timestamp
+	32, // 2: product.Product.updated_at:type_name -> common.Timestamp
+	3,  // 3: product.Product.images:type_name -> product.ProductImage
+	31, // 4: product.CreateProductRequest.price:type_name -> common.Money
+	2,  // 5: product.BatchGetProductsBySkuResponse.products:type_name -> product.Product
+	33, // 6: product.ListProductsRequest.pagination:type_name -> common.Pagination
+	2,  // 7: product.ListProductsResponse.products:type_name -> product.Product
+	34, // 8: product.ListProductsResponse.pagination:type_name -> common.PaginationResponse
+	33, // 9: product.SearchProductsRequest.pagination:type_name -> common.Pagination
+	2,  // 10: product.ProductSearchHit.product:type_name -> product.Product
+	12, // 11: product.SearchProductsResponse.hits:type_name -> product.ProductSearchHit
+	34, // 12: product.SearchProductsResponse.pagination:type_name -> common.PaginationResponse
+	31, // 13: product.UpdateProductRequest.price:type_name -> common.Money
+	1,  // 14: product.UpdateStockRequest.reason:type_name -> product.InventoryMovementReason
+	0,  // 15: product.Reservation.status:type_name -> product.ReservationStatus
+	20, // 16: product.Reservation.items:type_name -> product.StockHold
+	32, // 17: product.Reservation.expires_at:type_name -> common.Timestamp
+	20, // 18: product.ReserveStockRequest.items:type_name -> product.StockHold
+	1,  // 19: product.InventoryMovement.reason:type_name -> product.InventoryMovementReason
+	32, // 20: product.InventoryMovement.created_at:type_name -> common.Timestamp
+	33, // 21: product.ListInventoryMovementsRequest.pagination:type_name -> common.Pagination
+	1,  // 22: product.ListInventoryMovementsRequest.reason:type_name -> product.InventoryMovementReason
+	25, // 23: product.ListInventoryMovementsResponse.movements:type_name -> product.InventoryMovement
+	34, // 24: product.ListInventoryMovementsResponse.pagination:type_name -> common.PaginationResponse
+	4,  // 25: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	5,  // 26: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	6,  // 27: product.ProductService.GetProductBySku:input_type -> product.GetProductBySkuRequest
+	7,  // 28: product.ProductService.BatchGetProductsBySku:input_type -> product.BatchGetProductsBySkuRequest
+	9,  // 29: product.ProductService.ListProducts:input_type -> product.ListProductsRequest
+	11, // 30: product.ProductService.SearchProducts:input_type -> product.SearchProductsRequest
+	14, // 31: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
+	15, // 32: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
+	17, // 33: product.ProductService.UpdateStock:input_type -> product.UpdateStockRequest
+	18, // 34: product.ProductService.CheckStock:input_type -> product.CheckStockRequest
+	22, // 35: product.ProductService.ReserveStock:input_type -> product.ReserveStockRequest
+	23, // 36: product.ProductService.CommitReservation:input_type -> product.CommitReservationRequest
+	24, // 37: product.ProductService.ReleaseReservation:input_type -> product.ReleaseReservationRequest
+	26, // 38: product.ProductService.ListInventoryMovements:input_type -> product.ListInventoryMovementsRequest
+	28, // 39: product.ProductService.AddProductImage:input_type -> product.AddProductImageRequest
+	29, // 40: product.ProductService.ReorderProductImages:input_type -> product.ReorderProductImagesRequest
+	30, // 41: product.ProductService.RemoveProductImage:input_type -> product.RemoveProductImageRequest
+	2,  // 42: product.ProductService.CreateProduct:output_type -> product.Product
+	2,  // 43: product.ProductService.GetProduct:output_type -> product.Product
+	2,  // 44: product.ProductService.GetProductBySku:output_type -> product.Product
+	8,  // 45: product.ProductService.BatchGetProductsBySku:output_type -> product.BatchGetProductsBySkuResponse
+	10, // 46: product.ProductService.ListProducts:output_type -> product.ListProductsResponse
+	13, // 47: product.ProductService.SearchProducts:output_type -> product.SearchProductsResponse
+	2,  // 48: product.ProductService.UpdateProduct:output_type -> product.Product
+	16, // 49: product.ProductService.DeleteProduct:output_type -> product.DeleteProductResponse
+	2,  // 50: product.ProductService.UpdateStock:output_type -> product.Product
+	19, // 51: product.ProductService.CheckStock:output_type -> product.CheckStockResponse
+	21, // 52: product.ProductService.ReserveStock:output_type -> product.Reservation
+	21, // 53: product.ProductService.CommitReservation:output_type -> product.Reservation
+	21, // 54: product.ProductService.ReleaseReservation:output_type -> product.Reservation
+	27, // 55: product.ProductService.ListInventoryMovements:output_type -> product.ListInventoryMovementsResponse
+	2,  // 56: product.ProductService.AddProductImage:output_type -> product.Product
+	2,  // 57: product.ProductService.ReorderProductImages:output_type -> product.Product
+	2,  // 58: product.ProductService.RemoveProductImage:output_type -> product.Product
+	42, // [42:59] is the sub-list for method output_type
+	25, // [25:42] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_product_product_proto_init() }
@@ -887,13 +2275,14 @@ func file_proto_product_product_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_product_product_proto_rawDesc), len(file_proto_product_product_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_product_product_proto_goTypes,
 		DependencyIndexes: file_proto_product_product_proto_depIdxs,
+		EnumInfos:         file_proto_product_product_proto_enumTypes,
 		MessageInfos:      file_proto_product_product_proto_msgTypes,
 	}.Build()
 	File_proto_product_product_proto = out.File
@@ -48,4 +48,11 @@ protoc --go_out=. --go_opt=paths=source_relative \
   --proto_path=. \
   proto/payment/payment.proto
 
+# Generate checkout proto
+echo "Generating checkout proto..."
+protoc --go_out=. --go_opt=paths=source_relative \
+  --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+  --proto_path=. \
+  proto/checkout/checkout.proto
+
 echo "Proto generation completed successfully!"
@@ -5,6 +5,8 @@ CREATE TABLE IF NOT EXISTS users (
     password_hash VARCHAR(60) NOT NULL,  -- bcrypt hashes are always 60 characters
     name VARCHAR(255) NOT NULL,
     phone_number VARCHAR(50),
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    email_verified_at TIMESTAMP,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -14,3 +16,306 @@ CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 
 -- Create index on created_at
 CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+
+-- Create refresh_tokens table
+-- Only the SHA-256 hash of each refresh token is stored.
+-- Tokens obtained by rotating each other share a family_id.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    access_token_id VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for family revocation and per-user lookups
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_access_token_id ON refresh_tokens(access_token_id);
+
+-- Create revoked_access_tokens table
+-- Logged out access tokens are denied by jti until they expire.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    token_id VARCHAR(64) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on expires_at for purging expired entries
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+-- Create user_mfa table
+-- TOTP secrets of users enrolled in MFA. MFA is enforced once confirmed_at is set.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    totp_secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create mfa_recovery_codes table
+-- Only hashes of the one-time recovery codes are stored.
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on user_id and code_hash for code lookups
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id_code_hash ON mfa_recovery_codes(user_id, code_hash);
+
+-- Create password_reset_tokens table
+-- Only hashes of the single-use reset tokens are stored.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on user_id for invalidating the tokens of a user
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- Create login_attempts table
+-- Failed logins are counted per account and per client IP for brute-force protection.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    throttle_key VARCHAR(320) PRIMARY KEY,  -- account:<email> or ip:<address>
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP,
+    locked_until TIMESTAMP
+);
+
+-- Create user_roles table
+-- Roles are embedded in access tokens and checked by the services' permission tables.
+-- The first admin is granted by hand:
+--   INSERT INTO user_roles (user_id, role) VALUES ('<user id>', 'admin');
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(64) NOT NULL,  -- customer, staff, admin or a custom role
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role)
+);
+
+-- Grant the customer role to users registered before roles existed
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'customer' FROM users
+ON CONFLICT (user_id, role) DO NOTHING;
+
+-- Create service_clients table
+-- Backend services exchange their client secret for short-lived service tokens.
+-- Only hashes of the secrets are stored.
+CREATE TABLE IF NOT EXISTS service_clients (
+    id VARCHAR(64) PRIMARY KEY,
+    secret_hash VARCHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL,  -- e.g. {product:stock,payment:process}
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create checkout_sagas table
+-- The checkout service saves each checkout after every step, so checkouts
+-- interrupted by a restart are finished or compensated when it comes back.
+CREATE TABLE IF NOT EXISTS checkout_sagas (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    items JSONB NOT NULL,
+    shipping_address JSONB NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,
+    step VARCHAR(50) NOT NULL,  -- started, order_created, ..., completed, compensating or failed
+    order_id VARCHAR(255) NOT NULL DEFAULT '',
+    total_currency VARCHAR(3) NOT NULL DEFAULT '',
+    total_amount BIGINT NOT NULL DEFAULT 0,
+    reserved_items JSONB NOT NULL DEFAULT '[]',  -- stock taken and not yet given back
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    refund_id VARCHAR(255) NOT NULL DEFAULT '',
+    order_cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    failure VARCHAR(50) NOT NULL DEFAULT '',
+    failure_message TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,  -- guards against two coordinators running the same checkout
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for finding unfinished checkouts to resume
+CREATE INDEX IF NOT EXISTS idx_checkout_sagas_unfinished ON checkout_sagas(updated_at) WHERE step NOT IN ('completed', 'failed');
+
+-- Create orders table
+-- Items are a snapshot of the products at the time the order was placed.
+-- A NULL billing address means the order is billed to the shipping address.
+CREATE TABLE IF NOT EXISTS orders (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    items JSONB NOT NULL,
+    total_currency VARCHAR(3) NOT NULL,
+    total_amount BIGINT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    shipping_address JSONB NOT NULL,
+    billing_address JSONB,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for listing the orders of a user, newest first
+CREATE INDEX IF NOT EXISTS idx_orders_user_id_created_at ON orders(user_id, created_at DESC);
+
+-- Create order_status_history table
+-- Every status change of an order is recorded in the same transaction as the
+-- change, with who made it and why. from_status is empty for the creation.
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL DEFAULT '',
+    to_status VARCHAR(50) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,  -- user or service
+    actor_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on order_id for reading the history of an order
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, id);
+
+-- Create products table
+-- reserved_quantity is the stock held by reservations that have not been
+-- committed or released; available-to-sell is stock_quantity - reserved_quantity.
+CREATE TABLE IF NOT EXISTS products (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price_currency VARCHAR(3) NOT NULL,
+    price_amount BIGINT NOT NULL,
+    stock_quantity INTEGER NOT NULL DEFAULT 0 CHECK (stock_quantity >= 0),
+    reserved_quantity INTEGER NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0),
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    sku VARCHAR(100) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for listing the products of a category, newest first
+CREATE INDEX IF NOT EXISTS idx_products_category_created_at ON products(category, created_at DESC);
+
+-- Create stock_reservations table
+-- Each row holds stock of one product for an order. Holds move from held to
+-- committed, released, or expired when the sweeper releases them after expires_at.
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    order_id VARCHAR(255) NOT NULL,
+    product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, product_id)
+);
+
+-- Create index for finding holds to expire
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_held_expires_at ON stock_reservations(expires_at) WHERE status = 'held';
+
+-- Create inventory_movements table
+-- Every change of products.stock_quantity is recorded in the same transaction
+-- as the change, so the movements of a product add up to its stock.
+-- Products with movements cannot be deleted, so the ledger is never lost;
+-- they are deactivated instead.
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    quantity_change INTEGER NOT NULL CHECK (quantity_change <> 0),
+    stock_after INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL,  -- sale, restock, adjustment or return
+    reference_id VARCHAR(255) NOT NULL DEFAULT '',  -- e.g. the order id of a sale
+    actor_type VARCHAR(20) NOT NULL,  -- user or service
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on product_id for reading the ledger of a product
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_product_id ON inventory_movements(product_id, id);
+
+-- Create index on reference_id for reconciling movements with orders
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_reference_id ON inventory_movements(reference_id) WHERE reference_id <> '';
+
+-- Create product_images table
+-- position is the display order of the images of a product, starting at 0.
+-- The unique constraint is deferred so images can be reordered in one transaction.
+CREATE TABLE IF NOT EXISTS product_images (
+    id VARCHAR(255) PRIMARY KEY,
+    product_id VARCHAR(255) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL CHECK (position >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT product_images_product_id_position_key UNIQUE (product_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Create unique index on sku
+-- Warehouse and ERP integrations look products up by SKU. Products without a SKU are allowed.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku) WHERE sku <> '';
+
+-- Create full-text search column on products
+-- The simple configuration does not stem, so English and Japanese text are indexed alike.
+-- Matches in the name and SKU rank above matches in the category and description.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', sku), 'A') ||
+    setweight(to_tsvector('simple', category), 'B') ||
+    setweight(to_tsvector('simple', description), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+
+-- Create trigram indexes for substring search
+-- Japanese text has no spaces between words, so it is matched by substring instead of by word.
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_sku_trgm ON products USING GIN (sku gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_description_trgm ON products USING GIN (description gin_trgm_ops);
+
+-- Create product_search_terms materialized view
+-- The words of the catalog, used to suggest a correction for a query that matches nothing.
+-- The product service refreshes it periodically.
+CREATE MATERIALIZED VIEW IF NOT EXISTS product_search_terms AS
+    SELECT word, ndoc FROM ts_stat('SELECT search_vector FROM products');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_search_terms_word ON product_search_terms(word);
+CREATE INDEX IF NOT EXISTS idx_product_search_terms_word_trgm ON product_search_terms USING GIN (word gin_trgm_ops);
+
+-- Create idempotency key on orders
+-- The checkout saga sends its id so a retried or resumed CreateOrder finds the order it already created.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_user_id_idempotency_key ON orders(user_id, idempotency_key) WHERE idempotency_key <> '';
+
+-- Create payments table
+-- status and method hold the names of the PaymentStatus and PaymentMethod enums.
+CREATE TABLE IF NOT EXISTS payments (
+    id VARCHAR(255) PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    amount_currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    status VARCHAR(50) NOT NULL,
+    method VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create unique index on order_id for open and completed payments
+-- An order is charged at most once. A failed or refunded payment does not block another attempt.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_order_id_active ON payments(order_id)
+    WHERE status IN ('PAYMENT_STATUS_PENDING', 'PAYMENT_STATUS_PROCESSING', 'PAYMENT_STATUS_COMPLETED');
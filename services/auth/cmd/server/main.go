@@ -8,12 +8,15 @@ import (
 	"os"
 	"time"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/infrastructure/notification"
 	"github.com/Riku-KANO/kube-ec/services/auth/internal/infrastructure/persistence"
 	grpchandler "github.com/Riku-KANO/kube-ec/services/auth/internal/presentation/grpc"
 )
@@ -25,9 +28,12 @@ func main() {
 		log.Fatal("DATABASE_URL environment variable is required")
 	}
 
+	// Tokens are signed with asymmetric keys when a key directory is mounted,
+	// otherwise with the shared HS256 secret
+	jwtKeysDir := os.Getenv("JWT_KEYS_DIR")
 	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET environment variable is required")
+	if jwtKeysDir == "" && jwtSecret == "" {
+		log.Fatal("JWT_KEYS_DIR or JWT_SECRET environment variable is required")
 	}
 
 	// Connect to database
@@ -51,13 +57,49 @@ func main() {
 	// Initialize infrastructure layer (Repository)
 	authRepo := persistence.NewAuthRepository(db)
 
+	// Password reset and email verification tokens are written to a log until a mail sender is configured
+	var notifier domainauth.Notifier = notification.NewLogNotifier(os.Stdout)
+	if path := os.Getenv("NOTIFICATION_LOG_FILE"); path != "" {
+		fileNotifier, err := notification.NewFileNotifier(path)
+		if err != nil {
+			log.Fatalf("Failed to open notification log: %v", err)
+		}
+		notifier = fileNotifier
+	}
+
+	verificationPolicy, err := auth.ParseEmailVerificationPolicy(os.Getenv("EMAIL_VERIFICATION_POLICY"))
+	if err != nil {
+		log.Fatalf("Invalid EMAIL_VERIFICATION_POLICY: %v", err)
+	}
+	log.Printf("Email verification policy: %s", verificationPolicy)
+
 	// Initialize application layer (Service)
-	authService := appauth.NewService(
-		authRepo,
-		jwtSecret,
-		24*time.Hour,  // Access token duration
-		720*time.Hour, // Refresh token duration (30 days)
-	)
+	accessDuration := 24 * time.Hour   // Access token duration
+	refreshDuration := 720 * time.Hour // Refresh token duration (30 days)
+
+	var authService *appauth.Service
+	if jwtKeysDir != "" {
+		activeKeyID := os.Getenv("JWT_ACTIVE_KEY_ID")
+		if activeKeyID == "" {
+			log.Fatal("JWT_ACTIVE_KEY_ID environment variable is required with JWT_KEYS_DIR")
+		}
+
+		keyRing, err := auth.LoadKeyRing(jwtKeysDir, activeKeyID)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+
+		log.Printf("Signing tokens with key %s", activeKeyID)
+		authService = appauth.NewServiceWithKeyRing(authRepo, keyRing, accessDuration, refreshDuration,
+			appauth.WithNotifier(notifier),
+			appauth.WithEmailVerificationPolicy(verificationPolicy),
+		)
+	} else {
+		authService = appauth.NewService(authRepo, jwtSecret, accessDuration, refreshDuration,
+			appauth.WithNotifier(notifier),
+			appauth.WithEmailVerificationPolicy(verificationPolicy),
+		)
+	}
 
 	// Initialize presentation layer (gRPC Handler)
 	authHandler := grpchandler.NewAuthHandler(authService)
@@ -73,9 +115,28 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// Administrative RPCs require an admin access token.
+	// MFA settings can only be changed by the user and sessions can be revoked
+	// by the user or an admin, which the handler checks.
+	permissions := auth.Permissions{
+		authpb.AuthService_UnlockAccount_FullMethodName:       {auth.RoleAdmin},
+		authpb.AuthService_AssignRole_FullMethodName:          {auth.RoleAdmin},
+		authpb.AuthService_RevokeRole_FullMethodName:          {auth.RoleAdmin},
+		authpb.AuthService_CreateServiceClient_FullMethodName: {auth.RoleAdmin},
+
+		authpb.AuthService_EnrollMFA_FullMethodName:             {},
+		authpb.AuthService_ConfirmMFA_FullMethodName:            {},
+		authpb.AuthService_GenerateRecoveryCodes_FullMethodName: {},
+		authpb.AuthService_DisableMFA_FullMethodName:            {},
+		authpb.AuthService_LogoutAll_FullMethodName:             {},
+	}
+
 	// Create gRPC server with interceptors
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(grpchandler.UnaryRequestIDInterceptor()),
+		grpc.ChainUnaryInterceptor(
+			grpchandler.UnaryRequestIDInterceptor(),
+			auth.UnaryServerInterceptor(authService.AccessTokenVerifier(), permissions, nil),
+		),
 	)
 	authpb.RegisterAuthServiceServer(grpcServer, authHandler)
 
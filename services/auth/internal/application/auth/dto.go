@@ -10,10 +10,12 @@ type RegisterInput struct {
 	PhoneNumber string
 }
 
-// LoginInput represents login request data
+// LoginInput represents login request data.
+// ClientIP is the address of the end user, used to throttle failed logins per IP.
 type LoginInput struct {
 	Email    string
 	Password string
+	ClientIP string
 }
 
 // ChangePasswordInput represents password change request data
@@ -23,7 +25,72 @@ type ChangePasswordInput struct {
 	NewPassword string
 }
 
-// AuthOutput represents authentication response data
+// RequestPasswordResetInput represents password reset request data
+type RequestPasswordResetInput struct {
+	Email string
+}
+
+// ConfirmPasswordResetInput represents the data to set a new password with a reset token
+type ConfirmPasswordResetInput struct {
+	Token       string
+	NewPassword string
+}
+
+// ResendVerificationInput represents a request to send the email verification again
+type ResendVerificationInput struct {
+	Email string
+}
+
+// UnlockAccountInput represents a request to lift a login lockout.
+// ClientIP is optional and also unlocks that address.
+type UnlockAccountInput struct {
+	Email    string
+	ClientIP string
+}
+
+// RoleInput represents a request to assign or revoke a role of a user
+type RoleInput struct {
+	UserID string
+	Role   string
+}
+
+// ClientCredentialsInput represents a client credentials grant request.
+// Scopes may be empty to request every scope of the client.
+type ClientCredentialsInput struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// CreateServiceClientInput represents a request to register a service client
+type CreateServiceClientInput struct {
+	ClientID string
+	Scopes   []string
+}
+
+// LogoutInput represents logout request data
+type LogoutInput struct {
+	UserID      string
+	AccessToken string
+}
+
+// VerifyMFAInput represents the second step of a login with MFA
+type VerifyMFAInput struct {
+	MFAToken string
+	Code     string
+	ClientIP string
+}
+
+// MFACodeInput represents a request that must be confirmed with a TOTP or recovery code
+type MFACodeInput struct {
+	UserID   string
+	Code     string
+	ClientIP string
+}
+
+// AuthOutput represents authentication response data.
+// When MFARequired is set, no tokens are issued yet and MFAToken must be
+// exchanged through VerifyMFA.
 type AuthOutput struct {
 	UserID               string
 	Email                string
@@ -33,12 +100,38 @@ type AuthOutput struct {
 	RefreshToken         string
 	AccessTokenExpiresAt time.Time
 	CreatedAt            time.Time
+	EmailVerified        bool
+	MFARequired          bool
+	MFAToken             string
+}
+
+// MFAEnrollmentOutput represents a pending TOTP enrollment
+type MFAEnrollmentOutput struct {
+	Secret     string
+	OTPAuthURI string
 }
 
 // TokenVerificationOutput represents token verification result
 type TokenVerificationOutput struct {
-	Valid     bool
-	UserID    string
-	Email     string
-	ExpiresAt time.Time
+	Valid         bool
+	UserID        string
+	Email         string
+	EmailVerified bool
+	Roles         []string
+	ExpiresAt     time.Time
+}
+
+// ServiceTokenOutput represents a service token issued by the client credentials grant
+type ServiceTokenOutput struct {
+	AccessToken string
+	Scopes      []string
+	ExpiresAt   time.Time
+}
+
+// ServiceClientOutput represents a newly registered service client.
+// ClientSecret cannot be retrieved again.
+type ServiceClientOutput struct {
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
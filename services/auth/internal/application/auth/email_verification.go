@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+)
+
+// emailVerificationDuration is how long an email verification token stays valid
+const emailVerificationDuration = 24 * time.Hour
+
+// VerifyEmail marks the email address of a user as verified with a token sent
+// by Register or ResendVerification. Verifying an address twice is not an error.
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.verificationManager.Verify(token, auth.TokenTypeEmailVerification, auth.AudienceAuth)
+	if err != nil {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	// The token only proves ownership of the address it was sent to
+	if user.Email().String() != claims.Email {
+		return pkgerrors.ErrUnauthenticated
+	}
+	if user.EmailVerified() {
+		return nil
+	}
+
+	return s.repo.MarkEmailVerified(ctx, user.ID(), time.Now())
+}
+
+// ResendVerification sends a new email verification token. Like
+// RequestPasswordReset, the result does not tell whether the email belongs to
+// an account or was already verified.
+func (s *Service) ResendVerification(ctx context.Context, input ResendVerificationInput) error {
+	if s.notifier == nil {
+		return pkgerrors.ErrUnavailable
+	}
+
+	email, err := domainauth.NewEmail(input.Email)
+	if err != nil {
+		return pkgerrors.ErrInvalidArgument
+	}
+
+	user, err := s.repo.FindByEmail(ctx, email)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !user.EmailVerified() {
+		s.sendEmailVerification(ctx, user)
+	}
+	return nil
+}
+
+// sendEmailVerification issues an email verification token and delivers it
+// through the notifier. Failures are logged, since the caller has already
+// succeeded from the user's point of view.
+func (s *Service) sendEmailVerification(ctx context.Context, user *domainauth.User) {
+	if s.notifier == nil {
+		return
+	}
+
+	token, claims, err := s.verificationManager.GenerateWithClaims(
+		user.ID(), user.Email().String(), auth.TokenTypeEmailVerification, auth.AudienceAuth,
+	)
+	if err != nil {
+		log.Printf("failed to generate email verification token for user %s: %v", user.ID(), err)
+		return
+	}
+
+	if err := s.notifier.SendEmailVerification(ctx, user.Email(), token, claims.ExpiresAt.Time); err != nil {
+		log.Printf("failed to send email verification for user %s: %v", user.ID(), err)
+	}
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+)
+
+// LoginThrottle configures the brute-force protection of logins.
+// Failures are counted per account and per client IP. After FreeFailures
+// failures each attempt must wait for a delay that doubles with every further
+// failure, and the account or IP is locked for LockoutDuration once it
+// reaches its maximum number of failures.
+type LoginThrottle struct {
+	// MaxAccountFailures is the number of failures that locks an account, or zero to never lock accounts
+	MaxAccountFailures int
+	// MaxIPFailures is the number of failures that locks a client IP, or zero to never lock IPs.
+	// It is higher than MaxAccountFailures since many users may share an IP.
+	MaxIPFailures int
+	// FreeFailures is the number of failures allowed before attempts are delayed
+	FreeFailures int
+	// BaseDelay is the delay after the first failure beyond FreeFailures, or zero for no delays
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts
+	MaxDelay time.Duration
+	// LockoutDuration is how long a lockout lasts before logins are accepted again
+	LockoutDuration time.Duration
+	// FailureWindow is how long failures are remembered
+	FailureWindow time.Duration
+}
+
+// DefaultLoginThrottle returns the brute-force protection used unless
+// WithLoginThrottle is given
+func DefaultLoginThrottle() LoginThrottle {
+	return LoginThrottle{
+		MaxAccountFailures: 10,
+		MaxIPFailures:      100,
+		FreeFailures:       3,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+		LockoutDuration:    15 * time.Minute,
+		FailureWindow:      15 * time.Minute,
+	}
+}
+
+// WithLoginThrottle sets the brute-force protection of logins.
+// The zero LoginThrottle turns it off.
+func WithLoginThrottle(throttle LoginThrottle) Option {
+	return func(s *Service) {
+		s.loginThrottle = throttle
+	}
+}
+
+// delay returns how long to wait after the last of the given number of failures
+func (t LoginThrottle) delay(failures int) time.Duration {
+	if t.BaseDelay <= 0 || failures < t.FreeFailures {
+		return 0
+	}
+
+	delay := t.BaseDelay
+	for i := t.FreeFailures; i < failures; i++ {
+		delay *= 2
+		if t.MaxDelay > 0 && delay >= t.MaxDelay {
+			return t.MaxDelay
+		}
+	}
+	return delay
+}
+
+// throttleKey is a key failures are counted under, with the number of
+// failures that locks it
+type throttleKey struct {
+	key         string
+	maxFailures int
+}
+
+// loginThrottleKeys returns the keys that count failed logins to an account
+// from a client IP. The IP is unknown when the service is called directly.
+func (s *Service) loginThrottleKeys(email domainauth.Email, clientIP string) []throttleKey {
+	keys := []throttleKey{
+		{key: domainauth.AccountThrottleKey(email), maxFailures: s.loginThrottle.MaxAccountFailures},
+	}
+	if clientIP != "" {
+		keys = append(keys, throttleKey{
+			key:         domainauth.IPThrottleKey(clientIP),
+			maxFailures: s.loginThrottle.MaxIPFailures,
+		})
+	}
+	return keys
+}
+
+// checkLoginThrottle rejects an attempt while any of the keys is locked or
+// has to wait after its last failure
+func (s *Service) checkLoginThrottle(ctx context.Context, keys []throttleKey, now time.Time) error {
+	windowStart := now.Add(-s.loginThrottle.FailureWindow)
+
+	for _, k := range keys {
+		attempts, err := s.repo.FindLoginAttempts(ctx, k.key)
+		if errors.Is(err, pkgerrors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if attempts.IsLocked(now) {
+			return pkgerrors.ErrTooManyAttempts
+		}
+
+		delay := s.loginThrottle.delay(attempts.FailuresSince(windowStart))
+		if now.Before(attempts.LastFailureAt().Add(delay)) {
+			return pkgerrors.ErrTooManyAttempts
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt under each key and locks the
+// keys that reached their maximum number of failures
+func (s *Service) recordLoginFailure(ctx context.Context, keys []throttleKey, now time.Time) error {
+	windowStart := now.Add(-s.loginThrottle.FailureWindow)
+
+	for _, k := range keys {
+		attempts, err := s.repo.RecordLoginFailure(ctx, k.key, now, windowStart)
+		if err != nil {
+			return err
+		}
+
+		if k.maxFailures <= 0 || attempts.Failures() < k.maxFailures {
+			continue
+		}
+
+		lockedUntil := now.Add(s.loginThrottle.LockoutDuration)
+		if err := s.repo.LockLogin(ctx, k.key, lockedUntil); err != nil {
+			return err
+		}
+		log.Printf("locked logins for %s until %s after %d failures", k.key, lockedUntil.Format(time.RFC3339), attempts.Failures())
+	}
+
+	return nil
+}
+
+// UnlockAccount lifts the lockout of an account, and of a client IP when one
+// is given, and forgets their failed logins. It is meant for administrators.
+func (s *Service) UnlockAccount(ctx context.Context, input UnlockAccountInput) error {
+	email, err := domainauth.NewEmail(input.Email)
+	if err != nil {
+		return pkgerrors.ErrInvalidArgument
+	}
+
+	if err := s.repo.ResetLoginAttempts(ctx, domainauth.AccountThrottleKey(email)); err != nil {
+		return err
+	}
+
+	if input.ClientIP == "" {
+		return nil
+	}
+	return s.repo.ResetLoginAttempts(ctx, domainauth.IPThrottleKey(input.ClientIP))
+}
@@ -0,0 +1,274 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	// totpIssuer is the account issuer shown in authenticator apps
+	totpIssuer = "kube-ec"
+	// mfaChallengeDuration is how long a user has to enter the second factor after the password
+	mfaChallengeDuration = 5 * time.Minute
+	// recoveryCodeCount is the number of recovery codes generated at once
+	recoveryCodeCount = 10
+)
+
+// EnrollMFA starts a TOTP enrollment by generating a new secret.
+// MFA is not enforced until the enrollment is confirmed with ConfirmMFA.
+func (s *Service) EnrollMFA(ctx context.Context, userID string) (*MFAEnrollmentOutput, error) {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	enabled, err := s.findEnabledMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled != nil {
+		return nil, pkgerrors.ErrFailedPrecondition
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate TOTP secret")
+	}
+
+	// A new enrollment replaces any pending one
+	now := time.Now()
+	if err := s.repo.SaveMFA(ctx, domainauth.NewMFA(userID, secret, nil, 0, now, now)); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollmentOutput{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(totpIssuer, user.Email().String(), secret),
+	}, nil
+}
+
+// ConfirmMFA enables MFA once the user proves the authenticator app was set up
+// and returns the first set of recovery codes
+func (s *Service) ConfirmMFA(ctx context.Context, input MFACodeInput) ([]string, error) {
+	mfa, err := s.repo.FindMFA(ctx, input.UserID)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil, pkgerrors.ErrFailedPrecondition
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, pkgerrors.ErrFailedPrecondition
+	}
+
+	now := time.Now()
+	step, err := auth.ValidateTOTP(mfa.Secret(), input.Code, now)
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	mfa.Confirm(step, now)
+	if err := s.repo.SaveMFA(ctx, mfa); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, input.UserID)
+}
+
+// GenerateRecoveryCodes replaces the recovery codes of a user with MFA.
+// Codes that were issued before stop working.
+func (s *Service) GenerateRecoveryCodes(ctx context.Context, input MFACodeInput) ([]string, error) {
+	if err := s.verifyThrottledSecondFactor(ctx, input); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, input.UserID)
+}
+
+// DisableMFA turns MFA off after verifying a TOTP or recovery code
+func (s *Service) DisableMFA(ctx context.Context, input MFACodeInput) error {
+	if err := s.verifyThrottledSecondFactor(ctx, input); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMFA(ctx, input.UserID)
+}
+
+// verifyThrottledSecondFactor checks a TOTP or recovery code of a user with MFA.
+// Wrong codes count as failed logins, as in VerifyMFA, so a stolen access
+// token cannot be used to guess codes at full speed.
+func (s *Service) verifyThrottledSecondFactor(ctx context.Context, input MFACodeInput) error {
+	user, err := s.repo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return pkgerrors.ErrNotFound
+	}
+
+	now := time.Now()
+	throttleKeys := s.loginThrottleKeys(user.Email(), input.ClientIP)
+	if err := s.checkLoginThrottle(ctx, throttleKeys, now); err != nil {
+		return err
+	}
+
+	if err := s.verifyEnabledSecondFactor(ctx, input.UserID, input.Code); err != nil {
+		if errors.Is(err, pkgerrors.ErrUnauthenticated) {
+			return s.loginFailed(ctx, throttleKeys, now)
+		}
+		return err
+	}
+	return nil
+}
+
+// VerifyMFA completes a login by exchanging an MFA challenge token and a TOTP
+// or recovery code for real tokens. Each challenge token can be used once.
+func (s *Service) VerifyMFA(ctx context.Context, input VerifyMFAInput) (*AuthOutput, error) {
+	claims, err := s.mfaManager.Verify(input.MFAToken, auth.TokenTypeMFAChallenge, auth.AudienceAuth)
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	used, err := s.repo.IsAccessTokenRevoked(ctx, claims.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	// Wrong codes count as failed logins, so codes cannot be guessed at full speed either
+	email, err := domainauth.NewEmail(claims.Email)
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+	now := time.Now()
+	throttleKeys := s.loginThrottleKeys(email, input.ClientIP)
+	if err := s.checkLoginThrottle(ctx, throttleKeys, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyEnabledSecondFactor(ctx, claims.UserID, input.Code); err != nil {
+		if errors.Is(err, pkgerrors.ErrUnauthenticated) {
+			return nil, s.loginFailed(ctx, throttleKeys, now)
+		}
+		return nil, err
+	}
+
+	// The challenge shares the token denylist with access tokens
+	if err := s.repo.RevokeAccessToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ResetLoginAttempts(ctx, domainauth.AccountThrottleKey(email)); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	// Generate tokens, starting a new refresh token family
+	return s.issueTokens(ctx, user, uuid.New().String())
+}
+
+// issueMFAChallenge returns the short-lived token that VerifyMFA exchanges for real tokens
+func (s *Service) issueMFAChallenge(user *domainauth.User) (*AuthOutput, error) {
+	mfaToken, err := s.mfaManager.Generate(
+		user.ID(), user.Email().String(), auth.TokenTypeMFAChallenge, auth.AudienceAuth,
+	)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate MFA challenge")
+	}
+
+	return &AuthOutput{
+		UserID:        user.ID(),
+		Email:         user.Email().String(),
+		Name:          user.Name(),
+		PhoneNumber:   user.PhoneNumber(),
+		CreatedAt:     user.CreatedAt(),
+		EmailVerified: user.EmailVerified(),
+		MFARequired:   true,
+		MFAToken:      mfaToken,
+	}, nil
+}
+
+// findEnabledMFA returns the MFA settings of a user, or nil if MFA is not enabled
+func (s *Service) findEnabledMFA(ctx context.Context, userID string) (*domainauth.MFA, error) {
+	mfa, err := s.repo.FindMFA(ctx, userID)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, nil
+	}
+	return mfa, nil
+}
+
+// verifyEnabledSecondFactor checks a TOTP code, or else a one-time recovery
+// code, of a user with MFA enabled
+func (s *Service) verifyEnabledSecondFactor(ctx context.Context, userID, code string) error {
+	mfa, err := s.findEnabledMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil {
+		return pkgerrors.ErrFailedPrecondition
+	}
+
+	now := time.Now()
+	if step, err := auth.ValidateTOTP(mfa.Secret(), code, now); err == nil {
+		// Each code is accepted only once, even within its validity window
+		err := s.repo.MarkTOTPStepUsed(ctx, userID, step)
+		if errors.Is(err, domainauth.ErrTOTPCodeAlreadyUsed) {
+			return pkgerrors.ErrUnauthenticated
+		}
+		return err
+	}
+
+	err = s.repo.UseRecoveryCode(ctx, userID, domainauth.HashRecoveryCode(code), now)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return pkgerrors.ErrUnauthenticated
+	}
+	return err
+}
+
+// replaceRecoveryCodes generates new recovery codes and stores their hashes
+func (s *Service) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate recovery code")
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, domainauth.HashRecoveryCode(code))
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// newRecoveryCode generates a random 40-bit code formatted as xxxx-xxxx
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+	return code[:4] + "-" + code[4:], nil
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+	"github.com/google/uuid"
+)
+
+// passwordResetDuration is how long a password reset token stays valid
+const passwordResetDuration = 30 * time.Minute
+
+// RequestPasswordReset issues a password reset token and delivers it to the
+// user through the notifier. The result is the same whether or not the email
+// belongs to an account, so the response cannot be used to discover accounts.
+func (s *Service) RequestPasswordReset(ctx context.Context, input RequestPasswordResetInput) error {
+	if s.notifier == nil {
+		return pkgerrors.ErrUnavailable
+	}
+
+	email, err := domainauth.NewEmail(input.Email)
+	if err != nil {
+		return pkgerrors.ErrInvalidArgument
+	}
+
+	user, err := s.repo.FindByEmail(ctx, email)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := newPasswordResetToken()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate password reset token")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(passwordResetDuration)
+	resetToken := domainauth.NewPasswordResetToken(
+		uuid.New().String(),
+		user.ID(),
+		domainauth.HashToken(token),
+		expiresAt,
+		nil,
+		now,
+	)
+	if err := s.repo.CreatePasswordResetToken(ctx, resetToken); err != nil {
+		return err
+	}
+
+	// Delivery failures are not reported to the caller, since they would only
+	// happen for existing accounts
+	if err := s.notifier.SendPasswordReset(ctx, user.Email(), token, expiresAt); err != nil {
+		log.Printf("failed to send password reset for user %s: %v", user.ID(), err)
+	}
+
+	return nil
+}
+
+// ConfirmPasswordReset sets a new password with a password reset token.
+// The token can be used once, and every session of the user is revoked.
+func (s *Service) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	stored, err := s.repo.FindPasswordResetTokenByHash(ctx, domainauth.HashToken(input.Token))
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return pkgerrors.ErrUnauthenticated
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if stored.IsUsed() || stored.IsExpired(now) {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	// Validate the new password before consuming the token so the user can retry
+	password, err := domainauth.NewPassword(input.NewPassword)
+	if err != nil {
+		return pkgerrors.ErrInvalidArgument
+	}
+
+	if err := s.repo.MarkPasswordResetTokenUsed(ctx, stored.ID(), now); err != nil {
+		if errors.Is(err, domainauth.ErrPasswordResetTokenAlreadyUsed) {
+			return pkgerrors.ErrUnauthenticated
+		}
+		return err
+	}
+
+	if err := s.repo.UpdatePassword(ctx, stored.UserID(), password); err != nil {
+		return err
+	}
+
+	// Other reset links sent before must not work after the password changed
+	if err := s.repo.InvalidateUserPasswordResetTokens(ctx, stored.UserID(), now); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password must lose access
+	return s.LogoutAll(ctx, stored.UserID())
+}
+
+// newPasswordResetToken generates a random 256-bit token
+func newPasswordResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Riku-KANO/kube-ec/pkg/auth"
@@ -12,25 +13,88 @@ import (
 
 // Service handles authentication business logic
 type Service struct {
-	repo            domainauth.Repository
-	jwtSecret       string
-	accessDuration  time.Duration
-	refreshDuration time.Duration
+	repo                domainauth.Repository
+	accessManager       *auth.JWTManager
+	refreshManager      *auth.JWTManager
+	mfaManager          *auth.JWTManager
+	verificationManager *auth.JWTManager
+	serviceManager      *auth.JWTManager
+	keyRing             *auth.KeyRing
+	notifier            domainauth.Notifier
+	verificationPolicy  auth.EmailVerificationPolicy
+	loginThrottle       LoginThrottle
+	accessDuration      time.Duration
+	refreshDuration     time.Duration
 }
 
-// NewService creates a new authentication service
+// Option configures optional dependencies of the Service
+type Option func(*Service)
+
+// WithNotifier sets the notifier that delivers password reset and email verification tokens
+func WithNotifier(notifier domainauth.Notifier) Option {
+	return func(s *Service) {
+		s.notifier = notifier
+	}
+}
+
+// WithEmailVerificationPolicy sets which actions require a verified email address.
+// The default policy never requires one.
+func WithEmailVerificationPolicy(policy auth.EmailVerificationPolicy) Option {
+	return func(s *Service) {
+		s.verificationPolicy = policy
+	}
+}
+
+// NewService creates a new authentication service that signs tokens with a shared HS256 secret
 func NewService(
 	repo domainauth.Repository,
 	jwtSecret string,
 	accessDuration time.Duration,
 	refreshDuration time.Duration,
+	opts ...Option,
 ) *Service {
-	return &Service{
-		repo:            repo,
-		jwtSecret:       jwtSecret,
-		accessDuration:  accessDuration,
-		refreshDuration: refreshDuration,
+	return newService(&Service{
+		repo:                repo,
+		accessManager:       auth.NewJWTManager(jwtSecret, auth.DefaultIssuer, accessDuration),
+		refreshManager:      auth.NewJWTManager(jwtSecret, auth.DefaultIssuer, refreshDuration),
+		mfaManager:          auth.NewJWTManager(jwtSecret, auth.DefaultIssuer, mfaChallengeDuration),
+		verificationManager: auth.NewJWTManager(jwtSecret, auth.DefaultIssuer, emailVerificationDuration),
+		serviceManager:      auth.NewJWTManager(jwtSecret, auth.DefaultIssuer, serviceTokenDuration),
+		accessDuration:      accessDuration,
+		refreshDuration:     refreshDuration,
+	}, opts)
+}
+
+// NewServiceWithKeyRing creates a new authentication service that signs tokens
+// with the active key of an asymmetric key ring
+func NewServiceWithKeyRing(
+	repo domainauth.Repository,
+	keyRing *auth.KeyRing,
+	accessDuration time.Duration,
+	refreshDuration time.Duration,
+	opts ...Option,
+) *Service {
+	return newService(&Service{
+		repo:                repo,
+		accessManager:       auth.NewJWTManagerWithKeyRing(keyRing, auth.DefaultIssuer, accessDuration),
+		refreshManager:      auth.NewJWTManagerWithKeyRing(keyRing, auth.DefaultIssuer, refreshDuration),
+		mfaManager:          auth.NewJWTManagerWithKeyRing(keyRing, auth.DefaultIssuer, mfaChallengeDuration),
+		verificationManager: auth.NewJWTManagerWithKeyRing(keyRing, auth.DefaultIssuer, emailVerificationDuration),
+		serviceManager:      auth.NewJWTManagerWithKeyRing(keyRing, auth.DefaultIssuer, serviceTokenDuration),
+		keyRing:             keyRing,
+		accessDuration:      accessDuration,
+		refreshDuration:     refreshDuration,
+	}, opts)
+}
+
+// newService applies the options to a service
+func newService(s *Service, opts []Option) *Service {
+	s.verificationPolicy = auth.EmailVerificationOptional
+	s.loginThrottle = DefaultLoginThrottle()
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Register creates a new user account
@@ -60,6 +124,7 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*AuthOutpu
 		password,
 		input.Name,
 		input.PhoneNumber,
+		false,
 		now,
 		now,
 	)
@@ -69,29 +134,33 @@ func (s *Service) Register(ctx context.Context, input RegisterInput) (*AuthOutpu
 		return nil, err
 	}
 
-	// Generate tokens
-	accessManager := auth.NewJWTManager(s.jwtSecret, s.accessDuration)
-	accessToken, err := accessManager.Generate(user.ID(), user.Email().String())
+	customer, err := domainauth.NewRole(auth.RoleCustomer)
 	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate access token")
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, err.Error())
+	}
+	if err := s.repo.AddUserRole(ctx, user.ID(), customer, now); err != nil {
+		return nil, err
 	}
 
-	refreshManager := auth.NewJWTManager(s.jwtSecret, s.refreshDuration)
-	refreshToken, err := refreshManager.Generate(user.ID(), user.Email().String())
-	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate refresh token")
+	// A failed delivery must not fail the registration. The user can ask for
+	// the email again with ResendVerification.
+	s.sendEmailVerification(ctx, user)
+
+	// When verification is required for login, the account is not usable
+	// until the email is verified, so no tokens are issued yet
+	if s.verificationPolicy.RequiredForLogin() {
+		return &AuthOutput{
+			UserID:        user.ID(),
+			Email:         user.Email().String(),
+			Name:          user.Name(),
+			PhoneNumber:   user.PhoneNumber(),
+			CreatedAt:     user.CreatedAt(),
+			EmailVerified: user.EmailVerified(),
+		}, nil
 	}
 
-	return &AuthOutput{
-		UserID:               user.ID(),
-		Email:                user.Email().String(),
-		Name:                 user.Name(),
-		PhoneNumber:          user.PhoneNumber(),
-		AccessToken:          accessToken,
-		RefreshToken:         refreshToken,
-		AccessTokenExpiresAt: time.Now().Add(s.accessDuration),
-		CreatedAt:            user.CreatedAt(),
-	}, nil
+	// Generate tokens, starting a new refresh token family
+	return s.issueTokens(ctx, user, uuid.New().String())
 }
 
 // Login authenticates a user
@@ -102,94 +171,158 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (*AuthOutput, err
 		return nil, pkgerrors.Wrap(pkgerrors.ErrInvalidArgument, err.Error())
 	}
 
+	// Throttle before looking at the password, so rejected guesses cost no hashing
+	now := time.Now()
+	throttleKeys := s.loginThrottleKeys(email, input.ClientIP)
+	if err := s.checkLoginThrottle(ctx, throttleKeys, now); err != nil {
+		return nil, err
+	}
+
 	// Find user by email
 	user, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, pkgerrors.ErrUnauthenticated
+		return nil, s.loginFailed(ctx, throttleKeys, now)
 	}
 
 	// Verify password
 	if err := user.VerifyPassword(input.Password); err != nil {
-		return nil, pkgerrors.ErrUnauthenticated
+		return nil, s.loginFailed(ctx, throttleKeys, now)
 	}
 
-	// Generate tokens
-	accessManager := auth.NewJWTManager(s.jwtSecret, s.accessDuration)
-	accessToken, err := accessManager.Generate(user.ID(), user.Email().String())
-	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate access token")
+	// Checked after the password so the response does not reveal whether an account exists
+	if s.verificationPolicy.RequiredForLogin() && !user.EmailVerified() {
+		return nil, pkgerrors.ErrEmailNotVerified
 	}
 
-	refreshManager := auth.NewJWTManager(s.jwtSecret, s.refreshDuration)
-	refreshToken, err := refreshManager.Generate(user.ID(), user.Email().String())
+	// Users with MFA must complete the login through VerifyMFA
+	mfa, err := s.findEnabledMFA(ctx, user.ID())
 	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate refresh token")
+		return nil, err
+	}
+	if mfa != nil {
+		// Failures are kept until the second factor is verified too
+		return s.issueMFAChallenge(user)
 	}
 
-	return &AuthOutput{
-		UserID:               user.ID(),
-		Email:                user.Email().String(),
-		Name:                 user.Name(),
-		PhoneNumber:          user.PhoneNumber(),
-		AccessToken:          accessToken,
-		RefreshToken:         refreshToken,
-		AccessTokenExpiresAt: time.Now().Add(s.accessDuration),
-		CreatedAt:            user.CreatedAt(),
-	}, nil
+	if err := s.repo.ResetLoginAttempts(ctx, domainauth.AccountThrottleKey(email)); err != nil {
+		return nil, err
+	}
+
+	// Generate tokens, starting a new refresh token family
+	return s.issueTokens(ctx, user, uuid.New().String())
+}
+
+// loginFailed counts a failed login and returns the error to report to the caller
+func (s *Service) loginFailed(ctx context.Context, throttleKeys []throttleKey, now time.Time) error {
+	if err := s.recordLoginFailure(ctx, throttleKeys, now); err != nil {
+		return err
+	}
+	return pkgerrors.ErrUnauthenticated
 }
 
 // VerifyToken validates a JWT token
 func (s *Service) VerifyToken(ctx context.Context, token string) (*TokenVerificationOutput, error) {
-	jwtManager := auth.NewJWTManager(s.jwtSecret, s.accessDuration)
-	claims, err := jwtManager.Verify(token)
+	claims, err := s.accessManager.Verify(token, auth.TokenTypeAccess, auth.AudienceAPI)
 	if err != nil {
 		return &TokenVerificationOutput{Valid: false}, nil
 	}
 
+	user, err := s.checkAccessToken(ctx, claims)
+	if errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		return &TokenVerificationOutput{Valid: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The verification state is read from the account rather than the token,
+	// so it takes effect without waiting for the next token refresh
 	return &TokenVerificationOutput{
-		Valid:     true,
-		UserID:    claims.UserID,
-		Email:     claims.Email,
-		ExpiresAt: claims.ExpiresAt.Time,
+		Valid:         true,
+		UserID:        claims.UserID,
+		Email:         claims.Email,
+		EmailVerified: user.EmailVerified(),
+		Roles:         claims.Roles,
+		ExpiresAt:     claims.ExpiresAt.Time,
 	}, nil
 }
 
-// RefreshToken generates new tokens using a refresh token
-func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*AuthOutput, error) {
-	refreshManager := auth.NewJWTManager(s.jwtSecret, s.refreshDuration)
-	claims, err := refreshManager.Verify(refreshToken)
+// checkAccessToken checks that a verified access token was neither logged out
+// nor revoked and that its user still exists, and returns the user.
+// The roles in claims are replaced with the roles the user has now, so roles
+// granted or revoked since the token was issued apply.
+// Tokens that can no longer be used return pkgerrors.ErrUnauthenticated.
+func (s *Service) checkAccessToken(ctx context.Context, claims *auth.Claims) (*domainauth.User, error) {
+	// Reject tokens that were logged out or whose session was revoked
+	revoked, err := s.isAccessTokenRevoked(ctx, claims.ID)
 	if err != nil {
+		return nil, err
+	}
+	if revoked {
 		return nil, pkgerrors.ErrUnauthenticated
 	}
 
-	// Get user to ensure they still exist
 	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.userRoles(ctx, user.ID())
 	if err != nil {
+		return nil, err
+	}
+	claims.Roles = roles
+
+	return user, nil
+}
+
+// RefreshToken rotates a refresh token and generates new tokens.
+// Each refresh token can be exchanged only once. Presenting a token that was
+// already exchanged means it has leaked, so its whole family is revoked.
+func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*AuthOutput, error) {
+	if _, err := s.refreshManager.Verify(refreshToken, auth.TokenTypeRefresh, auth.AudienceAuth); err != nil {
 		return nil, pkgerrors.ErrUnauthenticated
 	}
 
-	// Generate new tokens
-	accessManager := auth.NewJWTManager(s.jwtSecret, s.accessDuration)
-	accessToken, err := accessManager.Generate(user.ID(), user.Email().String())
+	// Look up the stored token to make sure it was issued by us and is still usable
+	stored, err := s.repo.FindRefreshTokenByHash(ctx, domainauth.HashToken(refreshToken))
 	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate access token")
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	now := time.Now()
+	if stored.IsUsed() || stored.IsRevoked() {
+		return nil, s.revokeFamily(ctx, stored.FamilyID(), now)
+	}
+	if stored.IsExpired(now) {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	if err := s.repo.MarkRefreshTokenUsed(ctx, stored.ID(), now); err != nil {
+		if errors.Is(err, domainauth.ErrRefreshTokenAlreadyUsed) {
+			// Lost a race against another exchange of the same token
+			return nil, s.revokeFamily(ctx, stored.FamilyID(), now)
+		}
+		return nil, err
 	}
 
-	newRefreshToken, err := refreshManager.Generate(user.ID(), user.Email().String())
+	// Get user to ensure they still exist
+	user, err := s.repo.FindByID(ctx, stored.UserID())
 	if err != nil {
-		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate refresh token")
+		return nil, pkgerrors.ErrUnauthenticated
 	}
 
-	return &AuthOutput{
-		UserID:               user.ID(),
-		Email:                user.Email().String(),
-		Name:                 user.Name(),
-		PhoneNumber:          user.PhoneNumber(),
-		AccessToken:          accessToken,
-		RefreshToken:         newRefreshToken,
-		AccessTokenExpiresAt: time.Now().Add(s.accessDuration),
-		CreatedAt:            user.CreatedAt(),
-	}, nil
+	// Tokens issued before verification became required for login must not
+	// keep an unverified account signed in
+	if s.verificationPolicy.RequiredForLogin() && !user.EmailVerified() {
+		return nil, pkgerrors.ErrEmailNotVerified
+	}
+
+	// Generate new tokens in the same family
+	return s.issueTokens(ctx, user, stored.FamilyID())
 }
 
 // ChangePassword changes a user's password
@@ -210,5 +343,129 @@ func (s *Service) ChangePassword(ctx context.Context, input ChangePasswordInput)
 		return err
 	}
 
-	return nil
+	// Sessions opened with the old password must not survive the change
+	return s.LogoutAll(ctx, user.ID())
+}
+
+// PublicKeys returns the public keys that verify tokens issued by the service.
+// The set is empty when tokens are signed with a shared secret, which must never be published.
+func (s *Service) PublicKeys() auth.JWKS {
+	if s.keyRing == nil {
+		return auth.JWKS{Keys: []auth.JWK{}}
+	}
+	return s.keyRing.JWKS()
+}
+
+// Logout revokes the given access token and the refresh token family it belongs to
+func (s *Service) Logout(ctx context.Context, input LogoutInput) error {
+	claims, err := s.accessManager.Verify(input.AccessToken, auth.TokenTypeAccess, auth.AudienceAPI)
+	if err != nil {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	if claims.UserID != input.UserID {
+		return pkgerrors.ErrPermissionDenied
+	}
+
+	// Deny the access token for the rest of its lifetime
+	if err := s.repo.RevokeAccessToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	// Revoke the refresh tokens of the same session
+	session, err := s.repo.FindRefreshTokenByAccessTokenID(ctx, claims.ID)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RevokeRefreshTokenFamily(ctx, session.FamilyID(), time.Now())
+}
+
+// LogoutAll invalidates every session of a user.
+// Access tokens are rejected by VerifyToken once their refresh token family is revoked.
+func (s *Service) LogoutAll(ctx context.Context, userID string) error {
+	return s.repo.RevokeUserRefreshTokens(ctx, userID, time.Now())
+}
+
+// issueTokens generates an access token and a refresh token for the user and
+// stores the refresh token in the given family
+func (s *Service) issueTokens(ctx context.Context, user *domainauth.User, familyID string) (*AuthOutput, error) {
+	// Access tokens carry the roles so services can authorize calls offline
+	roles, err := s.userRoles(ctx, user.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, accessClaims, err := s.accessManager.GenerateWithRoles(
+		user.ID(), user.Email().String(), roles, auth.TokenTypeAccess, auth.AudienceAPI,
+	)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate access token")
+	}
+
+	refreshToken, err := s.refreshManager.Generate(
+		user.ID(), user.Email().String(), auth.TokenTypeRefresh, auth.AudienceAuth,
+	)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "failed to generate refresh token")
+	}
+
+	now := time.Now()
+	storedToken := domainauth.NewRefreshToken(
+		uuid.New().String(),
+		user.ID(),
+		familyID,
+		domainauth.HashToken(refreshToken),
+		accessClaims.ID,
+		now.Add(s.refreshDuration),
+		nil,
+		nil,
+		now,
+	)
+	if err := s.repo.CreateRefreshToken(ctx, storedToken); err != nil {
+		return nil, err
+	}
+
+	return &AuthOutput{
+		UserID:               user.ID(),
+		Email:                user.Email().String(),
+		Name:                 user.Name(),
+		PhoneNumber:          user.PhoneNumber(),
+		AccessToken:          accessToken,
+		RefreshToken:         refreshToken,
+		AccessTokenExpiresAt: now.Add(s.accessDuration),
+		CreatedAt:            user.CreatedAt(),
+		EmailVerified:        user.EmailVerified(),
+	}, nil
+}
+
+// revokeFamily revokes a refresh token family after reuse was detected and
+// returns the error to report to the caller
+func (s *Service) revokeFamily(ctx context.Context, familyID string, now time.Time) error {
+	if err := s.repo.RevokeRefreshTokenFamily(ctx, familyID, now); err != nil {
+		return err
+	}
+	return pkgerrors.ErrUnauthenticated
+}
+
+// isAccessTokenRevoked reports whether an access token was denylisted or
+// belongs to a session whose refresh token family was revoked
+func (s *Service) isAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	revoked, err := s.repo.IsAccessTokenRevoked(ctx, tokenID, time.Now())
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	session, err := s.repo.FindRefreshTokenByAccessTokenID(ctx, tokenID)
+	if errors.Is(err, pkgerrors.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return session.IsRevoked(), nil
 }
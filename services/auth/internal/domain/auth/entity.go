@@ -6,13 +6,14 @@ import (
 
 // User represents an authenticated user entity
 type User struct {
-	id          string
-	email       Email
-	password    Password
-	name        string
-	phoneNumber string
-	createdAt   time.Time
-	updatedAt   time.Time
+	id            string
+	email         Email
+	password      Password
+	name          string
+	phoneNumber   string
+	emailVerified bool
+	createdAt     time.Time
+	updatedAt     time.Time
 }
 
 // NewUser creates a new User entity
@@ -22,17 +23,19 @@ func NewUser(
 	password Password,
 	name string,
 	phoneNumber string,
+	emailVerified bool,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) *User {
 	return &User{
-		id:          id,
-		email:       email,
-		password:    password,
-		name:        name,
-		phoneNumber: phoneNumber,
-		createdAt:   createdAt,
-		updatedAt:   updatedAt,
+		id:            id,
+		email:         email,
+		password:      password,
+		name:          name,
+		phoneNumber:   phoneNumber,
+		emailVerified: emailVerified,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
 	}
 }
 
@@ -61,6 +64,11 @@ func (u *User) PhoneNumber() string {
 	return u.phoneNumber
 }
 
+// EmailVerified reports whether the user proved ownership of the email address
+func (u *User) EmailVerified() bool {
+	return u.emailVerified
+}
+
 // CreatedAt returns the creation time
 func (u *User) CreatedAt() time.Time {
 	return u.createdAt
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"time"
+)
+
+// LoginAttempts represents the failed logins recorded for a throttle key.
+// Failures are counted per account and per client IP, and a key is locked for
+// a while once too many of them happen in a row.
+type LoginAttempts struct {
+	key           string
+	failures      int
+	lastFailureAt time.Time
+	lockedUntil   *time.Time
+}
+
+// NewLoginAttempts creates a new LoginAttempts entity
+func NewLoginAttempts(
+	key string,
+	failures int,
+	lastFailureAt time.Time,
+	lockedUntil *time.Time,
+) *LoginAttempts {
+	return &LoginAttempts{
+		key:           key,
+		failures:      failures,
+		lastFailureAt: lastFailureAt,
+		lockedUntil:   lockedUntil,
+	}
+}
+
+// AccountThrottleKey returns the throttle key counting failed logins to an account.
+// Unknown emails are counted too, so lockouts do not reveal which accounts exist.
+func AccountThrottleKey(email Email) string {
+	return "account:" + email.String()
+}
+
+// IPThrottleKey returns the throttle key counting failed logins from a client IP
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Key returns the throttle key
+func (a *LoginAttempts) Key() string {
+	return a.key
+}
+
+// Failures returns the number of failures since the key was last reset or locked
+func (a *LoginAttempts) Failures() int {
+	return a.failures
+}
+
+// LastFailureAt returns the time of the last failure
+func (a *LoginAttempts) LastFailureAt() time.Time {
+	return a.lastFailureAt
+}
+
+// LockedUntil returns the end of the lockout, or nil if the key was never locked
+func (a *LoginAttempts) LockedUntil() *time.Time {
+	return a.lockedUntil
+}
+
+// IsLocked reports whether logins are rejected at the given time
+func (a *LoginAttempts) IsLocked(now time.Time) bool {
+	return a.lockedUntil != nil && now.Before(*a.lockedUntil)
+}
+
+// FailuresSince returns the number of failures, or zero if the last one
+// happened before windowStart
+func (a *LoginAttempts) FailuresSince(windowStart time.Time) int {
+	if a.lastFailureAt.Before(windowStart) {
+		return 0
+	}
+	return a.failures
+}
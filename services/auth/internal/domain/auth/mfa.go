@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrTOTPCodeAlreadyUsed is returned when a TOTP code of a time step that was
+// already accepted is presented again
+var ErrTOTPCodeAlreadyUsed = errors.New("totp code already used")
+
+// MFA represents the TOTP multi-factor authentication settings of a user.
+// Enrollment stores the secret, and MFA is only enforced once the user proves
+// possession of it by confirming a code.
+type MFA struct {
+	userID       string
+	secret       string
+	confirmedAt  *time.Time
+	lastUsedStep int64
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+// NewMFA creates a new MFA entity
+func NewMFA(
+	userID string,
+	secret string,
+	confirmedAt *time.Time,
+	lastUsedStep int64,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *MFA {
+	return &MFA{
+		userID:       userID,
+		secret:       secret,
+		confirmedAt:  confirmedAt,
+		lastUsedStep: lastUsedStep,
+		createdAt:    createdAt,
+		updatedAt:    updatedAt,
+	}
+}
+
+// UserID returns the ID of the user
+func (m *MFA) UserID() string {
+	return m.userID
+}
+
+// Secret returns the base32 encoded TOTP secret
+func (m *MFA) Secret() string {
+	return m.secret
+}
+
+// ConfirmedAt returns the time enrollment was confirmed, or nil while pending
+func (m *MFA) ConfirmedAt() *time.Time {
+	return m.confirmedAt
+}
+
+// LastUsedStep returns the TOTP time step of the last accepted code
+func (m *MFA) LastUsedStep() int64 {
+	return m.lastUsedStep
+}
+
+// CreatedAt returns the creation time
+func (m *MFA) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+// UpdatedAt returns the last update time
+func (m *MFA) UpdatedAt() time.Time {
+	return m.updatedAt
+}
+
+// IsEnabled reports whether enrollment was confirmed and MFA is enforced
+func (m *MFA) IsEnabled() bool {
+	return m.confirmedAt != nil
+}
+
+// Confirm enables MFA, recording the time step of the code used to confirm it
+func (m *MFA) Confirm(step int64, now time.Time) {
+	m.confirmedAt = &now
+	m.lastUsedStep = step
+	m.updatedAt = now
+}
+
+// HashRecoveryCode returns the hash of a recovery code.
+// Codes are normalized first so they can be entered with or without the
+// separator and in any case.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return HashToken(normalized)
+}
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Notifier delivers messages that carry secrets to users out of band,
+// such as by email
+type Notifier interface {
+	// SendPasswordReset delivers a password reset token
+	SendPasswordReset(ctx context.Context, email Email, token string, expiresAt time.Time) error
+
+	// SendEmailVerification delivers a token that verifies the email address
+	SendEmailVerification(ctx context.Context, email Email, token string, expiresAt time.Time) error
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPasswordResetTokenAlreadyUsed is returned when a password reset token has
+// already been used or invalidated
+var ErrPasswordResetTokenAlreadyUsed = errors.New("password reset token already used")
+
+// PasswordResetToken represents a persisted password reset token.
+// Only the hash of the token is stored, and each token can be used once.
+type PasswordResetToken struct {
+	id        string
+	userID    string
+	tokenHash string
+	expiresAt time.Time
+	usedAt    *time.Time
+	createdAt time.Time
+}
+
+// NewPasswordResetToken creates a new PasswordResetToken entity
+func NewPasswordResetToken(
+	id string,
+	userID string,
+	tokenHash string,
+	expiresAt time.Time,
+	usedAt *time.Time,
+	createdAt time.Time,
+) *PasswordResetToken {
+	return &PasswordResetToken{
+		id:        id,
+		userID:    userID,
+		tokenHash: tokenHash,
+		expiresAt: expiresAt,
+		usedAt:    usedAt,
+		createdAt: createdAt,
+	}
+}
+
+// ID returns the password reset token ID
+func (t *PasswordResetToken) ID() string {
+	return t.id
+}
+
+// UserID returns the ID of the user the token was issued to
+func (t *PasswordResetToken) UserID() string {
+	return t.userID
+}
+
+// TokenHash returns the hash of the token
+func (t *PasswordResetToken) TokenHash() string {
+	return t.tokenHash
+}
+
+// ExpiresAt returns the expiration time
+func (t *PasswordResetToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns the time the token was used, or nil if unused
+func (t *PasswordResetToken) UsedAt() *time.Time {
+	return t.usedAt
+}
+
+// CreatedAt returns the creation time
+func (t *PasswordResetToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// IsUsed reports whether the token has already been used or invalidated
+func (t *PasswordResetToken) IsUsed() bool {
+	return t.usedAt != nil
+}
+
+// IsExpired reports whether the token is expired at the given time
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
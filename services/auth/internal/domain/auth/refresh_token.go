@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrRefreshTokenAlreadyUsed is returned when a refresh token has already been
+// exchanged or revoked
+var ErrRefreshTokenAlreadyUsed = errors.New("refresh token already used")
+
+// RefreshToken represents a persisted refresh token.
+// Only the hash of the token is stored. Tokens issued by rotation share the
+// family ID of the login that started the chain, so the whole chain can be
+// revoked at once when reuse is detected. The ID of the access token issued
+// together with the refresh token links access tokens to their family.
+type RefreshToken struct {
+	id            string
+	userID        string
+	familyID      string
+	tokenHash     string
+	accessTokenID string
+	expiresAt     time.Time
+	usedAt        *time.Time
+	revokedAt     *time.Time
+	createdAt     time.Time
+}
+
+// NewRefreshToken creates a new RefreshToken entity
+func NewRefreshToken(
+	id string,
+	userID string,
+	familyID string,
+	tokenHash string,
+	accessTokenID string,
+	expiresAt time.Time,
+	usedAt *time.Time,
+	revokedAt *time.Time,
+	createdAt time.Time,
+) *RefreshToken {
+	return &RefreshToken{
+		id:            id,
+		userID:        userID,
+		familyID:      familyID,
+		tokenHash:     tokenHash,
+		accessTokenID: accessTokenID,
+		expiresAt:     expiresAt,
+		usedAt:        usedAt,
+		revokedAt:     revokedAt,
+		createdAt:     createdAt,
+	}
+}
+
+// ID returns the refresh token ID
+func (t *RefreshToken) ID() string {
+	return t.id
+}
+
+// UserID returns the ID of the user the token was issued to
+func (t *RefreshToken) UserID() string {
+	return t.userID
+}
+
+// FamilyID returns the ID of the rotation chain the token belongs to
+func (t *RefreshToken) FamilyID() string {
+	return t.familyID
+}
+
+// TokenHash returns the hash of the token
+func (t *RefreshToken) TokenHash() string {
+	return t.tokenHash
+}
+
+// AccessTokenID returns the ID (jti) of the access token issued with the token
+func (t *RefreshToken) AccessTokenID() string {
+	return t.accessTokenID
+}
+
+// ExpiresAt returns the expiration time
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// UsedAt returns the time the token was exchanged, or nil if unused
+func (t *RefreshToken) UsedAt() *time.Time {
+	return t.usedAt
+}
+
+// RevokedAt returns the time the token was revoked, or nil if not revoked
+func (t *RefreshToken) RevokedAt() *time.Time {
+	return t.revokedAt
+}
+
+// CreatedAt returns the creation time
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// IsUsed reports whether the token has already been exchanged
+func (t *RefreshToken) IsUsed() bool {
+	return t.usedAt != nil
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.revokedAt != nil
+}
+
+// IsExpired reports whether the token is expired at the given time
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 )
 
 // Repository defines the interface for authentication data operations
@@ -17,4 +18,96 @@ type Repository interface {
 
 	// UpdatePassword updates a user's password
 	UpdatePassword(ctx context.Context, userID string, password Password) error
+
+	// MarkEmailVerified records that a user verified their email address
+	MarkEmailVerified(ctx context.Context, userID string, verifiedAt time.Time) error
+
+	// FindUserRoles retrieves the roles granted to a user, sorted by name
+	FindUserRoles(ctx context.Context, userID string) ([]Role, error)
+
+	// AddUserRole grants a role to a user. Granting a role the user already has does nothing.
+	AddUserRole(ctx context.Context, userID string, role Role, grantedAt time.Time) error
+
+	// RemoveUserRole revokes a role from a user.
+	// It returns ErrNotFound if the user does not have the role.
+	RemoveUserRole(ctx context.Context, userID string, role Role) error
+
+	// CreateServiceClient registers a service client.
+	// It returns ErrAlreadyExists if the client ID is taken.
+	CreateServiceClient(ctx context.Context, client *ServiceClient) error
+
+	// FindServiceClient retrieves a service client by ID
+	FindServiceClient(ctx context.Context, id string) (*ServiceClient, error)
+
+	// CreateRefreshToken stores a newly issued refresh token
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+
+	// FindRefreshTokenByHash retrieves a refresh token by its hash
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// MarkRefreshTokenUsed marks a refresh token as exchanged.
+	// It returns ErrRefreshTokenAlreadyUsed if the token was already used or revoked.
+	MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error
+
+	// FindRefreshTokenByAccessTokenID retrieves the refresh token issued together
+	// with the given access token
+	FindRefreshTokenByAccessTokenID(ctx context.Context, accessTokenID string) (*RefreshToken, error)
+
+	// RevokeRefreshTokenFamily revokes every refresh token in a family
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+
+	// RevokeUserRefreshTokens revokes every refresh token of a user
+	RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error
+
+	// RevokeAccessToken adds an access token ID to the denylist until the token expires
+	RevokeAccessToken(ctx context.Context, tokenID string, userID string, expiresAt time.Time) error
+
+	// IsAccessTokenRevoked reports whether an access token ID is on the denylist
+	IsAccessTokenRevoked(ctx context.Context, tokenID string, now time.Time) (bool, error)
+
+	// SaveMFA creates or replaces the MFA settings of a user
+	SaveMFA(ctx context.Context, mfa *MFA) error
+
+	// FindMFA retrieves the MFA settings of a user
+	FindMFA(ctx context.Context, userID string) (*MFA, error)
+
+	// DeleteMFA removes the MFA settings and recovery codes of a user
+	DeleteMFA(ctx context.Context, userID string) error
+
+	// MarkTOTPStepUsed records the time step of an accepted TOTP code.
+	// It returns ErrTOTPCodeAlreadyUsed unless the step is newer than the last one used.
+	MarkTOTPStepUsed(ctx context.Context, userID string, step int64) error
+
+	// ReplaceRecoveryCodes replaces every recovery code of a user with the given hashes
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error
+
+	// UseRecoveryCode marks an unused recovery code as used.
+	// It returns ErrNotFound if the user has no unused code with the given hash.
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) error
+
+	// CreatePasswordResetToken stores a newly issued password reset token
+	CreatePasswordResetToken(ctx context.Context, token *PasswordResetToken) error
+
+	// FindPasswordResetTokenByHash retrieves a password reset token by its hash
+	FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+
+	// MarkPasswordResetTokenUsed marks a password reset token as used.
+	// It returns ErrPasswordResetTokenAlreadyUsed if the token was already used.
+	MarkPasswordResetTokenUsed(ctx context.Context, id string, usedAt time.Time) error
+
+	// InvalidateUserPasswordResetTokens marks every unused password reset token of a user as used
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID string, usedAt time.Time) error
+
+	// FindLoginAttempts returns the failed logins recorded for a throttle key
+	FindLoginAttempts(ctx context.Context, key string) (*LoginAttempts, error)
+
+	// RecordLoginFailure counts a failed login for a throttle key and returns the
+	// updated attempts. Failures before windowStart are forgotten.
+	RecordLoginFailure(ctx context.Context, key string, failedAt time.Time, windowStart time.Time) (*LoginAttempts, error)
+
+	// LockLogin rejects logins for a throttle key until the given time and clears its failure count
+	LockLogin(ctx context.Context, key string, until time.Time) error
+
+	// ResetLoginAttempts clears the failures and the lock of a throttle key
+	ResetLoginAttempts(ctx context.Context, key string) error
 }
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Role represents a role granted to a user.
+// Besides the built-in customer, staff and admin roles, deployments may grant
+// custom roles that their permission tables refer to.
+type Role struct {
+	value string
+}
+
+var roleRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// NewRole creates a new Role value object with validation
+func NewRole(role string) (Role, error) {
+	role = strings.TrimSpace(strings.ToLower(role))
+
+	if role == "" {
+		return Role{}, fmt.Errorf("role cannot be empty")
+	}
+
+	if !roleRegex.MatchString(role) {
+		return Role{}, fmt.Errorf("invalid role format")
+	}
+
+	return Role{value: role}, nil
+}
+
+// String returns the role as a string
+func (r Role) String() string {
+	return r.value
+}
+
+// RoleStrings converts roles to their string values
+func RoleStrings(roles []Role) []string {
+	values := make([]string, len(roles))
+	for i, role := range roles {
+		values[i] = role.String()
+	}
+	return values
+}
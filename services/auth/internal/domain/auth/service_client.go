@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	clientIDRegex = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+	// Scopes are named <service>:<action>, such as product:stock
+	scopeRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+)
+
+// ValidateServiceClient checks the client ID and scopes of a new service client
+func ValidateServiceClient(id string, scopes []string) error {
+	if !clientIDRegex.MatchString(id) {
+		return fmt.Errorf("invalid client id format")
+	}
+
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !scopeRegex.MatchString(scope) {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+
+	return nil
+}
+
+// ServiceClient represents a backend service registered for the client
+// credentials grant. Only the hash of its secret is stored.
+type ServiceClient struct {
+	id         string
+	secretHash string
+	scopes     []string
+	createdAt  time.Time
+}
+
+// NewServiceClient creates a new ServiceClient entity
+func NewServiceClient(
+	id string,
+	secretHash string,
+	scopes []string,
+	createdAt time.Time,
+) *ServiceClient {
+	return &ServiceClient{
+		id:         id,
+		secretHash: secretHash,
+		scopes:     scopes,
+		createdAt:  createdAt,
+	}
+}
+
+// ID returns the client ID
+func (c *ServiceClient) ID() string {
+	return c.id
+}
+
+// SecretHash returns the hash of the client secret
+func (c *ServiceClient) SecretHash() string {
+	return c.secretHash
+}
+
+// Scopes returns the scopes the client may request
+func (c *ServiceClient) Scopes() []string {
+	return c.scopes
+}
+
+// CreatedAt returns the creation time
+func (c *ServiceClient) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// VerifySecret checks a client secret against the stored hash in constant time
+func (c *ServiceClient) VerifySecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(secret)), []byte(c.secretHash)) == 1
+}
+
+// AllowsScopes reports whether the client may request every one of the scopes
+func (c *ServiceClient) AllowsScopes(requested []string) bool {
+	for _, scope := range requested {
+		allowed := false
+		for _, s := range c.scopes {
+			if s == scope {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
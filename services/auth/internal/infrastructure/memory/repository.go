@@ -0,0 +1,549 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+)
+
+// AuthRepository implements auth.Repository in memory.
+// It is intended for tests and local development without PostgreSQL.
+type AuthRepository struct {
+	mu                  sync.Mutex
+	users               map[string]*auth.User
+	refreshTokens       map[string]*auth.RefreshToken
+	revokedAccessTokens map[string]time.Time
+	mfa                 map[string]*auth.MFA
+	recoveryCodes       map[string]map[string]bool // user ID -> code hash -> used
+	passwordResetTokens map[string]*auth.PasswordResetToken
+	loginAttempts       map[string]*auth.LoginAttempts
+	userRoles           map[string]map[string]bool // user ID -> role -> granted
+	serviceClients      map[string]*auth.ServiceClient
+}
+
+// NewAuthRepository creates a new in-memory AuthRepository
+func NewAuthRepository() *AuthRepository {
+	return &AuthRepository{
+		users:               make(map[string]*auth.User),
+		refreshTokens:       make(map[string]*auth.RefreshToken),
+		revokedAccessTokens: make(map[string]time.Time),
+		mfa:                 make(map[string]*auth.MFA),
+		recoveryCodes:       make(map[string]map[string]bool),
+		passwordResetTokens: make(map[string]*auth.PasswordResetToken),
+		loginAttempts:       make(map[string]*auth.LoginAttempts),
+		userRoles:           make(map[string]map[string]bool),
+		serviceClients:      make(map[string]*auth.ServiceClient),
+	}
+}
+
+// CreateUser creates a new user
+func (r *AuthRepository) CreateUser(ctx context.Context, user *auth.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Email().Equals(user.Email()) {
+			return pkgerrors.ErrAlreadyExists
+		}
+	}
+
+	r.users[user.ID()] = user
+	return nil
+}
+
+// FindByEmail retrieves a user by email address
+func (r *AuthRepository) FindByEmail(ctx context.Context, email auth.Email) (*auth.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email().Equals(email) {
+			return user, nil
+		}
+	}
+
+	return nil, pkgerrors.ErrNotFound
+}
+
+// FindByID retrieves a user by ID
+func (r *AuthRepository) FindByID(ctx context.Context, id string) (*auth.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	return user, nil
+}
+
+// UpdatePassword updates a user's password
+func (r *AuthRepository) UpdatePassword(ctx context.Context, userID string, password auth.Password) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return pkgerrors.ErrNotFound
+	}
+
+	r.users[userID] = auth.NewUser(
+		user.ID(),
+		user.Email(),
+		password,
+		user.Name(),
+		user.PhoneNumber(),
+		user.EmailVerified(),
+		user.CreatedAt(),
+		time.Now(),
+	)
+	return nil
+}
+
+// MarkEmailVerified records that a user verified their email address
+func (r *AuthRepository) MarkEmailVerified(ctx context.Context, userID string, verifiedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return pkgerrors.ErrNotFound
+	}
+
+	r.users[userID] = auth.NewUser(
+		user.ID(),
+		user.Email(),
+		user.Password(),
+		user.Name(),
+		user.PhoneNumber(),
+		true,
+		user.CreatedAt(),
+		verifiedAt,
+	)
+	return nil
+}
+
+// FindUserRoles retrieves the roles granted to a user, sorted by name
+func (r *AuthRepository) FindUserRoles(ctx context.Context, userID string) ([]auth.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := make([]string, 0, len(r.userRoles[userID]))
+	for value := range r.userRoles[userID] {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	roles := make([]auth.Role, 0, len(values))
+	for _, value := range values {
+		role, err := auth.NewRole(value)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// AddUserRole grants a role to a user
+func (r *AuthRepository) AddUserRole(ctx context.Context, userID string, role auth.Role, grantedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return pkgerrors.ErrNotFound
+	}
+
+	if r.userRoles[userID] == nil {
+		r.userRoles[userID] = make(map[string]bool)
+	}
+	r.userRoles[userID][role.String()] = true
+	return nil
+}
+
+// RemoveUserRole revokes a role from a user
+func (r *AuthRepository) RemoveUserRole(ctx context.Context, userID string, role auth.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.userRoles[userID][role.String()] {
+		return pkgerrors.ErrNotFound
+	}
+
+	delete(r.userRoles[userID], role.String())
+	return nil
+}
+
+// CreateServiceClient registers a service client
+func (r *AuthRepository) CreateServiceClient(ctx context.Context, client *auth.ServiceClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.serviceClients[client.ID()]; exists {
+		return pkgerrors.ErrAlreadyExists
+	}
+
+	r.serviceClients[client.ID()] = client
+	return nil
+}
+
+// FindServiceClient retrieves a service client by ID
+func (r *AuthRepository) FindServiceClient(ctx context.Context, id string) (*auth.ServiceClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client, ok := r.serviceClients[id]
+	if !ok {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	return client, nil
+}
+
+// CreateRefreshToken stores a newly issued refresh token
+func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token *auth.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.refreshTokens {
+		if existing.TokenHash() == token.TokenHash() {
+			return pkgerrors.ErrAlreadyExists
+		}
+	}
+
+	r.refreshTokens[token.ID()] = token
+	return nil
+}
+
+// FindRefreshTokenByHash retrieves a refresh token by its hash
+func (r *AuthRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.refreshTokens {
+		if token.TokenHash() == tokenHash {
+			return token, nil
+		}
+	}
+
+	return nil, pkgerrors.ErrNotFound
+}
+
+// FindRefreshTokenByAccessTokenID retrieves the refresh token issued together
+// with the given access token
+func (r *AuthRepository) FindRefreshTokenByAccessTokenID(ctx context.Context, accessTokenID string) (*auth.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.refreshTokens {
+		if token.AccessTokenID() == accessTokenID {
+			return token, nil
+		}
+	}
+
+	return nil, pkgerrors.ErrNotFound
+}
+
+// MarkRefreshTokenUsed marks a refresh token as exchanged
+func (r *AuthRepository) MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.refreshTokens[id]
+	if !ok {
+		return pkgerrors.ErrNotFound
+	}
+	if token.IsUsed() || token.IsRevoked() {
+		return auth.ErrRefreshTokenAlreadyUsed
+	}
+
+	r.refreshTokens[id] = withRefreshTokenState(token, &usedAt, token.RevokedAt())
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token in a family
+func (r *AuthRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.refreshTokens {
+		if token.FamilyID() == familyID && !token.IsRevoked() {
+			r.refreshTokens[id] = withRefreshTokenState(token, token.UsedAt(), &revokedAt)
+		}
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user
+func (r *AuthRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.refreshTokens {
+		if token.UserID() == userID && !token.IsRevoked() {
+			r.refreshTokens[id] = withRefreshTokenState(token, token.UsedAt(), &revokedAt)
+		}
+	}
+
+	return nil
+}
+
+// RevokeAccessToken adds an access token ID to the denylist until the token expires
+func (r *AuthRepository) RevokeAccessToken(ctx context.Context, tokenID string, userID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range r.revokedAccessTokens {
+		if !exp.After(now) {
+			delete(r.revokedAccessTokens, id)
+		}
+	}
+
+	r.revokedAccessTokens[tokenID] = expiresAt
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether an access token ID is on the denylist
+func (r *AuthRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expiresAt, ok := r.revokedAccessTokens[tokenID]
+	return ok && expiresAt.After(now), nil
+}
+
+// SaveMFA creates or replaces the MFA settings of a user
+func (r *AuthRepository) SaveMFA(ctx context.Context, mfa *auth.MFA) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mfa[mfa.UserID()] = mfa
+	return nil
+}
+
+// FindMFA retrieves the MFA settings of a user
+func (r *AuthRepository) FindMFA(ctx context.Context, userID string) (*auth.MFA, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfa[userID]
+	if !ok {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	return mfa, nil
+}
+
+// DeleteMFA removes the MFA settings and recovery codes of a user
+func (r *AuthRepository) DeleteMFA(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.mfa, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+// MarkTOTPStepUsed records the time step of an accepted TOTP code
+func (r *AuthRepository) MarkTOTPStepUsed(ctx context.Context, userID string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfa[userID]
+	if !ok {
+		return pkgerrors.ErrNotFound
+	}
+	if step <= mfa.LastUsedStep() {
+		return auth.ErrTOTPCodeAlreadyUsed
+	}
+
+	r.mfa[userID] = auth.NewMFA(
+		mfa.UserID(),
+		mfa.Secret(),
+		mfa.ConfirmedAt(),
+		step,
+		mfa.CreatedAt(),
+		time.Now(),
+	)
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces every recovery code of a user with the given hashes
+func (r *AuthRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make(map[string]bool, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		codes[codeHash] = false
+	}
+
+	r.recoveryCodes[userID] = codes
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return pkgerrors.ErrNotFound
+	}
+
+	r.recoveryCodes[userID][codeHash] = true
+	return nil
+}
+
+// CreatePasswordResetToken stores a newly issued password reset token
+func (r *AuthRepository) CreatePasswordResetToken(ctx context.Context, token *auth.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.passwordResetTokens {
+		if existing.TokenHash() == token.TokenHash() {
+			return pkgerrors.ErrAlreadyExists
+		}
+	}
+
+	r.passwordResetTokens[token.ID()] = token
+	return nil
+}
+
+// FindPasswordResetTokenByHash retrieves a password reset token by its hash
+func (r *AuthRepository) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*auth.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.passwordResetTokens {
+		if token.TokenHash() == tokenHash {
+			return token, nil
+		}
+	}
+
+	return nil, pkgerrors.ErrNotFound
+}
+
+// MarkPasswordResetTokenUsed marks a password reset token as used
+func (r *AuthRepository) MarkPasswordResetTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.passwordResetTokens[id]
+	if !ok {
+		return pkgerrors.ErrNotFound
+	}
+	if token.IsUsed() {
+		return auth.ErrPasswordResetTokenAlreadyUsed
+	}
+
+	r.passwordResetTokens[id] = withPasswordResetTokenUsed(token, usedAt)
+	return nil
+}
+
+// InvalidateUserPasswordResetTokens marks every unused password reset token of a user as used
+func (r *AuthRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.passwordResetTokens {
+		if token.UserID() == userID && !token.IsUsed() {
+			r.passwordResetTokens[id] = withPasswordResetTokenUsed(token, usedAt)
+		}
+	}
+
+	return nil
+}
+
+// FindLoginAttempts returns the failed logins recorded for a throttle key
+func (r *AuthRepository) FindLoginAttempts(ctx context.Context, key string) (*auth.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.loginAttempts[key]
+	if !ok {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	return attempts, nil
+}
+
+// RecordLoginFailure counts a failed login for a throttle key
+func (r *AuthRepository) RecordLoginFailure(
+	ctx context.Context,
+	key string,
+	failedAt time.Time,
+	windowStart time.Time,
+) (*auth.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failures := 1
+	var lockedUntil *time.Time
+	if existing, ok := r.loginAttempts[key]; ok {
+		failures = existing.FailuresSince(windowStart) + 1
+		lockedUntil = existing.LockedUntil()
+	}
+
+	attempts := auth.NewLoginAttempts(key, failures, failedAt, lockedUntil)
+	r.loginAttempts[key] = attempts
+	return attempts, nil
+}
+
+// LockLogin rejects logins for a throttle key until the given time and clears its failure count
+func (r *AuthRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.loginAttempts[key]
+	if !ok {
+		return nil
+	}
+
+	r.loginAttempts[key] = auth.NewLoginAttempts(key, 0, existing.LastFailureAt(), &until)
+	return nil
+}
+
+// ResetLoginAttempts clears the failures and the lock of a throttle key
+func (r *AuthRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.loginAttempts, key)
+	return nil
+}
+
+// withRefreshTokenState returns a copy of the token with the given used and revoked times
+func withRefreshTokenState(token *auth.RefreshToken, usedAt, revokedAt *time.Time) *auth.RefreshToken {
+	return auth.NewRefreshToken(
+		token.ID(),
+		token.UserID(),
+		token.FamilyID(),
+		token.TokenHash(),
+		token.AccessTokenID(),
+		token.ExpiresAt(),
+		usedAt,
+		revokedAt,
+		token.CreatedAt(),
+	)
+}
+
+// withPasswordResetTokenUsed returns a copy of the token marked as used
+func withPasswordResetTokenUsed(token *auth.PasswordResetToken, usedAt time.Time) *auth.PasswordResetToken {
+	return auth.NewPasswordResetToken(
+		token.ID(),
+		token.UserID(),
+		token.TokenHash(),
+		token.ExpiresAt(),
+		&usedAt,
+		token.CreatedAt(),
+	)
+}
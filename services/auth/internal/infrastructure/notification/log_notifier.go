@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+)
+
+// LogNotifier implements auth.Notifier by writing messages to a log.
+// It is intended for local development without a mail server. The log
+// contains secrets such as reset tokens and must not be used in production.
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier creates a LogNotifier that writes to w
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{
+		logger: log.New(w, "[notification] ", log.LstdFlags),
+	}
+}
+
+// NewFileNotifier creates a LogNotifier that appends to the file at path
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- path comes from operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification file: %w", err)
+	}
+	return NewLogNotifier(f), nil
+}
+
+// SendPasswordReset writes the password reset token to the log
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, email auth.Email, token string, expiresAt time.Time) error {
+	n.logger.Printf("password reset for %s: token=%s expires_at=%s", email.String(), token, expiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// SendEmailVerification writes the email verification token to the log
+func (n *LogNotifier) SendEmailVerification(ctx context.Context, email auth.Email, token string, expiresAt time.Time) error {
+	n.logger.Printf("email verification for %s: token=%s expires_at=%s", email.String(), token, expiresAt.Format(time.RFC3339))
+	return nil
+}
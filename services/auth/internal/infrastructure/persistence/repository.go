@@ -33,8 +33,8 @@ func (r *AuthRepository) CreateUser(ctx context.Context, user *auth.User) error
 	defer cancel()
 
 	query := `
-		INSERT INTO users (id, email, password_hash, name, phone_number, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, email, password_hash, name, phone_number, email_verified, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -43,6 +43,7 @@ func (r *AuthRepository) CreateUser(ctx context.Context, user *auth.User) error
 		user.Password().Hash(),
 		user.Name(),
 		user.PhoneNumber(),
+		user.EmailVerified(),
 		user.CreatedAt(),
 		user.UpdatedAt(),
 	)
@@ -64,19 +65,20 @@ func (r *AuthRepository) FindByEmail(ctx context.Context, email auth.Email) (*au
 	defer cancel()
 
 	query := `
-		SELECT id, email, password_hash, name, phone_number, created_at, updated_at
+		SELECT id, email, password_hash, name, phone_number, email_verified, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
 
 	var (
-		id           string
-		emailStr     string
-		passwordHash string
-		name         string
-		phoneNumber  string
-		createdAt    time.Time
-		updatedAt    time.Time
+		id            string
+		emailStr      string
+		passwordHash  string
+		name          string
+		phoneNumber   string
+		emailVerified bool
+		createdAt     time.Time
+		updatedAt     time.Time
 	)
 
 	err := r.db.QueryRowContext(ctx, query, email.String()).Scan(
@@ -85,6 +87,7 @@ func (r *AuthRepository) FindByEmail(ctx context.Context, email auth.Email) (*au
 		&passwordHash,
 		&name,
 		&phoneNumber,
+		&emailVerified,
 		&createdAt,
 		&updatedAt,
 	)
@@ -96,7 +99,7 @@ func (r *AuthRepository) FindByEmail(ctx context.Context, email auth.Email) (*au
 		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find user: %v", err))
 	}
 
-	return rowToUser(id, emailStr, passwordHash, name, phoneNumber, createdAt, updatedAt)
+	return rowToUser(id, emailStr, passwordHash, name, phoneNumber, emailVerified, createdAt, updatedAt)
 }
 
 // FindByID retrieves a user by ID
@@ -105,18 +108,19 @@ func (r *AuthRepository) FindByID(ctx context.Context, id string) (*auth.User, e
 	defer cancel()
 
 	query := `
-		SELECT id, email, password_hash, name, phone_number, created_at, updated_at
+		SELECT id, email, password_hash, name, phone_number, email_verified, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 
 	var (
-		emailStr     string
-		passwordHash string
-		name         string
-		phoneNumber  string
-		createdAt    time.Time
-		updatedAt    time.Time
+		emailStr      string
+		passwordHash  string
+		name          string
+		phoneNumber   string
+		emailVerified bool
+		createdAt     time.Time
+		updatedAt     time.Time
 	)
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -125,6 +129,7 @@ func (r *AuthRepository) FindByID(ctx context.Context, id string) (*auth.User, e
 		&passwordHash,
 		&name,
 		&phoneNumber,
+		&emailVerified,
 		&createdAt,
 		&updatedAt,
 	)
@@ -136,7 +141,7 @@ func (r *AuthRepository) FindByID(ctx context.Context, id string) (*auth.User, e
 		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find user: %v", err))
 	}
 
-	return rowToUser(id, emailStr, passwordHash, name, phoneNumber, createdAt, updatedAt)
+	return rowToUser(id, emailStr, passwordHash, name, phoneNumber, emailVerified, createdAt, updatedAt)
 }
 
 // UpdatePassword updates a user's password
@@ -158,8 +163,789 @@ func (r *AuthRepository) UpdatePassword(ctx context.Context, userID string, pass
 	return nil
 }
 
+// MarkEmailVerified records that a user verified their email address.
+// The time of the first verification is kept.
+func (r *AuthRepository) MarkEmailVerified(ctx context.Context, userID string, verifiedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE users
+		SET email_verified = TRUE, email_verified_at = COALESCE(email_verified_at, $2), updated_at = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, verifiedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark email verified: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark email verified: %v", err))
+	}
+	if affected == 0 {
+		return pkgerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindUserRoles retrieves the roles granted to a user, sorted by name
+func (r *AuthRepository) FindUserRoles(ctx context.Context, userID string) ([]auth.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find user roles: %v", err))
+	}
+	defer rows.Close()
+
+	var roles []auth.Role
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to scan user role: %v", err))
+		}
+
+		role, err := auth.NewRole(value)
+		if err != nil {
+			return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("invalid role in database: %v", err))
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find user roles: %v", err))
+	}
+
+	return roles, nil
+}
+
+// AddUserRole grants a role to a user
+func (r *AuthRepository) AddUserRole(ctx context.Context, userID string, role auth.Role, grantedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO user_roles (user_id, role, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, role) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, role.String(), grantedAt)
+	if err != nil {
+		// The user does not exist
+		if isForeignKeyError(err) {
+			return pkgerrors.ErrNotFound
+		}
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to add user role: %v", err))
+	}
+
+	return nil
+}
+
+// RemoveUserRole revokes a role from a user
+func (r *AuthRepository) RemoveUserRole(ctx context.Context, userID string, role auth.Role) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+
+	result, err := r.db.ExecContext(ctx, query, userID, role.String())
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to remove user role: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to remove user role: %v", err))
+	}
+	if affected == 0 {
+		return pkgerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateServiceClient registers a service client
+func (r *AuthRepository) CreateServiceClient(ctx context.Context, client *auth.ServiceClient) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO service_clients (id, secret_hash, scopes, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		client.ID(),
+		client.SecretHash(),
+		pq.Array(client.Scopes()),
+		client.CreatedAt(),
+	)
+
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return pkgerrors.ErrAlreadyExists
+		}
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to create service client: %v", err))
+	}
+
+	return nil
+}
+
+// FindServiceClient retrieves a service client by ID
+func (r *AuthRepository) FindServiceClient(ctx context.Context, id string) (*auth.ServiceClient, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, secret_hash, scopes, created_at
+		FROM service_clients
+		WHERE id = $1
+	`
+
+	var (
+		secretHash string
+		scopes     []string
+		createdAt  time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&id,
+		&secretHash,
+		pq.Array(&scopes),
+		&createdAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, pkgerrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find service client: %v", err))
+	}
+
+	return auth.NewServiceClient(id, secretHash, scopes, createdAt), nil
+}
+
+// CreateRefreshToken stores a newly issued refresh token
+func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token *auth.RefreshToken) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, access_token_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID(),
+		token.UserID(),
+		token.FamilyID(),
+		token.TokenHash(),
+		token.AccessTokenID(),
+		token.ExpiresAt(),
+		token.CreatedAt(),
+	)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to create refresh token: %v", err))
+	}
+
+	return nil
+}
+
+// FindRefreshTokenByHash retrieves a refresh token by its hash
+func (r *AuthRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, family_id, token_hash, access_token_id, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	return scanRefreshToken(r.db.QueryRowContext(ctx, query, tokenHash))
+}
+
+// FindRefreshTokenByAccessTokenID retrieves the refresh token issued together
+// with the given access token
+func (r *AuthRepository) FindRefreshTokenByAccessTokenID(ctx context.Context, accessTokenID string) (*auth.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, family_id, token_hash, access_token_id, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE access_token_id = $1
+	`
+
+	return scanRefreshToken(r.db.QueryRowContext(ctx, query, accessTokenID))
+}
+
+// MarkRefreshTokenUsed marks a refresh token as exchanged.
+// The update only succeeds while the token is unused and not revoked, so two
+// concurrent refreshes with the same token cannot both win.
+func (r *AuthRepository) MarkRefreshTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark refresh token used: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark refresh token used: %v", err))
+	}
+	if affected == 0 {
+		return auth.ErrRefreshTokenAlreadyUsed
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes every refresh token in a family
+func (r *AuthRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, familyID, revokedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to revoke refresh token family: %v", err))
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user
+func (r *AuthRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, revokedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to revoke refresh tokens: %v", err))
+	}
+
+	return nil
+}
+
+// RevokeAccessToken adds an access token ID to the denylist until the token expires.
+// Entries whose token has already expired are purged at the same time.
+func (r *AuthRepository) RevokeAccessToken(ctx context.Context, tokenID string, userID string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	now := time.Now()
+
+	query := `
+		INSERT INTO revoked_access_tokens (token_id, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenID, userID, expiresAt, now); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to revoke access token: %v", err))
+	}
+
+	purgeQuery := `DELETE FROM revoked_access_tokens WHERE expires_at <= $1`
+	if _, err := r.db.ExecContext(ctx, purgeQuery, now); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to purge revoked access tokens: %v", err))
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether an access token ID is on the denylist
+func (r *AuthRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM revoked_access_tokens
+			WHERE token_id = $1 AND expires_at > $2
+		)
+	`
+
+	var revoked bool
+	if err := r.db.QueryRowContext(ctx, query, tokenID, now).Scan(&revoked); err != nil {
+		return false, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to check revoked access token: %v", err))
+	}
+
+	return revoked, nil
+}
+
+// SaveMFA creates or replaces the MFA settings of a user
+func (r *AuthRepository) SaveMFA(ctx context.Context, mfa *auth.MFA) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO user_mfa (user_id, totp_secret, confirmed_at, last_used_step, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET totp_secret = EXCLUDED.totp_secret,
+			confirmed_at = EXCLUDED.confirmed_at,
+			last_used_step = EXCLUDED.last_used_step,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		mfa.UserID(),
+		mfa.Secret(),
+		mfa.ConfirmedAt(),
+		mfa.LastUsedStep(),
+		mfa.CreatedAt(),
+		mfa.UpdatedAt(),
+	)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to save mfa: %v", err))
+	}
+
+	return nil
+}
+
+// FindMFA retrieves the MFA settings of a user
+func (r *AuthRepository) FindMFA(ctx context.Context, userID string) (*auth.MFA, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT user_id, totp_secret, confirmed_at, last_used_step, created_at, updated_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+
+	var (
+		secret       string
+		confirmedAt  sql.NullTime
+		lastUsedStep int64
+		createdAt    time.Time
+		updatedAt    time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&userID,
+		&secret,
+		&confirmedAt,
+		&lastUsedStep,
+		&createdAt,
+		&updatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, pkgerrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find mfa: %v", err))
+	}
+
+	return auth.NewMFA(userID, secret, nullTimeToPtr(confirmedAt), lastUsedStep, createdAt, updatedAt), nil
+}
+
+// DeleteMFA removes the MFA settings and recovery codes of a user
+func (r *AuthRepository) DeleteMFA(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to delete recovery codes: %v", err))
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to delete mfa: %v", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to delete mfa: %v", err))
+	}
+
+	return nil
+}
+
+// MarkTOTPStepUsed records the time step of an accepted TOTP code.
+// The update only succeeds for a newer step, so a code cannot be replayed
+// even by concurrent requests.
+func (r *AuthRepository) MarkTOTPStepUsed(ctx context.Context, userID string, step int64) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE user_mfa
+		SET last_used_step = $2, updated_at = $3
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, step, time.Now())
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark totp step used: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark totp step used: %v", err))
+	}
+	if affected == 0 {
+		return auth.ErrTOTPCodeAlreadyUsed
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces every recovery code of a user with the given hashes
+func (r *AuthRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string, createdAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to begin transaction: %v", err))
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to delete recovery codes: %v", err))
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at)
+		VALUES ($1, $2, $3)
+	`
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, userID, codeHash, createdAt); err != nil {
+			return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to create recovery code: %v", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to replace recovery codes: %v", err))
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash, usedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to use recovery code: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to use recovery code: %v", err))
+	}
+	if affected == 0 {
+		return pkgerrors.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreatePasswordResetToken stores a newly issued password reset token
+func (r *AuthRepository) CreatePasswordResetToken(ctx context.Context, token *auth.PasswordResetToken) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID(),
+		token.UserID(),
+		token.TokenHash(),
+		token.ExpiresAt(),
+		token.CreatedAt(),
+	)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to create password reset token: %v", err))
+	}
+
+	return nil
+}
+
+// FindPasswordResetTokenByHash retrieves a password reset token by its hash
+func (r *AuthRepository) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*auth.PasswordResetToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+
+	var (
+		id        string
+		userID    string
+		expiresAt time.Time
+		usedAt    sql.NullTime
+		createdAt time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&id,
+		&userID,
+		&tokenHash,
+		&expiresAt,
+		&usedAt,
+		&createdAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, pkgerrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find password reset token: %v", err))
+	}
+
+	return auth.NewPasswordResetToken(id, userID, tokenHash, expiresAt, nullTimeToPtr(usedAt), createdAt), nil
+}
+
+// MarkPasswordResetTokenUsed marks a password reset token as used.
+// The update only succeeds while the token is unused, so a token cannot be
+// used twice even by concurrent requests.
+func (r *AuthRepository) MarkPasswordResetTokenUsed(ctx context.Context, id string, usedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, usedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark password reset token used: %v", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to mark password reset token used: %v", err))
+	}
+	if affected == 0 {
+		return auth.ErrPasswordResetTokenAlreadyUsed
+	}
+
+	return nil
+}
+
+// InvalidateUserPasswordResetTokens marks every unused password reset token of a user as used
+func (r *AuthRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID string, usedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = $2
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, usedAt)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to invalidate password reset tokens: %v", err))
+	}
+
+	return nil
+}
+
+// FindLoginAttempts returns the failed logins recorded for a throttle key
+func (r *AuthRepository) FindLoginAttempts(ctx context.Context, key string) (*auth.LoginAttempts, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		SELECT throttle_key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE throttle_key = $1
+	`
+
+	return scanLoginAttempts(r.db.QueryRowContext(ctx, query, key))
+}
+
+// RecordLoginFailure counts a failed login for a throttle key in a single
+// statement, so concurrent failures are all counted
+func (r *AuthRepository) RecordLoginFailure(
+	ctx context.Context,
+	key string,
+	failedAt time.Time,
+	windowStart time.Time,
+) (*auth.LoginAttempts, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO login_attempts (throttle_key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (throttle_key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.last_failure_at < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = $2
+		RETURNING throttle_key, failures, last_failure_at, locked_until
+	`
+
+	attempts, err := scanLoginAttempts(r.db.QueryRowContext(ctx, query, key, failedAt, windowStart))
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to record login failure: %v", err))
+	}
+
+	return attempts, nil
+}
+
+// LockLogin rejects logins for a throttle key until the given time and clears its failure count
+func (r *AuthRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE login_attempts
+		SET failures = 0, locked_until = $2
+		WHERE throttle_key = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key, until)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to lock login: %v", err))
+	}
+
+	return nil
+}
+
+// ResetLoginAttempts clears the failures and the lock of a throttle key
+func (r *AuthRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	query := `DELETE FROM login_attempts WHERE throttle_key = $1`
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to reset login attempts: %v", err))
+	}
+
+	return nil
+}
+
+// scanRefreshToken converts a refresh_tokens row to a domain RefreshToken entity
+func scanRefreshToken(row *sql.Row) (*auth.RefreshToken, error) {
+	var (
+		id            string
+		userID        string
+		familyID      string
+		tokenHash     string
+		accessTokenID string
+		expiresAt     time.Time
+		usedAt        sql.NullTime
+		revokedAt     sql.NullTime
+		createdAt     time.Time
+	)
+
+	err := row.Scan(
+		&id,
+		&userID,
+		&familyID,
+		&tokenHash,
+		&accessTokenID,
+		&expiresAt,
+		&usedAt,
+		&revokedAt,
+		&createdAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, pkgerrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to find refresh token: %v", err))
+	}
+
+	return auth.NewRefreshToken(
+		id,
+		userID,
+		familyID,
+		tokenHash,
+		accessTokenID,
+		expiresAt,
+		nullTimeToPtr(usedAt),
+		nullTimeToPtr(revokedAt),
+		createdAt,
+	), nil
+}
+
+// scanLoginAttempts converts a login_attempts row to a domain LoginAttempts entity
+func scanLoginAttempts(row *sql.Row) (*auth.LoginAttempts, error) {
+	var (
+		key           string
+		failures      int
+		lastFailureAt sql.NullTime
+		lockedUntil   sql.NullTime
+	)
+
+	err := row.Scan(&key, &failures, &lastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, pkgerrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, fmt.Sprintf("failed to scan login attempts: %v", err))
+	}
+
+	return auth.NewLoginAttempts(
+		key,
+		failures,
+		lastFailureAt.Time,
+		nullTimeToPtr(lockedUntil),
+	), nil
+}
+
 // rowToUser converts database row to domain User entity
-func rowToUser(id, emailStr, passwordHash, name, phoneNumber string, createdAt, updatedAt time.Time) (*auth.User, error) {
+func rowToUser(
+	id, emailStr, passwordHash, name, phoneNumber string,
+	emailVerified bool,
+	createdAt, updatedAt time.Time,
+) (*auth.User, error) {
 	email, err := auth.NewEmail(emailStr)
 	if err != nil {
 		return nil, pkgerrors.Wrap(pkgerrors.ErrInternal, "invalid email in database")
@@ -173,11 +959,20 @@ func rowToUser(id, emailStr, passwordHash, name, phoneNumber string, createdAt,
 		password,
 		name,
 		phoneNumber,
+		emailVerified,
 		createdAt,
 		updatedAt,
 	), nil
 }
 
+// nullTimeToPtr converts a nullable database timestamp to a time pointer
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
 // isDuplicateKeyError checks if the error is a duplicate key constraint violation
 func isDuplicateKeyError(err error) bool {
 	if err == nil {
@@ -192,3 +987,13 @@ func isDuplicateKeyError(err error) bool {
 
 	return false
 }
+
+// isForeignKeyError checks if the error is a foreign key violation error
+func isForeignKeyError(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23503"
+	}
+
+	return false
+}
@@ -3,6 +3,8 @@ package grpc
 import (
 	"context"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/pkg/clientip"
 	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
 	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
@@ -22,6 +24,19 @@ func NewAuthHandler(authService *appauth.Service) *AuthHandler {
 	}
 }
 
+// authorizeUser checks that the caller is the user a request is about.
+// Callers with one of roles may act on other users as well.
+func authorizeUser(ctx context.Context, userID string, roles ...string) error {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return pkgerrors.ErrUnauthenticated.GRPCStatus().Err()
+	}
+	if claims.UserID != userID && !auth.HasAnyRole(claims.Roles, roles...) {
+		return pkgerrors.ErrPermissionDenied.GRPCStatus().Err()
+	}
+	return nil
+}
+
 // Register creates a new user account
 func (h *AuthHandler) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
 	// Validate request
@@ -54,6 +69,7 @@ func (h *AuthHandler) Register(ctx context.Context, req *authpb.RegisterRequest)
 		CreatedAt: &commonpb.Timestamp{
 			Seconds: output.CreatedAt.Unix(),
 		},
+		EmailVerified: output.EmailVerified,
 	}, nil
 }
 
@@ -68,6 +84,7 @@ func (h *AuthHandler) Login(ctx context.Context, req *authpb.LoginRequest) (*aut
 	output, err := h.authService.Login(ctx, appauth.LoginInput{
 		Email:    req.Email,
 		Password: req.Password,
+		ClientIP: clientip.FromIncomingContext(ctx),
 	})
 
 	if err != nil {
@@ -77,14 +94,7 @@ func (h *AuthHandler) Login(ctx context.Context, req *authpb.LoginRequest) (*aut
 		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
 	}
 
-	return &authpb.LoginResponse{
-		UserId:       output.UserID,
-		Email:        output.Email,
-		Name:         output.Name,
-		PhoneNumber:  output.PhoneNumber,
-		AccessToken:  output.AccessToken,
-		RefreshToken: output.RefreshToken,
-	}, nil
+	return toLoginResponse(output), nil
 }
 
 // VerifyToken validates a JWT token
@@ -99,9 +109,11 @@ func (h *AuthHandler) VerifyToken(ctx context.Context, req *authpb.VerifyTokenRe
 	}
 
 	return &authpb.VerifyTokenResponse{
-		Valid:  output.Valid,
-		UserId: output.UserID,
-		Email:  output.Email,
+		Valid:         output.Valid,
+		UserId:        output.UserID,
+		Email:         output.Email,
+		EmailVerified: output.EmailVerified,
+		Roles:         output.Roles,
 		ExpiresAt: &commonpb.Timestamp{
 			Seconds: output.ExpiresAt.Unix(),
 		},
@@ -131,10 +143,43 @@ func (h *AuthHandler) RefreshToken(ctx context.Context, req *authpb.RefreshToken
 	}, nil
 }
 
-// Logout invalidates a user's tokens
+// Logout revokes an access token and the refresh tokens of its session
 func (h *AuthHandler) Logout(ctx context.Context, req *authpb.LogoutRequest) (*authpb.LogoutResponse, error) {
-	// TODO: Implement token blacklist/invalidation
-	// For now, just return success (client-side token deletion)
+	if req.UserId == "" || req.Token == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.Logout(ctx, appauth.LogoutInput{
+		UserID:      req.UserId,
+		AccessToken: req.Token,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.LogoutResponse{Success: true}, nil
+}
+
+// LogoutAll invalidates every session of a user
+func (h *AuthHandler) LogoutAll(ctx context.Context, req *authpb.LogoutAllRequest) (*authpb.LogoutResponse, error) {
+	if req.UserId == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+	if err := authorizeUser(ctx, req.UserId, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := h.authService.LogoutAll(ctx, req.UserId); err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
 	return &authpb.LogoutResponse{Success: true}, nil
 }
 
@@ -159,3 +204,354 @@ func (h *AuthHandler) ChangePassword(ctx context.Context, req *authpb.ChangePass
 
 	return &authpb.ChangePasswordResponse{Success: true}, nil
 }
+
+// RequestPasswordReset sends a password reset token to the email of an account
+func (h *AuthHandler) RequestPasswordReset(ctx context.Context, req *authpb.RequestPasswordResetRequest) (*authpb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.RequestPasswordReset(ctx, appauth.RequestPasswordResetInput{
+		Email: req.Email,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.RequestPasswordResetResponse{Success: true}, nil
+}
+
+// ConfirmPasswordReset sets a new password with a reset token
+func (h *AuthHandler) ConfirmPasswordReset(ctx context.Context, req *authpb.ConfirmPasswordResetRequest) (*authpb.ConfirmPasswordResetResponse, error) {
+	if req.Token == "" || req.NewPassword == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.ConfirmPasswordResetResponse{Success: true}, nil
+}
+
+// VerifyEmail marks an email address as verified
+func (h *AuthHandler) VerifyEmail(ctx context.Context, req *authpb.VerifyEmailRequest) (*authpb.VerifyEmailResponse, error) {
+	if req.Token == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.VerifyEmail(ctx, req.Token)
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.VerifyEmailResponse{Success: true}, nil
+}
+
+// ResendVerification sends the email verification token again
+func (h *AuthHandler) ResendVerification(ctx context.Context, req *authpb.ResendVerificationRequest) (*authpb.ResendVerificationResponse, error) {
+	if req.Email == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.ResendVerification(ctx, appauth.ResendVerificationInput{
+		Email: req.Email,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.ResendVerificationResponse{Success: true}, nil
+}
+
+// UnlockAccount lifts a login lockout
+func (h *AuthHandler) UnlockAccount(ctx context.Context, req *authpb.UnlockAccountRequest) (*authpb.UnlockAccountResponse, error) {
+	if req.Email == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	err := h.authService.UnlockAccount(ctx, appauth.UnlockAccountInput{
+		Email:    req.Email,
+		ClientIP: req.IpAddress,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.UnlockAccountResponse{Success: true}, nil
+}
+
+// AssignRole grants a role to a user
+func (h *AuthHandler) AssignRole(ctx context.Context, req *authpb.RoleRequest) (*authpb.RoleResponse, error) {
+	if req.UserId == "" || req.Role == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	roles, err := h.authService.AssignRole(ctx, appauth.RoleInput{
+		UserID: req.UserId,
+		Role:   req.Role,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.RoleResponse{Roles: roles}, nil
+}
+
+// RevokeRole takes a role away from a user
+func (h *AuthHandler) RevokeRole(ctx context.Context, req *authpb.RoleRequest) (*authpb.RoleResponse, error) {
+	if req.UserId == "" || req.Role == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	roles, err := h.authService.RevokeRole(ctx, appauth.RoleInput{
+		UserID: req.UserId,
+		Role:   req.Role,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.RoleResponse{Roles: roles}, nil
+}
+
+// IssueServiceToken exchanges client credentials for a service token
+func (h *AuthHandler) IssueServiceToken(ctx context.Context, req *authpb.ServiceTokenRequest) (*authpb.ServiceTokenResponse, error) {
+	if req.ClientId == "" || req.ClientSecret == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	output, err := h.authService.IssueServiceToken(ctx, appauth.ClientCredentialsInput{
+		ClientID:     req.ClientId,
+		ClientSecret: req.ClientSecret,
+		Scopes:       req.Scopes,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.ServiceTokenResponse{
+		AccessToken: output.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresAt: &commonpb.Timestamp{
+			Seconds: output.ExpiresAt.Unix(),
+		},
+		Scopes: output.Scopes,
+	}, nil
+}
+
+// CreateServiceClient registers a service client
+func (h *AuthHandler) CreateServiceClient(ctx context.Context, req *authpb.CreateServiceClientRequest) (*authpb.CreateServiceClientResponse, error) {
+	if req.ClientId == "" || len(req.Scopes) == 0 {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	output, err := h.authService.CreateServiceClient(ctx, appauth.CreateServiceClientInput{
+		ClientID: req.ClientId,
+		Scopes:   req.Scopes,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.CreateServiceClientResponse{
+		ClientId:     output.ClientID,
+		ClientSecret: output.ClientSecret,
+		Scopes:       output.Scopes,
+	}, nil
+}
+
+// GetJWKS returns the public keys that verify issued tokens
+func (h *AuthHandler) GetJWKS(ctx context.Context, req *authpb.GetJWKSRequest) (*authpb.GetJWKSResponse, error) {
+	jwks := h.authService.PublicKeys()
+
+	keys := make([]*authpb.JSONWebKey, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		keys = append(keys, &authpb.JSONWebKey{
+			Kty: key.KeyType,
+			Kid: key.KeyID,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Curve,
+			X:   key.X,
+		})
+	}
+
+	return &authpb.GetJWKSResponse{Keys: keys}, nil
+}
+
+// EnrollMFA starts a TOTP enrollment
+func (h *AuthHandler) EnrollMFA(ctx context.Context, req *authpb.EnrollMFARequest) (*authpb.EnrollMFAResponse, error) {
+	if req.UserId == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+	if err := authorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	output, err := h.authService.EnrollMFA(ctx, req.UserId)
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.EnrollMFAResponse{
+		Secret:     output.Secret,
+		OtpauthUri: output.OTPAuthURI,
+	}, nil
+}
+
+// ConfirmMFA enables MFA with a code from the authenticator app
+func (h *AuthHandler) ConfirmMFA(ctx context.Context, req *authpb.ConfirmMFARequest) (*authpb.RecoveryCodesResponse, error) {
+	if req.UserId == "" || req.Code == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+	if err := authorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	codes, err := h.authService.ConfirmMFA(ctx, appauth.MFACodeInput{
+		UserID: req.UserId,
+		Code:   req.Code,
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// GenerateRecoveryCodes replaces the one-time recovery codes
+func (h *AuthHandler) GenerateRecoveryCodes(ctx context.Context, req *authpb.GenerateRecoveryCodesRequest) (*authpb.RecoveryCodesResponse, error) {
+	if req.UserId == "" || req.Code == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+	if err := authorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	codes, err := h.authService.GenerateRecoveryCodes(ctx, appauth.MFACodeInput{
+		UserID:   req.UserId,
+		Code:     req.Code,
+		ClientIP: clientip.FromIncomingContext(ctx),
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns MFA off
+func (h *AuthHandler) DisableMFA(ctx context.Context, req *authpb.DisableMFARequest) (*authpb.DisableMFAResponse, error) {
+	if req.UserId == "" || req.Code == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+	if err := authorizeUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	err := h.authService.DisableMFA(ctx, appauth.MFACodeInput{
+		UserID:   req.UserId,
+		Code:     req.Code,
+		ClientIP: clientip.FromIncomingContext(ctx),
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return &authpb.DisableMFAResponse{Success: true}, nil
+}
+
+// VerifyMFA completes a login that returned an MFA challenge
+func (h *AuthHandler) VerifyMFA(ctx context.Context, req *authpb.VerifyMFARequest) (*authpb.LoginResponse, error) {
+	if req.MfaToken == "" || req.Code == "" {
+		return nil, pkgerrors.ErrInvalidArgument.GRPCStatus().Err()
+	}
+
+	output, err := h.authService.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: req.MfaToken,
+		Code:     req.Code,
+		ClientIP: clientip.FromIncomingContext(ctx),
+	})
+
+	if err != nil {
+		if pkgErr, ok := err.(*pkgerrors.Error); ok {
+			return nil, pkgErr.GRPCStatus().Err()
+		}
+		return nil, pkgerrors.ErrInternal.GRPCStatus().Err()
+	}
+
+	return toLoginResponse(output), nil
+}
+
+// toLoginResponse converts a login result to the gRPC response
+func toLoginResponse(output *appauth.AuthOutput) *authpb.LoginResponse {
+	return &authpb.LoginResponse{
+		UserId:        output.UserID,
+		Email:         output.Email,
+		Name:          output.Name,
+		PhoneNumber:   output.PhoneNumber,
+		AccessToken:   output.AccessToken,
+		RefreshToken:  output.RefreshToken,
+		MfaRequired:   output.MFARequired,
+		MfaToken:      output.MFAToken,
+		EmailVerified: output.EmailVerified,
+	}
+}
@@ -0,0 +1,275 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// createServiceClient registers the order service as a client with stock and payment scopes
+func createServiceClient(t *testing.T, service *appauth.Service) *appauth.ServiceClientOutput {
+	t.Helper()
+
+	client, err := service.CreateServiceClient(context.Background(), appauth.CreateServiceClientInput{
+		ClientID: "order-service",
+		Scopes:   []string{pkgauth.ScopeProductStock, pkgauth.ScopePaymentProcess},
+	})
+	if err != nil {
+		t.Fatalf("CreateServiceClient() error = %v", err)
+	}
+	if client.ClientSecret == "" {
+		t.Fatal("CreateServiceClient() should return the client secret")
+	}
+	return client
+}
+
+func TestIssueServiceToken(t *testing.T) {
+	service, _ := newTestService(t)
+	client := createServiceClient(t, service)
+
+	tests := []struct {
+		name       string
+		scopes     []string
+		wantScopes []string
+	}{
+		{
+			name:       "every scope of the client",
+			wantScopes: []string{pkgauth.ScopeProductStock, pkgauth.ScopePaymentProcess},
+		},
+		{
+			name:       "subset of the scopes",
+			scopes:     []string{pkgauth.ScopeProductStock},
+			wantScopes: []string{pkgauth.ScopeProductStock},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := service.IssueServiceToken(context.Background(), appauth.ClientCredentialsInput{
+				ClientID:     client.ClientID,
+				ClientSecret: client.ClientSecret,
+				Scopes:       tt.scopes,
+			})
+			if err != nil {
+				t.Fatalf("IssueServiceToken() error = %v", err)
+			}
+
+			claims, err := service.AccessTokenVerifier().Verify(output.AccessToken, pkgauth.TokenTypeService, pkgauth.AudienceAPI)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if claims.ClientID != client.ClientID {
+				t.Errorf("ClientID = %v, want %v", claims.ClientID, client.ClientID)
+			}
+			if got := claims.Scopes(); !reflect.DeepEqual(got, tt.wantScopes) {
+				t.Errorf("Scopes() = %v, want %v", got, tt.wantScopes)
+			}
+			if !output.ExpiresAt.After(time.Now()) {
+				t.Errorf("ExpiresAt = %v, want a future time", output.ExpiresAt)
+			}
+
+			// Service tokens cannot be used as user access tokens
+			assertTokenValid(t, service, output.AccessToken, false)
+		})
+	}
+}
+
+func TestIssueServiceToken_RejectsInvalidRequests(t *testing.T) {
+	service, _ := newTestService(t)
+	client := createServiceClient(t, service)
+
+	tests := []struct {
+		name    string
+		input   appauth.ClientCredentialsInput
+		wantErr error
+	}{
+		{
+			name:    "unknown client",
+			input:   appauth.ClientCredentialsInput{ClientID: "unknown", ClientSecret: client.ClientSecret},
+			wantErr: pkgerrors.ErrUnauthenticated,
+		},
+		{
+			name:    "wrong secret",
+			input:   appauth.ClientCredentialsInput{ClientID: client.ClientID, ClientSecret: "wrong-secret"},
+			wantErr: pkgerrors.ErrUnauthenticated,
+		},
+		{
+			name: "scope not granted to the client",
+			input: appauth.ClientCredentialsInput{
+				ClientID:     client.ClientID,
+				ClientSecret: client.ClientSecret,
+				Scopes:       []string{pkgauth.ScopePaymentRefund},
+			},
+			wantErr: pkgerrors.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.IssueServiceToken(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("IssueServiceToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateServiceClient_RejectsInvalidInput(t *testing.T) {
+	service, _ := newTestService(t)
+	ctx := context.Background()
+	createServiceClient(t, service)
+
+	tests := []struct {
+		name    string
+		input   appauth.CreateServiceClientInput
+		wantErr error
+	}{
+		{
+			name:    "malformed client id",
+			input:   appauth.CreateServiceClientInput{ClientID: "Order Service", Scopes: []string{pkgauth.ScopeProductStock}},
+			wantErr: pkgerrors.ErrInvalidArgument,
+		},
+		{
+			name:    "no scopes",
+			input:   appauth.CreateServiceClientInput{ClientID: "payment-service"},
+			wantErr: pkgerrors.ErrInvalidArgument,
+		},
+		{
+			name:    "malformed scope",
+			input:   appauth.CreateServiceClientInput{ClientID: "payment-service", Scopes: []string{"everything"}},
+			wantErr: pkgerrors.ErrInvalidArgument,
+		},
+		{
+			name:    "client id taken",
+			input:   appauth.CreateServiceClientInput{ClientID: "order-service", Scopes: []string{pkgauth.ScopeProductStock}},
+			wantErr: pkgerrors.ErrAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateServiceClient(ctx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateServiceClient() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_EnforcesScopes(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	client := createServiceClient(t, service)
+
+	serviceToken, err := service.IssueServiceToken(ctx, appauth.ClientCredentialsInput{
+		ClientID:     client.ClientID,
+		ClientSecret: client.ClientSecret,
+		Scopes:       []string{pkgauth.ScopeProductStock},
+	})
+	if err != nil {
+		t.Fatalf("IssueServiceToken() error = %v", err)
+	}
+
+	interceptor := pkgauth.UnaryServerInterceptor(service.AccessTokenVerifier(), pkgauth.Permissions{
+		"/test.Service/UpdateStock": {pkgauth.RoleStaff},
+		"/test.Service/GetProfile":  nil,
+	}, pkgauth.Scopes{
+		"/test.Service/UpdateStock": pkgauth.ScopeProductStock,
+		"/test.Service/Process":     pkgauth.ScopePaymentProcess,
+		"/test.Service/Reserve":     pkgauth.ScopeProductStock,
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		wantCode codes.Code
+	}{
+		{name: "service with scope", method: "/test.Service/UpdateStock", token: serviceToken.AccessToken, wantCode: codes.OK},
+		{name: "service-only method with scope", method: "/test.Service/Reserve", token: serviceToken.AccessToken, wantCode: codes.OK},
+		{name: "service without scope", method: "/test.Service/Process", token: serviceToken.AccessToken, wantCode: codes.PermissionDenied},
+		{name: "service calling user method", method: "/test.Service/GetProfile", token: serviceToken.AccessToken, wantCode: codes.Unauthenticated},
+		{name: "user without role", method: "/test.Service/UpdateStock", token: registered.AccessToken, wantCode: codes.PermissionDenied},
+		{name: "user calling service-only method", method: "/test.Service/Reserve", token: registered.AccessToken, wantCode: codes.PermissionDenied},
+		{name: "no token", method: "/test.Service/Reserve", wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := ctx
+			if tt.token != "" {
+				callCtx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			}
+
+			_, err := interceptor(callCtx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestClientCredentials_RefreshesExpiringTokens(t *testing.T) {
+	service, _ := newTestService(t)
+	client := createServiceClient(t, service)
+
+	fetches := 0
+	expiresIn := 30 * time.Second
+	credentials := pkgauth.NewClientCredentials(pkgauth.TokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		output, err := service.IssueServiceToken(ctx, appauth.ClientCredentialsInput{
+			ClientID:     client.ClientID,
+			ClientSecret: client.ClientSecret,
+		})
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return output.AccessToken, time.Now().Add(expiresIn), nil
+	}))
+
+	token := func() string {
+		t.Helper()
+		md, err := credentials.GetRequestMetadata(context.Background())
+		if err != nil {
+			t.Fatalf("GetRequestMetadata() error = %v", err)
+		}
+		token, ok := strings.CutPrefix(md["authorization"], "Bearer ")
+		if !ok {
+			t.Fatalf("authorization metadata = %q, want a bearer token", md["authorization"])
+		}
+		return token
+	}
+
+	// Tokens about to expire are replaced before they are sent
+	token()
+	token()
+	if fetches != 2 {
+		t.Errorf("token fetched %d times, want 2", fetches)
+	}
+
+	// Tokens far from expiry are reused
+	expiresIn = time.Hour
+	first := token()
+	if second := token(); second != first || fetches != 3 {
+		t.Errorf("token fetched %d times, want 3", fetches)
+	}
+
+	if _, err := service.AccessTokenVerifier().Verify(first, pkgauth.TokenTypeService, pkgauth.AudienceAPI); err != nil {
+		t.Errorf("attached token is invalid: %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/infrastructure/memory"
+)
+
+// assertEmailVerified checks the verification state reported for an access token
+func assertEmailVerified(t *testing.T, service *appauth.Service, accessToken string, want bool) {
+	t.Helper()
+
+	output, err := service.VerifyToken(context.Background(), accessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if !output.Valid {
+		t.Fatal("VerifyToken() should accept the token")
+	}
+	if output.EmailVerified != want {
+		t.Errorf("VerifyToken().EmailVerified = %v, want %v", output.EmailVerified, want)
+	}
+}
+
+func TestVerifyEmail_WithTokenSentOnRegister(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, registered := newTestService(t, appauth.WithNotifier(notifier))
+	ctx := context.Background()
+
+	if registered.EmailVerified {
+		t.Error("Register().EmailVerified = true, want false")
+	}
+	assertEmailVerified(t, service, registered.AccessToken, false)
+
+	token := notifier.lastVerificationToken(t, "test@example.com")
+	if err := service.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+
+	// Tokens issued before the verification report the new state
+	assertEmailVerified(t, service, registered.AccessToken, true)
+
+	output, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !output.EmailVerified {
+		t.Error("Login().EmailVerified = false, want true")
+	}
+
+	// Following the link again is harmless
+	if err := service.VerifyEmail(ctx, token); err != nil {
+		t.Errorf("VerifyEmail() again error = %v", err)
+	}
+}
+
+func TestVerifyEmail_RejectsInvalidTokens(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, registered := newTestService(t, appauth.WithNotifier(notifier))
+
+	other := pkgauth.NewJWTManager("other-secret", pkgauth.DefaultIssuer, time.Hour)
+	forged, err := other.Generate(
+		registered.UserID, "test@example.com", pkgauth.TokenTypeEmailVerification, pkgauth.AudienceAuth,
+	)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	issuer := pkgauth.NewJWTManager(testJWTSecret, pkgauth.DefaultIssuer, time.Hour)
+	otherEmail, err := issuer.Generate(
+		registered.UserID, "old@example.com", pkgauth.TokenTypeEmailVerification, pkgauth.AudienceAuth,
+	)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "malformed", token: "not-a-token"},
+		{name: "access token", token: registered.AccessToken},
+		{name: "refresh token", token: registered.RefreshToken},
+		{name: "signed with another key", token: forged},
+		{name: "sent to another address", token: otherEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.VerifyEmail(context.Background(), tt.token)
+			if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+				t.Errorf("VerifyEmail() error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+			}
+		})
+	}
+
+	assertEmailVerified(t, service, registered.AccessToken, false)
+}
+
+func TestLogin_EmailVerificationPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  pkgauth.EmailVerificationPolicy
+		wantErr error
+	}{
+		{name: "optional", policy: pkgauth.EmailVerificationOptional},
+		{name: "required for orders", policy: pkgauth.EmailVerificationRequiredForOrders},
+		{name: "required for login", policy: pkgauth.EmailVerificationRequiredForLogin, wantErr: pkgerrors.ErrEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := newRecordingNotifier()
+			service, _ := newTestService(t,
+				appauth.WithNotifier(notifier),
+				appauth.WithEmailVerificationPolicy(tt.policy),
+			)
+			ctx := context.Background()
+			input := appauth.LoginInput{Email: "test@example.com", Password: "password123"}
+
+			_, err := service.Login(ctx, input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() before verification error = %v, want %v", err, tt.wantErr)
+			}
+
+			// A wrong password is reported the same way whether or not the email is verified
+			_, err = service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "wrong-password"})
+			if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+				t.Errorf("Login() with wrong password error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+			}
+
+			if err := service.VerifyEmail(ctx, notifier.lastVerificationToken(t, "test@example.com")); err != nil {
+				t.Fatalf("VerifyEmail() error = %v", err)
+			}
+			if _, err := service.Login(ctx, input); err != nil {
+				t.Errorf("Login() after verification error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRegister_RequiredForLoginIssuesNoTokens(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, registered := newTestService(t,
+		appauth.WithNotifier(notifier),
+		appauth.WithEmailVerificationPolicy(pkgauth.EmailVerificationRequiredForLogin),
+	)
+
+	if registered.AccessToken != "" || registered.RefreshToken != "" {
+		t.Error("Register() should not issue tokens before the email is verified")
+	}
+	if registered.UserID == "" || registered.Email != "test@example.com" {
+		t.Errorf("Register() = %+v, want the registered user", registered)
+	}
+
+	// The verification email is still sent
+	if err := service.VerifyEmail(context.Background(), notifier.lastVerificationToken(t, "test@example.com")); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+}
+
+func TestRefreshToken_RequiredForLoginRejectsUnverifiedUsers(t *testing.T) {
+	repo := memory.NewAuthRepository()
+	newService := func(policy pkgauth.EmailVerificationPolicy, notifier *recordingNotifier) *appauth.Service {
+		return appauth.NewService(repo, testJWTSecret, 15*time.Minute, 720*time.Hour,
+			appauth.WithNotifier(notifier),
+			appauth.WithEmailVerificationPolicy(policy),
+		)
+	}
+	ctx := context.Background()
+
+	// Tokens issued while verification was optional
+	notifier := newRecordingNotifier()
+	registered, err := newService(pkgauth.EmailVerificationOptional, notifier).Register(ctx, appauth.RegisterInput{
+		Email:    "test@example.com",
+		Password: "password123",
+		Name:     "Test User",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	service := newService(pkgauth.EmailVerificationRequiredForLogin, notifier)
+	if _, err := service.RefreshToken(ctx, registered.RefreshToken); !errors.Is(err, pkgerrors.ErrEmailNotVerified) {
+		t.Fatalf("RefreshToken() before verification error = %v, want %v", err, pkgerrors.ErrEmailNotVerified)
+	}
+
+	// Once verified the user signs in again; the rejected token was already exchanged
+	if err := service.VerifyEmail(ctx, notifier.lastVerificationToken(t, "test@example.com")); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	output, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, err := service.RefreshToken(ctx, output.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() after verification error = %v", err)
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, _ := newTestService(t, appauth.WithNotifier(notifier))
+	ctx := context.Background()
+
+	if err := service.ResendVerification(ctx, appauth.ResendVerificationInput{Email: "test@example.com"}); err != nil {
+		t.Fatalf("ResendVerification() error = %v", err)
+	}
+	if got := notifier.verificationCount("test@example.com"); got != 2 {
+		t.Fatalf("verification emails sent = %d, want 2", got)
+	}
+
+	// Unknown accounts look the same to the caller but nothing is sent
+	if err := service.ResendVerification(ctx, appauth.ResendVerificationInput{Email: "unknown@example.com"}); err != nil {
+		t.Errorf("ResendVerification() for unknown email error = %v", err)
+	}
+	if got := notifier.verificationCount("unknown@example.com"); got != 0 {
+		t.Errorf("verification emails sent to unknown email = %d, want 0", got)
+	}
+
+	// The resent token works
+	if err := service.VerifyEmail(ctx, notifier.lastVerificationToken(t, "test@example.com")); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+
+	// Verified addresses are not sent another token
+	if err := service.ResendVerification(ctx, appauth.ResendVerificationInput{Email: "test@example.com"}); err != nil {
+		t.Errorf("ResendVerification() after verification error = %v", err)
+	}
+	if got := notifier.verificationCount("test@example.com"); got != 2 {
+		t.Errorf("verification emails sent after verification = %d, want 2", got)
+	}
+}
+
+func TestResendVerification_RequiresNotifier(t *testing.T) {
+	service, _ := newTestService(t)
+
+	err := service.ResendVerification(context.Background(), appauth.ResendVerificationInput{Email: "test@example.com"})
+	if !errors.Is(err, pkgerrors.ErrUnavailable) {
+		t.Errorf("ResendVerification() error = %v, want %v", err, pkgerrors.ErrUnavailable)
+	}
+}
@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/infrastructure/memory"
+)
+
+func newRSAKey(t *testing.T, id string) *pkgauth.Key {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	return newSigningKey(t, id, privateKey)
+}
+
+func newEd25519Key(t *testing.T, id string) *pkgauth.Key {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	return newSigningKey(t, id, privateKey)
+}
+
+func newSigningKey(t *testing.T, id string, privateKey crypto.Signer) *pkgauth.Key {
+	t.Helper()
+
+	key, err := pkgauth.NewPrivateKey(id, privateKey)
+	if err != nil {
+		t.Fatalf("NewPrivateKey() error = %v", err)
+	}
+	return key
+}
+
+func newKeyRing(t *testing.T, activeID string, keys ...*pkgauth.Key) *pkgauth.KeyRing {
+	t.Helper()
+
+	ring, err := pkgauth.NewKeyRing(activeID, keys...)
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+	return ring
+}
+
+func TestKeyRing_SignsAndVerifiesWithPublishedKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     *pkgauth.Key
+		wantAlg string
+	}{
+		{
+			name:    "RS256",
+			key:     newRSAKey(t, "rsa-1"),
+			wantAlg: "RS256",
+		},
+		{
+			name:    "EdDSA",
+			key:     newEd25519Key(t, "ed-1"),
+			wantAlg: "EdDSA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := appauth.NewServiceWithKeyRing(
+				memory.NewAuthRepository(),
+				newKeyRing(t, tt.key.ID, tt.key),
+				15*time.Minute,
+				720*time.Hour,
+			)
+
+			output, err := service.Register(context.Background(), appauth.RegisterInput{
+				Email:    "test@example.com",
+				Password: "password123",
+				Name:     "Test User",
+			})
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			assertTokenValid(t, service, output.AccessToken, true)
+
+			jwks := service.PublicKeys()
+			if len(jwks.Keys) != 1 {
+				t.Fatalf("PublicKeys() returned %d keys, want 1", len(jwks.Keys))
+			}
+			if jwks.Keys[0].KeyID != tt.key.ID || jwks.Keys[0].Algorithm != tt.wantAlg {
+				t.Errorf("PublicKeys() = %+v, want kid %v alg %v", jwks.Keys[0], tt.key.ID, tt.wantAlg)
+			}
+
+			// A verifier holding only the published keys accepts the token
+			verifierRing, err := pkgauth.NewKeyRingFromJWKS(jwks)
+			if err != nil {
+				t.Fatalf("NewKeyRingFromJWKS() error = %v", err)
+			}
+			verifier := pkgauth.NewJWTManagerWithKeyRing(verifierRing, pkgauth.DefaultIssuer, time.Hour)
+			claims, err := verifier.Verify(output.AccessToken, pkgauth.TokenTypeAccess, pkgauth.AudienceAPI)
+			if err != nil {
+				t.Fatalf("Verify() with published keys error = %v", err)
+			}
+			if claims.UserID != output.UserID {
+				t.Errorf("Verify().UserID = %v, want %v", claims.UserID, output.UserID)
+			}
+
+			// Verify-only key rings cannot sign
+			if _, err := verifier.Generate(output.UserID, output.Email, pkgauth.TokenTypeAccess, pkgauth.AudienceAPI); err == nil {
+				t.Error("Generate() with verify-only key ring should fail")
+			}
+		})
+	}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	oldKey := newRSAKey(t, "key-1")
+	newKey := newEd25519Key(t, "key-2")
+	repo := memory.NewAuthRepository()
+
+	before := appauth.NewServiceWithKeyRing(repo, newKeyRing(t, "key-1", oldKey), 15*time.Minute, 720*time.Hour)
+	output, err := before.Register(context.Background(), appauth.RegisterInput{
+		Email:    "test@example.com",
+		Password: "password123",
+		Name:     "Test User",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// After rotation the old key is kept for verification only
+	rotated := appauth.NewServiceWithKeyRing(repo, newKeyRing(t, "key-2", oldKey, newKey), 15*time.Minute, 720*time.Hour)
+	assertTokenValid(t, rotated, output.AccessToken, true)
+
+	refreshed, err := rotated.RefreshToken(context.Background(), output.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	assertTokenValid(t, rotated, refreshed.AccessToken, true)
+
+	// Once the old key is retired, tokens it signed are rejected
+	retired := appauth.NewServiceWithKeyRing(repo, newKeyRing(t, "key-2", newKey), 15*time.Minute, 720*time.Hour)
+	assertTokenValid(t, retired, output.AccessToken, false)
+	assertTokenValid(t, retired, refreshed.AccessToken, true)
+}
+
+func TestKeyRing_RejectsHMACTokens(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	service := appauth.NewServiceWithKeyRing(memory.NewAuthRepository(), newKeyRing(t, "key-1", key), 15*time.Minute, 720*time.Hour)
+
+	// An HS256 token must not be verified with the public key as HMAC secret
+	hmac := pkgauth.NewJWTManager(testJWTSecret, pkgauth.DefaultIssuer, time.Hour)
+	token, err := hmac.Generate("user-1", "test@example.com", pkgauth.TokenTypeAccess, pkgauth.AudienceAPI)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	assertTokenValid(t, service, token, false)
+}
+
+func TestLoadKeyRing(t *testing.T) {
+	dir := t.TempDir()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, "key-1.pem"), data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ring, err := pkgauth.LoadKeyRing(dir, "key-1")
+	if err != nil {
+		t.Fatalf("LoadKeyRing() error = %v", err)
+	}
+	active, err := ring.Active()
+	if err != nil {
+		t.Fatalf("Active() error = %v", err)
+	}
+	if active.ID != "key-1" || active.Method.Alg() != "EdDSA" {
+		t.Errorf("Active() = %v %v, want key-1 EdDSA", active.ID, active.Method.Alg())
+	}
+
+	// The active key must exist in the directory
+	if _, err := pkgauth.LoadKeyRing(dir, "key-2"); err == nil {
+		t.Error("LoadKeyRing() with missing active key should fail")
+	}
+}
@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+)
+
+// attemptLogin logs in as the given email from a client IP and returns the error
+func attemptLogin(service *appauth.Service, email, password, clientIP string) error {
+	_, err := service.Login(context.Background(), appauth.LoginInput{
+		Email:    email,
+		Password: password,
+		ClientIP: clientIP,
+	})
+	return err
+}
+
+// failLogins makes n failed logins and checks each was rejected for the password
+func failLogins(t *testing.T, service *appauth.Service, email, clientIP string, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		err := attemptLogin(service, email, "wrong-password", clientIP)
+		if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+			t.Fatalf("failed login %d error = %v, want %v", i+1, err, pkgerrors.ErrUnauthenticated)
+		}
+	}
+}
+
+func TestLoginThrottle_DelaysAttemptsAfterFreeFailures(t *testing.T) {
+	service, _ := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		FreeFailures:  2,
+		BaseDelay:     time.Hour,
+		FailureWindow: time.Hour,
+	}))
+
+	failLogins(t, service, "test@example.com", "", 2)
+
+	// Even the right password is not checked during the delay
+	err := attemptLogin(service, "test@example.com", "password123", "")
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Errorf("Login() during delay error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+}
+
+func TestLoginThrottle_LocksAccountUntilUnlocked(t *testing.T) {
+	service, _ := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxAccountFailures: 3,
+		LockoutDuration:    time.Hour,
+		FailureWindow:      time.Hour,
+	}))
+	ctx := context.Background()
+
+	failLogins(t, service, "test@example.com", "192.0.2.1", 3)
+
+	// The lock applies to the account from any IP
+	err := attemptLogin(service, "test@example.com", "password123", "198.51.100.1")
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Fatalf("Login() while locked error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+
+	if err := service.UnlockAccount(ctx, appauth.UnlockAccountInput{Email: "TEST@example.com"}); err != nil {
+		t.Fatalf("UnlockAccount() error = %v", err)
+	}
+	if err := attemptLogin(service, "test@example.com", "password123", "192.0.2.1"); err != nil {
+		t.Errorf("Login() after unlock error = %v", err)
+	}
+}
+
+func TestLoginThrottle_UnlocksAutomatically(t *testing.T) {
+	service, _ := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxAccountFailures: 2,
+		LockoutDuration:    time.Second,
+		FailureWindow:      time.Hour,
+	}))
+
+	failLogins(t, service, "test@example.com", "", 2)
+	err := attemptLogin(service, "test@example.com", "password123", "")
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Fatalf("Login() while locked error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+
+	time.Sleep(time.Second)
+
+	if err := attemptLogin(service, "test@example.com", "password123", ""); err != nil {
+		t.Errorf("Login() after lockout error = %v", err)
+	}
+}
+
+func TestLoginThrottle_LocksClientIPAcrossAccounts(t *testing.T) {
+	service, _ := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxIPFailures:   3,
+		LockoutDuration: time.Hour,
+		FailureWindow:   time.Hour,
+	}))
+
+	// Guessing against many accounts, including ones that do not exist
+	failLogins(t, service, "a@example.com", "192.0.2.1", 1)
+	failLogins(t, service, "b@example.com", "192.0.2.1", 1)
+	failLogins(t, service, "test@example.com", "192.0.2.1", 1)
+
+	err := attemptLogin(service, "test@example.com", "password123", "192.0.2.1")
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Errorf("Login() from locked IP error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+	if err := attemptLogin(service, "test@example.com", "password123", "198.51.100.1"); err != nil {
+		t.Errorf("Login() from another IP error = %v", err)
+	}
+}
+
+func TestLoginThrottle_SuccessfulLoginResetsAccountFailures(t *testing.T) {
+	service, _ := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxAccountFailures: 3,
+		LockoutDuration:    time.Hour,
+		FailureWindow:      time.Hour,
+	}))
+
+	failLogins(t, service, "test@example.com", "", 2)
+	if err := attemptLogin(service, "test@example.com", "password123", ""); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	failLogins(t, service, "test@example.com", "", 2)
+
+	if err := attemptLogin(service, "test@example.com", "password123", ""); err != nil {
+		t.Errorf("Login() after reset error = %v", err)
+	}
+}
+
+func TestLoginThrottle_CountsWrongMFACodes(t *testing.T) {
+	service, registered := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxAccountFailures: 2,
+		LockoutDuration:    time.Hour,
+		FailureWindow:      time.Hour,
+	}))
+	ctx := context.Background()
+	secret, _ := enableMFA(t, service, registered.UserID)
+	challenge := loginWithPassword(t, service)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.VerifyMFA(ctx, appauth.VerifyMFAInput{MFAToken: challenge.MFAToken, Code: "wrong-code"})
+		if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+			t.Fatalf("VerifyMFA() with wrong code error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+		}
+	}
+
+	_, err := service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: challenge.MFAToken,
+		Code:     totpCode(t, secret, 30*time.Second),
+	})
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Errorf("VerifyMFA() while locked error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+}
+
+func TestLoginThrottle_CountsWrongCodesWhenChangingMFA(t *testing.T) {
+	service, registered := newTestService(t, appauth.WithLoginThrottle(appauth.LoginThrottle{
+		MaxAccountFailures: 2,
+		LockoutDuration:    time.Hour,
+		FailureWindow:      time.Hour,
+	}))
+	ctx := context.Background()
+	secret, _ := enableMFA(t, service, registered.UserID)
+	wrong := appauth.MFACodeInput{UserID: registered.UserID, Code: "wrong-code"}
+
+	if _, err := service.GenerateRecoveryCodes(ctx, wrong); !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Fatalf("GenerateRecoveryCodes() with wrong code error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+	if err := service.DisableMFA(ctx, wrong); !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Fatalf("DisableMFA() with wrong code error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+
+	err := service.DisableMFA(ctx, appauth.MFACodeInput{
+		UserID: registered.UserID,
+		Code:   totpCode(t, secret, 30*time.Second),
+	})
+	if !errors.Is(err, pkgerrors.ErrTooManyAttempts) {
+		t.Errorf("DisableMFA() while locked error = %v, want %v", err, pkgerrors.ErrTooManyAttempts)
+	}
+}
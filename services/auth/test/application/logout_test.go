@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+)
+
+// assertTokenValid checks the VerifyToken result for an access token
+func assertTokenValid(t *testing.T, service *appauth.Service, token string, want bool) {
+	t.Helper()
+
+	output, err := service.VerifyToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if output.Valid != want {
+		t.Errorf("VerifyToken().Valid = %v, want %v", output.Valid, want)
+	}
+}
+
+func TestLogout_RevokesSession(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	assertTokenValid(t, service, registered.AccessToken, true)
+
+	err := service.Logout(ctx, appauth.LogoutInput{
+		UserID:      registered.UserID,
+		AccessToken: registered.AccessToken,
+	})
+	if err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	assertTokenValid(t, service, registered.AccessToken, false)
+
+	_, err = service.RefreshToken(ctx, registered.RefreshToken)
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("RefreshToken() after logout error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
+
+func TestLogout_KeepsOtherSessions(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	other, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	err = service.Logout(ctx, appauth.LogoutInput{
+		UserID:      registered.UserID,
+		AccessToken: registered.AccessToken,
+	})
+	if err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	assertTokenValid(t, service, other.AccessToken, true)
+	if _, err := service.RefreshToken(ctx, other.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() for other session error = %v", err)
+	}
+}
+
+func TestLogout_RevokesRotatedAccessTokens(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	refreshed, err := service.RefreshToken(ctx, registered.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	err = service.Logout(ctx, appauth.LogoutInput{
+		UserID:      refreshed.UserID,
+		AccessToken: refreshed.AccessToken,
+	})
+	if err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	// The access token issued before the rotation belongs to the same session
+	assertTokenValid(t, service, registered.AccessToken, false)
+}
+
+func TestLogout_InvalidRequests(t *testing.T) {
+	service, registered := newTestService(t)
+
+	tests := []struct {
+		name    string
+		input   appauth.LogoutInput
+		wantErr error
+	}{
+		{
+			name: "token of another user",
+			input: appauth.LogoutInput{
+				UserID:      "other-user",
+				AccessToken: registered.AccessToken,
+			},
+			wantErr: pkgerrors.ErrPermissionDenied,
+		},
+		{
+			name: "malformed token",
+			input: appauth.LogoutInput{
+				UserID:      registered.UserID,
+				AccessToken: "not-a-jwt",
+			},
+			wantErr: pkgerrors.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.Logout(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Logout() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Rejected requests must not revoke anything
+	assertTokenValid(t, service, registered.AccessToken, true)
+}
+
+func TestLogoutAll_RevokesEverySession(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	other, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if err := service.LogoutAll(ctx, registered.UserID); err != nil {
+		t.Fatalf("LogoutAll() error = %v", err)
+	}
+
+	for _, session := range []*appauth.AuthOutput{registered, other} {
+		assertTokenValid(t, service, session.AccessToken, false)
+		if _, err := service.RefreshToken(ctx, session.RefreshToken); err == nil {
+			t.Error("RefreshToken() after LogoutAll should fail")
+		}
+	}
+}
+
+func TestChangePassword_RevokesEverySession(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	err := service.ChangePassword(ctx, appauth.ChangePasswordInput{
+		UserID:      registered.UserID,
+		OldPassword: "password123",
+		NewPassword: "newPassword456",
+	})
+	if err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+
+	assertTokenValid(t, service, registered.AccessToken, false)
+
+	// A new login with the new password opens a fresh session
+	loggedIn, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "newPassword456",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	assertTokenValid(t, service, loggedIn.AccessToken, true)
+}
@@ -0,0 +1,319 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+)
+
+// totpCode generates the TOTP code for the time step containing now plus offset.
+// Each time step is accepted only once, so tests use consecutive offsets.
+func totpCode(t *testing.T, secret string, offset time.Duration) string {
+	t.Helper()
+
+	code, err := pkgauth.GenerateTOTPCode(secret, time.Now().Add(offset))
+	if err != nil {
+		t.Fatalf("GenerateTOTPCode() error = %v", err)
+	}
+	return code
+}
+
+// enableMFA enrolls the user in MFA using the code of the current time step
+// and returns the secret and the recovery codes
+func enableMFA(t *testing.T, service *appauth.Service, userID string) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := service.EnrollMFA(ctx, userID)
+	if err != nil {
+		t.Fatalf("EnrollMFA() error = %v", err)
+	}
+
+	recoveryCodes, err := service.ConfirmMFA(ctx, appauth.MFACodeInput{
+		UserID: userID,
+		Code:   totpCode(t, enrollment.Secret, 0),
+	})
+	if err != nil {
+		t.Fatalf("ConfirmMFA() error = %v", err)
+	}
+
+	return enrollment.Secret, recoveryCodes
+}
+
+// loginWithPassword logs the test user in and expects an MFA challenge
+func loginWithPassword(t *testing.T, service *appauth.Service) *appauth.AuthOutput {
+	t.Helper()
+
+	output, err := service.Login(context.Background(), appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !output.MFARequired || output.MFAToken == "" {
+		t.Fatal("Login() should return an MFA challenge")
+	}
+	if output.AccessToken != "" || output.RefreshToken != "" {
+		t.Error("Login() should not issue tokens before the second factor")
+	}
+
+	return output
+}
+
+func TestEnrollMFA_ReturnsOTPAuthURI(t *testing.T) {
+	service, registered := newTestService(t)
+
+	enrollment, err := service.EnrollMFA(context.Background(), registered.UserID)
+	if err != nil {
+		t.Fatalf("EnrollMFA() error = %v", err)
+	}
+
+	uri, err := url.Parse(enrollment.OTPAuthURI)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("OTPAuthURI = %v, want otpauth://totp/...", enrollment.OTPAuthURI)
+	}
+	if !strings.Contains(uri.Path, "test@example.com") {
+		t.Errorf("OTPAuthURI path = %v, want account name", uri.Path)
+	}
+	if got := uri.Query().Get("secret"); got != enrollment.Secret {
+		t.Errorf("OTPAuthURI secret = %v, want %v", got, enrollment.Secret)
+	}
+
+	// MFA is not enforced until the enrollment is confirmed
+	output, err := service.Login(context.Background(), appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if output.MFARequired {
+		t.Error("Login() should not require MFA before confirmation")
+	}
+}
+
+func TestVerifyMFA_CompletesLogin(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	secret, _ := enableMFA(t, service, registered.UserID)
+
+	challenge := loginWithPassword(t, service)
+
+	output, err := service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: challenge.MFAToken,
+		Code:     totpCode(t, secret, 30*time.Second),
+	})
+	if err != nil {
+		t.Fatalf("VerifyMFA() error = %v", err)
+	}
+	if output.UserID != registered.UserID {
+		t.Errorf("VerifyMFA().UserID = %v, want %v", output.UserID, registered.UserID)
+	}
+	assertTokenValid(t, service, output.AccessToken, true)
+	if _, err := service.RefreshToken(ctx, output.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() error = %v", err)
+	}
+
+	// The challenge token can be exchanged only once
+	_, err = service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: challenge.MFAToken,
+		Code:     totpCode(t, secret, -30*time.Second),
+	})
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("VerifyMFA() with used challenge error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
+
+func TestVerifyMFA_RejectsInvalidInput(t *testing.T) {
+	service, registered := newTestService(t)
+	secret, _ := enableMFA(t, service, registered.UserID)
+	challenge := loginWithPassword(t, service)
+
+	tests := []struct {
+		name  string
+		input appauth.VerifyMFAInput
+	}{
+		{
+			name: "code already used to confirm enrollment",
+			input: appauth.VerifyMFAInput{
+				MFAToken: challenge.MFAToken,
+				Code:     totpCode(t, secret, 0),
+			},
+		},
+		{
+			name: "wrong code",
+			input: appauth.VerifyMFAInput{
+				MFAToken: challenge.MFAToken,
+				Code:     "not-a-code",
+			},
+		},
+		{
+			name: "access token instead of challenge",
+			input: appauth.VerifyMFAInput{
+				MFAToken: registered.AccessToken,
+				Code:     totpCode(t, secret, 30*time.Second),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.VerifyMFA(context.Background(), tt.input)
+			if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+				t.Errorf("VerifyMFA() error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+			}
+		})
+	}
+}
+
+func TestVerifyMFA_RecoveryCodes(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	_, recoveryCodes := enableMFA(t, service, registered.UserID)
+
+	if len(recoveryCodes) == 0 {
+		t.Fatal("ConfirmMFA() should return recovery codes")
+	}
+
+	// Recovery codes are accepted in any case and without the separator
+	code := strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))
+	output, err := service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: loginWithPassword(t, service).MFAToken,
+		Code:     code,
+	})
+	if err != nil {
+		t.Fatalf("VerifyMFA() with recovery code error = %v", err)
+	}
+	assertTokenValid(t, service, output.AccessToken, true)
+
+	// Each recovery code works once
+	_, err = service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: loginWithPassword(t, service).MFAToken,
+		Code:     recoveryCodes[0],
+	})
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("VerifyMFA() with used recovery code error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+
+	// Regenerating invalidates the previous codes
+	regenerated, err := service.GenerateRecoveryCodes(ctx, appauth.MFACodeInput{
+		UserID: registered.UserID,
+		Code:   recoveryCodes[1],
+	})
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+	if len(regenerated) != len(recoveryCodes) {
+		t.Errorf("GenerateRecoveryCodes() returned %d codes, want %d", len(regenerated), len(recoveryCodes))
+	}
+
+	_, err = service.VerifyMFA(ctx, appauth.VerifyMFAInput{
+		MFAToken: loginWithPassword(t, service).MFAToken,
+		Code:     recoveryCodes[2],
+	})
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("VerifyMFA() with replaced recovery code error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
+
+func TestDisableMFA(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	secret, _ := enableMFA(t, service, registered.UserID)
+
+	err := service.DisableMFA(ctx, appauth.MFACodeInput{
+		UserID: registered.UserID,
+		Code:   totpCode(t, secret, 30*time.Second),
+	})
+	if err != nil {
+		t.Fatalf("DisableMFA() error = %v", err)
+	}
+
+	output, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if output.MFARequired {
+		t.Error("Login() should not require MFA after it was disabled")
+	}
+	assertTokenValid(t, service, output.AccessToken, true)
+}
+
+func TestMFA_InvalidStates(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		run     func(t *testing.T, service *appauth.Service, userID string) error
+		wantErr error
+	}{
+		{
+			name: "confirm without enrollment",
+			run: func(t *testing.T, service *appauth.Service, userID string) error {
+				_, err := service.ConfirmMFA(ctx, appauth.MFACodeInput{UserID: userID, Code: "123456"})
+				return err
+			},
+			wantErr: pkgerrors.ErrFailedPrecondition,
+		},
+		{
+			name: "confirm with wrong code",
+			run: func(t *testing.T, service *appauth.Service, userID string) error {
+				if _, err := service.EnrollMFA(ctx, userID); err != nil {
+					t.Fatalf("EnrollMFA() error = %v", err)
+				}
+				_, err := service.ConfirmMFA(ctx, appauth.MFACodeInput{UserID: userID, Code: "000000x"})
+				return err
+			},
+			wantErr: pkgerrors.ErrUnauthenticated,
+		},
+		{
+			name: "enroll twice",
+			run: func(t *testing.T, service *appauth.Service, userID string) error {
+				enableMFA(t, service, userID)
+				_, err := service.EnrollMFA(ctx, userID)
+				return err
+			},
+			wantErr: pkgerrors.ErrFailedPrecondition,
+		},
+		{
+			name: "disable without MFA",
+			run: func(t *testing.T, service *appauth.Service, userID string) error {
+				return service.DisableMFA(ctx, appauth.MFACodeInput{UserID: userID, Code: "123456"})
+			},
+			wantErr: pkgerrors.ErrFailedPrecondition,
+		},
+		{
+			name: "enroll unknown user",
+			run: func(t *testing.T, service *appauth.Service, userID string) error {
+				_, err := service.EnrollMFA(ctx, "unknown-user")
+				return err
+			},
+			wantErr: pkgerrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, registered := newTestService(t)
+
+			err := tt.run(t, service, registered.UserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
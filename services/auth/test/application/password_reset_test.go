@@ -0,0 +1,203 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	domainauth "github.com/Riku-KANO/kube-ec/services/auth/internal/domain/auth"
+)
+
+// recordingNotifier keeps the tokens it was asked to deliver
+type recordingNotifier struct {
+	mu                 sync.Mutex
+	resetTokens        map[string][]string // email -> tokens
+	verificationTokens map[string][]string // email -> tokens
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{
+		resetTokens:        make(map[string][]string),
+		verificationTokens: make(map[string][]string),
+	}
+}
+
+func (n *recordingNotifier) SendPasswordReset(ctx context.Context, email domainauth.Email, token string, expiresAt time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.resetTokens[email.String()] = append(n.resetTokens[email.String()], token)
+	return nil
+}
+
+func (n *recordingNotifier) SendEmailVerification(ctx context.Context, email domainauth.Email, token string, expiresAt time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.verificationTokens[email.String()] = append(n.verificationTokens[email.String()], token)
+	return nil
+}
+
+// lastResetToken returns the last password reset token sent to the email
+func (n *recordingNotifier) lastResetToken(t *testing.T, email string) string {
+	t.Helper()
+	return n.lastToken(t, n.resetTokens, email, "password reset")
+}
+
+// lastVerificationToken returns the last email verification token sent to the email
+func (n *recordingNotifier) lastVerificationToken(t *testing.T, email string) string {
+	t.Helper()
+	return n.lastToken(t, n.verificationTokens, email, "email verification")
+}
+
+// verificationCount returns the number of email verification tokens sent to the email
+func (n *recordingNotifier) verificationCount(email string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.verificationTokens[email])
+}
+
+func (n *recordingNotifier) lastToken(t *testing.T, sent map[string][]string, email, kind string) string {
+	t.Helper()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	tokens := sent[email]
+	if len(tokens) == 0 {
+		t.Fatalf("no %s token sent to %s", kind, email)
+	}
+	return tokens[len(tokens)-1]
+}
+
+// requestPasswordReset requests a reset for the test user and returns the delivered token
+func requestPasswordReset(t *testing.T, service *appauth.Service, notifier *recordingNotifier) string {
+	t.Helper()
+
+	err := service.RequestPasswordReset(context.Background(), appauth.RequestPasswordResetInput{
+		Email: "test@example.com",
+	})
+	if err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	return notifier.lastResetToken(t, "test@example.com")
+}
+
+func TestPasswordReset_SetsNewPasswordAndRevokesSessions(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, registered := newTestService(t, appauth.WithNotifier(notifier))
+	ctx := context.Background()
+
+	token := requestPasswordReset(t, service, notifier)
+
+	err := service.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+		Token:       token,
+		NewPassword: "newPassword456",
+	})
+	if err != nil {
+		t.Fatalf("ConfirmPasswordReset() error = %v", err)
+	}
+
+	assertTokenValid(t, service, registered.AccessToken, false)
+	if _, err := service.RefreshToken(ctx, registered.RefreshToken); err == nil {
+		t.Error("RefreshToken() after password reset should fail")
+	}
+
+	if _, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"}); err == nil {
+		t.Error("Login() with old password should fail")
+	}
+	if _, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "newPassword456"}); err != nil {
+		t.Errorf("Login() with new password error = %v", err)
+	}
+}
+
+func TestPasswordReset_TokensAreSingleUse(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, _ := newTestService(t, appauth.WithNotifier(notifier))
+	ctx := context.Background()
+
+	older := requestPasswordReset(t, service, notifier)
+	token := requestPasswordReset(t, service, notifier)
+
+	err := service.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+		Token:       token,
+		NewPassword: "newPassword456",
+	})
+	if err != nil {
+		t.Fatalf("ConfirmPasswordReset() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "used token",
+			token: token,
+		},
+		{
+			name:  "token requested before the reset",
+			token: older,
+		},
+		{
+			name:  "unknown token",
+			token: "not-a-reset-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+				Token:       tt.token,
+				NewPassword: "anotherPassword789",
+			})
+			if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+				t.Errorf("ConfirmPasswordReset() error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+			}
+		})
+	}
+}
+
+func TestPasswordReset_InvalidPasswordKeepsToken(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, _ := newTestService(t, appauth.WithNotifier(notifier))
+	ctx := context.Background()
+
+	token := requestPasswordReset(t, service, notifier)
+
+	err := service.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+		Token:       token,
+		NewPassword: "short",
+	})
+	if !errors.Is(err, pkgerrors.ErrInvalidArgument) {
+		t.Fatalf("ConfirmPasswordReset() error = %v, want %v", err, pkgerrors.ErrInvalidArgument)
+	}
+
+	// The token was not consumed by the rejected attempt
+	err = service.ConfirmPasswordReset(ctx, appauth.ConfirmPasswordResetInput{
+		Token:       token,
+		NewPassword: "newPassword456",
+	})
+	if err != nil {
+		t.Errorf("ConfirmPasswordReset() retry error = %v", err)
+	}
+}
+
+func TestRequestPasswordReset_DoesNotRevealUnknownEmail(t *testing.T) {
+	notifier := newRecordingNotifier()
+	service, _ := newTestService(t, appauth.WithNotifier(notifier))
+
+	err := service.RequestPasswordReset(context.Background(), appauth.RequestPasswordResetInput{
+		Email: "unknown@example.com",
+	})
+	if err != nil {
+		t.Errorf("RequestPasswordReset() for unknown email error = %v, want nil", err)
+	}
+	if len(notifier.resetTokens) != 0 {
+		t.Errorf("RequestPasswordReset() sent %d notifications, want 0", len(notifier.resetTokens))
+	}
+}
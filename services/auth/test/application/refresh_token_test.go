@@ -0,0 +1,148 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	"github.com/Riku-KANO/kube-ec/services/auth/internal/infrastructure/memory"
+)
+
+const testJWTSecret = "test-secret"
+
+// newTestService creates a service backed by the in-memory repository and
+// registers a user
+func newTestService(t *testing.T, opts ...appauth.Option) (*appauth.Service, *appauth.AuthOutput) {
+	t.Helper()
+
+	service := appauth.NewService(
+		memory.NewAuthRepository(),
+		testJWTSecret,
+		15*time.Minute,
+		720*time.Hour,
+		opts...,
+	)
+
+	output, err := service.Register(context.Background(), appauth.RegisterInput{
+		Email:    "test@example.com",
+		Password: "password123",
+		Name:     "Test User",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	return service, output
+}
+
+func TestRefreshToken_Rotates(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	refreshed, err := service.RefreshToken(ctx, registered.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	if refreshed.RefreshToken == registered.RefreshToken {
+		t.Error("RefreshToken() should issue a new refresh token")
+	}
+	if refreshed.UserID != registered.UserID {
+		t.Errorf("RefreshToken().UserID = %v, want %v", refreshed.UserID, registered.UserID)
+	}
+
+	// The rotated token can be used once more
+	if _, err := service.RefreshToken(ctx, refreshed.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() with rotated token error = %v", err)
+	}
+}
+
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	refreshed, err := service.RefreshToken(ctx, registered.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	// Replaying the already used token must fail
+	_, err = service.RefreshToken(ctx, registered.RefreshToken)
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Fatalf("RefreshToken() with reused token error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+
+	// The reuse revokes the token that was issued by the legitimate rotation
+	_, err = service.RefreshToken(ctx, refreshed.RefreshToken)
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("RefreshToken() after family revocation error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
+
+func TestRefreshToken_ReuseDoesNotAffectOtherFamilies(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	loggedIn, err := service.Login(ctx, appauth.LoginInput{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if _, err := service.RefreshToken(ctx, registered.RefreshToken); err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if _, err := service.RefreshToken(ctx, registered.RefreshToken); err == nil {
+		t.Fatal("RefreshToken() with reused token should fail")
+	}
+
+	// The session started by Login belongs to another family
+	if _, err := service.RefreshToken(ctx, loggedIn.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() for other session error = %v", err)
+	}
+}
+
+func TestRefreshToken_RejectsUnknownTokens(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	// Correctly signed, but never issued through the service
+	manager := pkgauth.NewJWTManager(testJWTSecret, pkgauth.DefaultIssuer, time.Hour)
+	forged, err := manager.Generate(registered.UserID, registered.Email, pkgauth.TokenTypeRefresh, pkgauth.AudienceAuth)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "not issued by the service",
+			token: forged,
+		},
+		{
+			name:  "access token",
+			token: registered.AccessToken,
+		},
+		{
+			name:  "malformed token",
+			token: "not-a-jwt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RefreshToken(ctx, tt.token)
+			if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+				t.Errorf("RefreshToken() error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+	grpchandler "github.com/Riku-KANO/kube-ec/services/auth/internal/presentation/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// assertRoles checks the roles reported for an access token and carried by it
+func assertRoles(t *testing.T, service *appauth.Service, accessToken string, want []string) {
+	t.Helper()
+
+	output, err := service.VerifyToken(context.Background(), accessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if !reflect.DeepEqual(output.Roles, want) {
+		t.Errorf("VerifyToken().Roles = %v, want %v", output.Roles, want)
+	}
+}
+
+// tokenRoles returns the roles embedded in an access token
+func tokenRoles(t *testing.T, accessToken string) []string {
+	t.Helper()
+
+	manager := pkgauth.NewJWTManager(testJWTSecret, pkgauth.DefaultIssuer, time.Hour)
+	claims, err := manager.Verify(accessToken, pkgauth.TokenTypeAccess, pkgauth.AudienceAPI)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	return claims.Roles
+}
+
+func TestRegister_GrantsCustomerRole(t *testing.T) {
+	service, registered := newTestService(t)
+
+	want := []string{pkgauth.RoleCustomer}
+	if got := tokenRoles(t, registered.AccessToken); !reflect.DeepEqual(got, want) {
+		t.Errorf("access token roles = %v, want %v", got, want)
+	}
+	assertRoles(t, service, registered.AccessToken, want)
+}
+
+func TestAssignRole(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	input := appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleStaff}
+
+	roles, err := service.AssignRole(ctx, input)
+	if err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	want := []string{pkgauth.RoleCustomer, pkgauth.RoleStaff}
+	if !reflect.DeepEqual(roles, want) {
+		t.Errorf("AssignRole() = %v, want %v", roles, want)
+	}
+
+	// Assigning a role twice is harmless
+	if _, err := service.AssignRole(ctx, input); err != nil {
+		t.Errorf("AssignRole() again error = %v", err)
+	}
+
+	// VerifyToken reports the new role for tokens issued before it was assigned
+	assertRoles(t, service, registered.AccessToken, want)
+
+	output, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got := tokenRoles(t, output.AccessToken); !reflect.DeepEqual(got, want) {
+		t.Errorf("access token roles after login = %v, want %v", got, want)
+	}
+}
+
+func TestRevokeRole_LogsOutSessions(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+	input := appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleAdmin}
+
+	if _, err := service.AssignRole(ctx, input); err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	session, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	roles, err := service.RevokeRole(ctx, input)
+	if err != nil {
+		t.Fatalf("RevokeRole() error = %v", err)
+	}
+	if want := []string{pkgauth.RoleCustomer}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("RevokeRole() = %v, want %v", roles, want)
+	}
+
+	// Tokens still carrying the role are no longer accepted
+	assertTokenValid(t, service, session.AccessToken, false)
+	if _, err := service.RefreshToken(ctx, session.RefreshToken); !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("RefreshToken() after revoke error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
+
+func TestRoles_InvalidInput(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "assign malformed role",
+			run: func() error {
+				_, err := service.AssignRole(ctx, appauth.RoleInput{UserID: registered.UserID, Role: "not a role!"})
+				return err
+			},
+			wantErr: pkgerrors.ErrInvalidArgument,
+		},
+		{
+			name: "assign to unknown user",
+			run: func() error {
+				_, err := service.AssignRole(ctx, appauth.RoleInput{UserID: "unknown-user", Role: pkgauth.RoleStaff})
+				return err
+			},
+			wantErr: pkgerrors.ErrNotFound,
+		},
+		{
+			name: "revoke role the user does not have",
+			run: func() error {
+				_, err := service.RevokeRole(ctx, appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleStaff})
+				return err
+			},
+			wantErr: pkgerrors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_EnforcesPermissions(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := service.AssignRole(ctx, appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleStaff}); err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	staff, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	customer, err := service.Register(ctx, appauth.RegisterInput{
+		Email:    "customer@example.com",
+		Password: "password123",
+		Name:     "Customer",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	interceptor := pkgauth.UnaryServerInterceptor(service.AccessTokenVerifier(), pkgauth.Permissions{
+		"/test.Service/Public":  nil,
+		"/test.Service/Manage":  {pkgauth.RoleStaff},
+		"/test.Service/Destroy": {"operator"},
+	}, nil)
+
+	tests := []struct {
+		name     string
+		method   string
+		token    string
+		wantCode codes.Code
+	}{
+		{name: "unlisted method without token", method: "/test.Service/Other", wantCode: codes.OK},
+		{name: "authenticated method without token", method: "/test.Service/Public", wantCode: codes.Unauthenticated},
+		{name: "invalid token", method: "/test.Service/Public", token: "not-a-token", wantCode: codes.Unauthenticated},
+		{name: "refresh token", method: "/test.Service/Public", token: staff.RefreshToken, wantCode: codes.Unauthenticated},
+		{name: "customer calling staff method", method: "/test.Service/Manage", token: customer.AccessToken, wantCode: codes.PermissionDenied},
+		{name: "staff calling staff method", method: "/test.Service/Manage", token: staff.AccessToken, wantCode: codes.OK},
+		{name: "staff calling custom role method", method: "/test.Service/Destroy", token: staff.AccessToken, wantCode: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := ctx
+			if tt.token != "" {
+				callCtx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				if _, ok := pkgauth.FromContext(ctx); !ok && tt.method != "/test.Service/Other" {
+					t.Error("handler should receive the caller's claims")
+				}
+				return nil, nil
+			}
+
+			_, err := interceptor(callCtx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("interceptor code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_UsesCurrentRoles(t *testing.T) {
+	service, registered := newTestService(t)
+	ctx := context.Background()
+
+	interceptor := pkgauth.UnaryServerInterceptor(service.AccessTokenVerifier(), pkgauth.Permissions{
+		authpb.AuthService_AssignRole_FullMethodName: {pkgauth.RoleAdmin},
+	}, nil)
+	handler := grpchandler.NewAuthHandler(service)
+	assignRole := func(token, role string) error {
+		callCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		_, err := interceptor(callCtx, &authpb.RoleRequest{UserId: registered.UserID, Role: role},
+			&grpc.UnaryServerInfo{FullMethod: authpb.AuthService_AssignRole_FullMethodName},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return handler.AssignRole(ctx, req.(*authpb.RoleRequest))
+			})
+		return err
+	}
+
+	// A role granted after the token was issued applies right away
+	if _, err := service.AssignRole(ctx, appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleAdmin}); err != nil {
+		t.Fatalf("AssignRole() error = %v", err)
+	}
+	if err := assignRole(registered.AccessToken, pkgauth.RoleStaff); err != nil {
+		t.Fatalf("AssignRole as admin error = %v", err)
+	}
+	admin, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// A demoted admin cannot grant the role back with a token issued before
+	if _, err := service.RevokeRole(ctx, appauth.RoleInput{UserID: registered.UserID, Role: pkgauth.RoleAdmin}); err != nil {
+		t.Fatalf("RevokeRole() error = %v", err)
+	}
+	if err := assignRole(admin.AccessToken, pkgauth.RoleAdmin); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("AssignRole with a demoted admin's token code = %v, want %v", status.Code(err), codes.Unauthenticated)
+	}
+
+	// A new session of the demoted user is denied as well
+	customer, err := service.Login(ctx, appauth.LoginInput{Email: "test@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if err := assignRole(customer.AccessToken, pkgauth.RoleAdmin); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AssignRole after demotion code = %v, want %v", status.Code(err), codes.PermissionDenied)
+	}
+
+	assertRoles(t, service, customer.AccessToken, []string{pkgauth.RoleCustomer, pkgauth.RoleStaff})
+}
@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgauth "github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	appauth "github.com/Riku-KANO/kube-ec/services/auth/internal/application/auth"
+)
+
+func TestVerifyToken_RejectsWrongTokens(t *testing.T) {
+	service, registered := newTestService(t)
+
+	otherIssuer := pkgauth.NewJWTManager(testJWTSecret, "someone-else", time.Hour)
+	foreignToken, err := otherIssuer.Generate(registered.UserID, registered.Email, pkgauth.TokenTypeAccess, pkgauth.AudienceAPI)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	sameIssuer := pkgauth.NewJWTManager(testJWTSecret, pkgauth.DefaultIssuer, time.Hour)
+	wrongAudience, err := sameIssuer.Generate(registered.UserID, registered.Email, pkgauth.TokenTypeAccess, "another-api")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{
+			name:  "access token",
+			token: registered.AccessToken,
+			want:  true,
+		},
+		{
+			name:  "refresh token",
+			token: registered.RefreshToken,
+			want:  false,
+		},
+		{
+			name:  "token from another issuer",
+			token: foreignToken,
+			want:  false,
+		},
+		{
+			name:  "token for another audience",
+			token: wrongAudience,
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertTokenValid(t, service, tt.token, tt.want)
+		})
+	}
+}
+
+func TestLogout_RejectsRefreshToken(t *testing.T) {
+	service, registered := newTestService(t)
+
+	err := service.Logout(context.Background(), appauth.LogoutInput{
+		UserID:      registered.UserID,
+		AccessToken: registered.RefreshToken,
+	})
+	if !errors.Is(err, pkgerrors.ErrUnauthenticated) {
+		t.Errorf("Logout() with refresh token error = %v, want %v", err, pkgerrors.ErrUnauthenticated)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
+	pb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	// resumeInterval 停止したチェックアウトを探す間隔
+	resumeInterval = time.Minute
+	// staleAfter この時間更新されていない未完了のチェックアウトを停止したものとみなす
+	staleAfter = 2 * checkoutTimeout
+)
+
+// serviceScopes チェックアウトサービスが他サービスを呼び出すのに必要なスコープ
+var serviceScopes = []string{
+	auth.ScopeOrderCreate,
+	auth.ScopeOrderStatus,
+	auth.ScopeProductStock,
+	auth.ScopePaymentProcess,
+	auth.ScopePaymentRefund,
+	auth.ScopePaymentRead,
+}
+
+func main() {
+	// データベース接続
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	log.Println("Successfully connected to database")
+
+	// アクセストークンの検証（JWKS_URL が設定されていれば認証サービスの公開鍵、なければ共有シークレット）
+	verifier, err := auth.NewVerifier(os.Getenv("JWKS_URL"), os.Getenv("JWT_SECRET"))
+	if err != nil {
+		log.Fatalf("JWKS_URL or JWT_SECRET environment variable is required: %v", err)
+	}
+
+	// 他サービスの呼び出しには認証サービスから取得したサービストークンを使う
+	clientID := os.Getenv("CLIENT_ID")
+	clientSecret := os.Getenv("CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		log.Fatal("CLIENT_ID and CLIENT_SECRET environment variables are required")
+	}
+
+	authConn, err := dial(getEnv("AUTH_SERVICE_ADDR", "localhost:50052"))
+	if err != nil {
+		log.Fatalf("Failed to connect to auth service: %v", err)
+	}
+	defer authConn.Close()
+
+	credentials := auth.NewClientCredentials(serviceTokenSource(authpb.NewAuthServiceClient(authConn), clientID, clientSecret))
+
+	orderConn, err := dial(getEnv("ORDER_SERVICE_ADDR", "localhost:50054"), grpc.WithPerRPCCredentials(credentials))
+	if err != nil {
+		log.Fatalf("Failed to connect to order service: %v", err)
+	}
+	defer orderConn.Close()
+
+	productConn, err := dial(getEnv("PRODUCT_SERVICE_ADDR", "localhost:50053"), grpc.WithPerRPCCredentials(credentials))
+	if err != nil {
+		log.Fatalf("Failed to connect to product service: %v", err)
+	}
+	defer productConn.Close()
+
+	paymentConn, err := dial(getEnv("PAYMENT_SERVICE_ADDR", "localhost:50055"), grpc.WithPerRPCCredentials(credentials))
+	if err != nil {
+		log.Fatalf("Failed to connect to payment service: %v", err)
+	}
+	defer paymentConn.Close()
+
+	// リポジトリとサーバーの初期化
+	store := NewSagaRepository(db)
+	coordinator := NewCoordinator(
+		store,
+		orderpb.NewOrderServiceClient(orderConn),
+		productpb.NewProductServiceClient(productConn),
+		paymentpb.NewPaymentServiceClient(paymentConn),
+	)
+	checkoutServer := NewCheckoutServer(coordinator, store)
+
+	// 起動時と定期的に、停止したコーディネーターが残したチェックアウトを再開する
+	go resumeLoop(coordinator)
+
+	// gRPCサーバーの起動
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = "50056"
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	// チェックアウトの実行と参照はログインユーザー（本人のチェックアウトのみ）
+	permissions := auth.Permissions{
+		pb.CheckoutService_Checkout_FullMethodName:    {},
+		pb.CheckoutService_GetCheckout_FullMethodName: {},
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(verifier, permissions, nil)),
+	)
+	pb.RegisterCheckoutServiceServer(grpcServer, checkoutServer)
+
+	// リフレクションを有効化（開発用）
+	reflection.Register(grpcServer)
+
+	log.Printf("Checkout service is running on port %s", port)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
+
+// serviceTokenSource クライアントクレデンシャルでサービストークンを取得する
+func serviceTokenSource(client authpb.AuthServiceClient, clientID, clientSecret string) auth.TokenSource {
+	return auth.TokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+		resp, err := client.IssueServiceToken(ctx, &authpb.ServiceTokenRequest{
+			ClientId:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       serviceScopes,
+		})
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to obtain service token: %w", err)
+		}
+		return resp.AccessToken, time.Unix(resp.ExpiresAt.GetSeconds(), 0), nil
+	})
+}
+
+// resumeLoop 停止したチェックアウトを定期的に再開する
+func resumeLoop(coordinator *Coordinator) {
+	ticker := time.NewTicker(resumeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := coordinator.Resume(context.Background(), staleAfter); err != nil {
+			log.Printf("Failed to resume checkouts: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+func dial(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return grpc.Dial(addr, opts...)
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+)
+
+// SagaRepository checkout_sagas テーブルに保存する SagaStore
+type SagaRepository struct {
+	db *sql.DB
+}
+
+func NewSagaRepository(db *sql.DB) *SagaRepository {
+	return &SagaRepository{db: db}
+}
+
+const sagaColumns = `id, user_id, items, shipping_address, payment_method, step, order_id,
+		total_currency, total_amount, reserved_items, payment_id, transaction_id, refund_id,
+		order_cancelled, failure, failure_message, version, created_at, updated_at`
+
+func (r *SagaRepository) Create(ctx context.Context, saga *Saga) error {
+	itemsJSON, err := json.Marshal(saga.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal items: %w", err)
+	}
+	addressJSON, err := json.Marshal(saga.ShippingAddress)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shipping address: %w", err)
+	}
+
+	query := `
+		INSERT INTO checkout_sagas (id, user_id, items, shipping_address, payment_method, step, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 1, $7, $8)
+	`
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx, query,
+		saga.ID,
+		saga.UserID,
+		itemsJSON,
+		addressJSON,
+		saga.PaymentMethod.String(),
+		string(saga.Step),
+		now,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+
+	saga.Version = 1
+	saga.CreatedAt = now
+	saga.UpdatedAt = now
+	return nil
+}
+
+func (r *SagaRepository) Update(ctx context.Context, saga *Saga) error {
+	reservedJSON, err := json.Marshal(saga.ReservedItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reserved items: %w", err)
+	}
+
+	var currency string
+	var amount int64
+	if saga.TotalAmount != nil {
+		currency = saga.TotalAmount.Currency
+		amount = saga.TotalAmount.Amount
+	}
+
+	// 読み込んだときの version のままの場合のみ更新する
+	query := `
+		UPDATE checkout_sagas
+		SET step = $1, order_id = $2, total_currency = $3, total_amount = $4, reserved_items = $5,
+			payment_id = $6, transaction_id = $7, refund_id = $8, order_cancelled = $9,
+			failure = $10, failure_message = $11, version = version + 1, updated_at = $12
+		WHERE id = $13 AND version = $14
+	`
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		string(saga.Step),
+		saga.OrderID,
+		currency,
+		amount,
+		reservedJSON,
+		saga.PaymentID,
+		saga.TransactionID,
+		saga.RefundID,
+		saga.OrderCancelled,
+		saga.Failure.String(),
+		saga.FailureMessage,
+		now,
+		saga.ID,
+		saga.Version,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSagaConflict
+	}
+
+	saga.Version++
+	saga.UpdatedAt = now
+	return nil
+}
+
+func (r *SagaRepository) Get(ctx context.Context, id string) (*Saga, error) {
+	query := `SELECT ` + sagaColumns + ` FROM checkout_sagas WHERE id = $1`
+
+	saga, err := scanSaga(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("checkout not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return saga, nil
+}
+
+func (r *SagaRepository) ListStale(ctx context.Context, updatedBefore time.Time) ([]*Saga, error) {
+	query := `
+		SELECT ` + sagaColumns + `
+		FROM checkout_sagas
+		WHERE step NOT IN ('completed', 'failed') AND updated_at < $1
+		ORDER BY updated_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []*Saga
+	for rows.Next() {
+		saga, err := scanSaga(rows)
+		if err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, rows.Err()
+}
+
+// scanSaga sagaColumns の順に読み込んだ行をサガに変換する
+func scanSaga(row interface{ Scan(dest ...any) error }) (*Saga, error) {
+	saga := &Saga{TotalAmount: &commonpb.Money{}}
+
+	var itemsJSON, addressJSON, reservedJSON []byte
+	var method, step, failure string
+
+	err := row.Scan(
+		&saga.ID,
+		&saga.UserID,
+		&itemsJSON,
+		&addressJSON,
+		&method,
+		&step,
+		&saga.OrderID,
+		&saga.TotalAmount.Currency,
+		&saga.TotalAmount.Amount,
+		&reservedJSON,
+		&saga.PaymentID,
+		&saga.TransactionID,
+		&saga.RefundID,
+		&saga.OrderCancelled,
+		&failure,
+		&saga.FailureMessage,
+		&saga.Version,
+		&saga.CreatedAt,
+		&saga.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &saga.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	if err := json.Unmarshal(addressJSON, &saga.ShippingAddress); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping address: %w", err)
+	}
+	if err := json.Unmarshal(reservedJSON, &saga.ReservedItems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reserved items: %w", err)
+	}
+
+	saga.Step = Step(step)
+	saga.PaymentMethod = paymentpb.PaymentMethod(paymentpb.PaymentMethod_value[method])
+	saga.Failure = pb.CheckoutFailure(pb.CheckoutFailure_value[failure])
+	return saga, nil
+}
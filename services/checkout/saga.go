@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Step チェックアウトのサガの進行状況
+type Step string
+
+const (
+	StepStarted          Step = "started"           // 受付済み
+	StepOrderCreated     Step = "order_created"     // 注文作成済み
+	StepStockReserved    Step = "stock_reserved"    // 全商品の在庫確保済み
+	StepPaymentCreated   Step = "payment_created"   // 決済作成済み
+	StepPaymentProcessed Step = "payment_processed" // 決済完了。これ以降は補償せず前進する
+	StepCompleted        Step = "completed"         // 注文確定済み
+	StepCompensating     Step = "compensating"      // 失敗したため完了済みのステップを取り消し中
+	StepFailed           Step = "failed"            // 取り消し完了
+)
+
+// ErrSagaConflict 別のコーディネーターが同じサガを更新した
+var ErrSagaConflict = errors.New("checkout was updated by another coordinator")
+
+// SagaItem 注文する商品と数量
+type SagaItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int32  `json:"quantity"`
+}
+
+// Saga チェックアウトのサガの状態
+// ステップが進むたびに保存し、コーディネーターが停止しても再開できるようにする。
+// 決済トークンは保存しない。
+type Saga struct {
+	ID              string
+	UserID          string
+	Items           []SagaItem
+	ShippingAddress *commonpb.Address
+	PaymentMethod   paymentpb.PaymentMethod
+	Step            Step
+	OrderID         string
+	TotalAmount     *commonpb.Money
+	ReservedItems   []SagaItem // 注文IDで確保済みで、まだ確定も解放もしていない在庫
+	PaymentID       string
+	TransactionID   string // 決済完了時に設定される
+	RefundID        string
+	OrderCancelled  bool
+	Failure         pb.CheckoutFailure
+	FailureMessage  string
+	Version         int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Finished サガが完了または取り消し済みか
+func (s *Saga) Finished() bool {
+	return s.Step == StepCompleted || s.Step == StepFailed
+}
+
+// SagaStore サガの保存先
+type SagaStore interface {
+	// Create 新しいサガを保存する
+	Create(ctx context.Context, saga *Saga) error
+	// Update サガを保存して Version を進める
+	// 読み込んだ後に他で更新されていた場合は ErrSagaConflict を返す
+	Update(ctx context.Context, saga *Saga) error
+	// Get サガを取得する
+	Get(ctx context.Context, id string) (*Saga, error)
+	// ListStale updatedBefore 以降更新されていない未完了のサガを返す
+	ListStale(ctx context.Context, updatedBefore time.Time) ([]*Saga, error)
+}
+
+// Coordinator 注文・在庫・決済をまたぐチェックアウトのサガを実行する
+// 途中で失敗した場合は返金、在庫の解放、注文のキャンセルで補償する
+type Coordinator struct {
+	store    SagaStore
+	orders   orderpb.OrderServiceClient
+	products productpb.ProductServiceClient
+	payments paymentpb.PaymentServiceClient
+}
+
+// NewCoordinator creates a new Coordinator.
+// The clients must authenticate with a service token.
+func NewCoordinator(
+	store SagaStore,
+	orders orderpb.OrderServiceClient,
+	products productpb.ProductServiceClient,
+	payments paymentpb.PaymentServiceClient,
+) *Coordinator {
+	return &Coordinator{
+		store:    store,
+		orders:   orders,
+		products: products,
+		payments: payments,
+	}
+}
+
+// Start サガを保存して完了または取り消しまで実行する
+// エラーの場合、サガは最後に保存したステップから Resume で再開される
+func (c *Coordinator) Start(ctx context.Context, saga *Saga, paymentToken string) error {
+	saga.Step = StepStarted
+	if err := c.store.Create(ctx, saga); err != nil {
+		return fmt.Errorf("failed to save checkout: %w", err)
+	}
+	return c.run(ctx, saga, paymentToken)
+}
+
+// Resume staleAfter の間更新されていない未完了のサガを再開する
+// 決済トークンは保存しないため、決済前に止まったサガは取り消す
+func (c *Coordinator) Resume(ctx context.Context, staleAfter time.Duration) error {
+	sagas, err := c.store.ListStale(ctx, time.Now().Add(-staleAfter))
+	if err != nil {
+		return fmt.Errorf("failed to list unfinished checkouts: %w", err)
+	}
+
+	for _, saga := range sagas {
+		if err := c.resume(ctx, saga); err != nil {
+			log.Printf("failed to resume checkout %s at %s: %v", saga.ID, saga.Step, err)
+			continue
+		}
+		log.Printf("resumed checkout %s: %s", saga.ID, saga.Step)
+	}
+	return nil
+}
+
+// resume 停止したサガを引き継いで完了または取り消しまで実行する
+func (c *Coordinator) resume(ctx context.Context, saga *Saga) error {
+	// 先に保存して、他のコーディネーターが同時に再開しないようにする
+	if err := c.store.Update(ctx, saga); err != nil {
+		return err
+	}
+
+	switch saga.Step {
+	case StepStarted, StepOrderCreated, StepStockReserved:
+		if saga.Step == StepStarted {
+			// 注文の作成後、注文IDを保存する前に停止した可能性があるため、作成済みの注文を探して取り消す
+			if err := c.findOrder(ctx, saga); err != nil {
+				return err
+			}
+		}
+		if err := c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL,
+			errors.New("checkout was interrupted before payment")); err != nil {
+			return err
+		}
+	case StepPaymentCreated:
+		// 決済処理中に停止した可能性があるため、決済サービスに結果を問い合わせる
+		if err := c.checkPayment(ctx, saga, true); err != nil {
+			return err
+		}
+	}
+
+	return c.run(ctx, saga, "")
+}
+
+// run 現在のステップから完了または取り消しまでサガを進める
+func (c *Coordinator) run(ctx context.Context, saga *Saga, paymentToken string) error {
+	for !saga.Finished() {
+		var err error
+		switch saga.Step {
+		case StepStarted:
+			err = c.createOrder(ctx, saga)
+		case StepOrderCreated:
+			err = c.reserveStock(ctx, saga)
+		case StepStockReserved:
+			err = c.createPayment(ctx, saga)
+		case StepPaymentCreated:
+			err = c.processPayment(ctx, saga, paymentToken)
+		case StepPaymentProcessed:
+			err = c.confirmOrder(ctx, saga)
+		case StepCompensating:
+			err = c.compensate(ctx, saga)
+		default:
+			err = fmt.Errorf("unknown checkout step %q", saga.Step)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createOrder 注文を作成する
+// 価格は注文サービスが商品カタログから決める。存在しない商品や販売中でない商品は注文サービスが拒否する
+func (c *Coordinator) createOrder(ctx context.Context, saga *Saga) error {
+	items := make([]*orderpb.OrderItem, 0, len(saga.Items))
+	for _, item := range saga.Items {
+		items = append(items, &orderpb.OrderItem{ProductId: item.ProductID, Quantity: item.Quantity})
+	}
+
+	// サガIDを冪等キーにして、再試行しても注文が二重に作成されないようにする
+	order, err := c.orders.CreateOrder(ctx, &orderpb.CreateOrderRequest{
+		UserId:          saga.UserID,
+		Items:           items,
+		ShippingAddress: saga.ShippingAddress,
+		IdempotencyKey:  saga.ID,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE, fmt.Errorf("order rejected: %w", err))
+	default:
+		// 注文が作成されたか分からないため、作成済みであれば取り消す
+		if err := c.findOrder(ctx, saga); err != nil {
+			return err
+		}
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL, fmt.Errorf("failed to create order: %w", err))
+	}
+
+	saga.OrderID = order.Id
+	saga.TotalAmount = order.TotalAmount
+	return c.advance(ctx, saga, StepOrderCreated)
+}
+
+// findOrder サガIDを冪等キーにして作成された注文を探し、あればサガに設定する
+func (c *Coordinator) findOrder(ctx context.Context, saga *Saga) error {
+	order, err := c.orders.GetOrderByIdempotencyKey(ctx, &orderpb.GetOrderByIdempotencyKeyRequest{
+		UserId:         saga.UserID,
+		IdempotencyKey: saga.ID,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		saga.OrderID = order.Id
+		saga.TotalAmount = order.TotalAmount
+		return nil
+	case codes.NotFound:
+		return nil
+	default:
+		return fmt.Errorf("failed to find order of checkout %s: %w", saga.ID, err)
+	}
+}
+
+// reserveStock 注文された全商品の在庫を注文IDで確保する
+// 確保は商品サービスで期限付きで保持され、1商品でも足りなければ何も確保されない
+func (c *Coordinator) reserveStock(ctx context.Context, saga *Saga) error {
+	items := make([]*productpb.StockHold, 0, len(saga.Items))
+	for _, item := range saga.Items {
+		items = append(items, &productpb.StockHold{ProductId: item.ProductID, Quantity: item.Quantity})
+	}
+
+	// 同じ注文IDでの再試行は確保中の内容がそのまま返る
+	_, err := c.products.ReserveStock(ctx, &productpb.ReserveStockRequest{
+		OrderId: saga.OrderID,
+		Items:   items,
+	})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.FailedPrecondition, codes.NotFound:
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK, fmt.Errorf("stock rejected: %w", err))
+	default:
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL, fmt.Errorf("failed to reserve stock: %w", err))
+	}
+
+	saga.ReservedItems = saga.Items
+	return c.advance(ctx, saga, StepStockReserved)
+}
+
+// createPayment 注文の合計金額で決済を作成する
+func (c *Coordinator) createPayment(ctx context.Context, saga *Saga) error {
+	payment, err := c.payments.CreatePayment(ctx, &paymentpb.CreatePaymentRequest{
+		OrderId: saga.OrderID,
+		UserId:  saga.UserID,
+		Amount:  saga.TotalAmount,
+		Method:  saga.PaymentMethod,
+	})
+	if err != nil {
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL, fmt.Errorf("failed to create payment: %w", err))
+	}
+
+	saga.PaymentID = payment.Id
+	return c.advance(ctx, saga, StepPaymentCreated)
+}
+
+// processPayment 決済トークンで決済する
+func (c *Coordinator) processPayment(ctx context.Context, saga *Saga, paymentToken string) error {
+	resp, err := c.payments.ProcessPayment(ctx, &paymentpb.ProcessPaymentRequest{
+		PaymentId:    saga.PaymentID,
+		PaymentToken: paymentToken,
+	})
+	if err != nil {
+		// 決済されたか分からないため決済サービスに問い合わせる
+		log.Printf("failed to process payment %s of checkout %s: %v", saga.PaymentID, saga.ID, err)
+		return c.checkPayment(ctx, saga, false)
+	}
+	if !resp.Success {
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED, errors.New(resp.Message))
+	}
+
+	saga.TransactionID = resp.TransactionId
+	return c.advance(ctx, saga, StepPaymentProcessed)
+}
+
+// checkPayment 決済の状態を問い合わせ、完了していれば先に進め、失敗していれば取り消す
+// 処理中の決済はまだ完了する可能性があるため、エラーを返して Resume で問い合わせ直す。
+// 未処理の決済は、決済トークンを保存しないため再開時（resuming）には処理できず取り消す
+func (c *Coordinator) checkPayment(ctx context.Context, saga *Saga, resuming bool) error {
+	resp, err := c.payments.GetPaymentStatus(ctx, &paymentpb.GetPaymentStatusRequest{PaymentId: saga.PaymentID})
+	if err != nil {
+		return fmt.Errorf("failed to get status of payment %s: %w", saga.PaymentID, err)
+	}
+
+	switch resp.Status {
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED:
+		saga.TransactionID = resp.TransactionId
+		return c.advance(ctx, saga, StepPaymentProcessed)
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED:
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED,
+			fmt.Errorf("payment %s failed", saga.PaymentID))
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING:
+		if resuming {
+			return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL,
+				fmt.Errorf("payment %s was not processed", saga.PaymentID))
+		}
+	}
+	return fmt.Errorf("payment %s is %v; checking it again later", saga.PaymentID, resp.Status)
+}
+
+// confirmOrder 確保した在庫を確定し、決済済みの注文を確定する
+func (c *Coordinator) confirmOrder(ctx context.Context, saga *Saga) error {
+	_, err := c.products.CommitReservation(ctx, &productpb.CommitReservationRequest{OrderId: saga.OrderID})
+	if status.Code(err) == codes.FailedPrecondition {
+		// 決済に時間がかかり確保が期限切れで解放された。在庫は他の注文に売られた可能性があるため返金して取り消す
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK,
+			fmt.Errorf("stock reservation of order %s expired before payment completed: %w", saga.OrderID, err))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit stock reservation of order %s: %w", saga.OrderID, err)
+	}
+
+	_, err = c.orders.UpdateOrderStatus(ctx, &orderpb.UpdateOrderStatusRequest{
+		Id:        saga.OrderID,
+		Status:    orderpb.OrderStatus_ORDER_STATUS_CONFIRMED,
+		PaymentId: saga.PaymentID,
+	})
+	if err != nil {
+		return c.fail(ctx, saga, pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL, fmt.Errorf("failed to confirm order: %w", err))
+	}
+
+	saga.ReservedItems = nil
+	return c.advance(ctx, saga, StepCompleted)
+}
+
+// fail 失敗の理由を記録して取り消しに移る
+func (c *Coordinator) fail(ctx context.Context, saga *Saga, failure pb.CheckoutFailure, cause error) error {
+	log.Printf("checkout %s failed at %s: %v", saga.ID, saga.Step, cause)
+
+	saga.Failure = failure
+	saga.FailureMessage = cause.Error()
+	return c.advance(ctx, saga, StepCompensating)
+}
+
+// compensate 完了済みのステップを逆順に取り消す
+// 各取り消しの後に保存するため、途中で失敗しても再開時に二重に取り消さない
+func (c *Coordinator) compensate(ctx context.Context, saga *Saga) error {
+	if saga.TransactionID != "" && saga.RefundID == "" {
+		resp, err := c.payments.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{
+			PaymentId: saga.PaymentID,
+			Amount:    saga.TotalAmount,
+			Reason:    "checkout failed: " + saga.FailureMessage,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to refund payment %s: %w", saga.PaymentID, err)
+		}
+		if !resp.Success {
+			return fmt.Errorf("failed to refund payment %s: %s", saga.PaymentID, resp.Message)
+		}
+
+		saga.RefundID = resp.RefundId
+		if err := c.store.Update(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	if len(saga.ReservedItems) > 0 {
+		_, err := c.products.ReleaseReservation(ctx, &productpb.ReleaseReservationRequest{OrderId: saga.OrderID})
+		switch status.Code(err) {
+		case codes.OK, codes.NotFound:
+			saga.ReservedItems = nil
+			if err := c.store.Update(ctx, saga); err != nil {
+				return err
+			}
+		case codes.FailedPrecondition:
+			// 注文の確定に失敗したが確保は確定済みのため、差し引かれた在庫を1商品ずつ戻す
+		default:
+			return fmt.Errorf("failed to release stock reservation of order %s: %w", saga.OrderID, err)
+		}
+	}
+
+	for len(saga.ReservedItems) > 0 {
+		item := saga.ReservedItems[len(saga.ReservedItems)-1]
+		_, err := c.products.UpdateStock(ctx, &productpb.UpdateStockRequest{
+			ProductId:      item.ProductID,
+			QuantityChange: item.Quantity,
+			Reason:         productpb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RETURN,
+			ReferenceId:    saga.OrderID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to return stock of %s: %w", item.ProductID, err)
+		}
+
+		saga.ReservedItems = saga.ReservedItems[:len(saga.ReservedItems)-1]
+		if err := c.store.Update(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	if saga.OrderID != "" && !saga.OrderCancelled {
+		_, err := c.orders.UpdateOrderStatus(ctx, &orderpb.UpdateOrderStatusRequest{
+			Id:     saga.OrderID,
+			Status: orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+			Reason: "checkout failed: " + saga.FailureMessage,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel order %s: %w", saga.OrderID, err)
+		}
+
+		saga.OrderCancelled = true
+		if err := c.store.Update(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	return c.advance(ctx, saga, StepFailed)
+}
+
+// advance サガを次のステップに進めて保存する
+func (c *Coordinator) advance(ctx context.Context, saga *Saga, step Step) error {
+	saga.Step = step
+	return c.store.Update(ctx, saga)
+}
@@ -0,0 +1,738 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const declinedPaymentToken = "tok_declined"
+
+// memoryStore is an in-memory SagaStore with the same version check as the repository
+type memoryStore struct {
+	mu    sync.Mutex
+	sagas map[string]Saga
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{sagas: make(map[string]Saga)}
+}
+
+func (s *memoryStore) Create(ctx context.Context, saga *Saga) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saga.Version = 1
+	saga.CreatedAt = time.Now()
+	saga.UpdatedAt = saga.CreatedAt
+	s.sagas[saga.ID] = copySaga(saga)
+	return nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, saga *Saga) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sagas[saga.ID].Version != saga.Version {
+		return ErrSagaConflict
+	}
+	saga.Version++
+	saga.UpdatedAt = time.Now()
+	s.sagas[saga.ID] = copySaga(saga)
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id string) (*Saga, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saga, ok := s.sagas[id]
+	if !ok {
+		return nil, errors.New("checkout not found")
+	}
+	return &saga, nil
+}
+
+func (s *memoryStore) ListStale(ctx context.Context, updatedBefore time.Time) ([]*Saga, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sagas []*Saga
+	for _, saga := range s.sagas {
+		if !saga.Finished() && saga.UpdatedAt.Before(updatedBefore) {
+			saga := saga
+			sagas = append(sagas, &saga)
+		}
+	}
+	return sagas, nil
+}
+
+// copySaga copies the slices of a saga so stored sagas do not share them with the coordinator
+func copySaga(saga *Saga) Saga {
+	stored := *saga
+	stored.Items = append([]SagaItem(nil), saga.Items...)
+	stored.ReservedItems = append([]SagaItem(nil), saga.ReservedItems...)
+	return stored
+}
+
+// fakeOrderClient records orders and their statuses, pricing items from the catalog like the order service
+type fakeOrderClient struct {
+	orderpb.OrderServiceClient
+	catalog    *fakeProductClient
+	statuses   map[string]orderpb.OrderStatus
+	byKey      map[string]*orderpb.Order
+	confirmErr error
+	lostReply  bool // the order is created but the reply is lost
+}
+
+func (c *fakeOrderClient) CreateOrder(ctx context.Context, req *orderpb.CreateOrderRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if order, ok := c.byKey[req.IdempotencyKey]; ok {
+		return order, nil
+	}
+
+	var total *commonpb.Money
+	items := make([]*orderpb.OrderItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		product, ok := c.catalog.products[item.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "product %s not found", item.ProductId)
+		}
+		if !product.IsActive {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is not for sale", item.ProductId)
+		}
+		if total == nil {
+			total = &commonpb.Money{Currency: product.Price.Currency}
+		}
+		if product.Price.Currency != total.Currency {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is priced in %s", item.ProductId, product.Price.Currency)
+		}
+
+		subtotal := product.Price.Amount * int64(item.Quantity)
+		total.Amount += subtotal
+		items = append(items, &orderpb.OrderItem{
+			ProductId:   product.Id,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   product.Price,
+			Subtotal:    &commonpb.Money{Currency: product.Price.Currency, Amount: subtotal},
+		})
+	}
+
+	order := &orderpb.Order{
+		Id:          "order-1",
+		UserId:      req.UserId,
+		Items:       items,
+		TotalAmount: total,
+		Status:      orderpb.OrderStatus_ORDER_STATUS_PENDING,
+	}
+	c.statuses[order.Id] = order.Status
+	c.byKey[req.IdempotencyKey] = order
+	if c.lostReply {
+		return nil, status.Error(codes.Unavailable, "connection reset")
+	}
+	return order, nil
+}
+
+func (c *fakeOrderClient) GetOrderByIdempotencyKey(ctx context.Context, req *orderpb.GetOrderByIdempotencyKeyRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	order, ok := c.byKey[req.IdempotencyKey]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return order, nil
+}
+
+func (c *fakeOrderClient) UpdateOrderStatus(ctx context.Context, req *orderpb.UpdateOrderStatusRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if req.Status == orderpb.OrderStatus_ORDER_STATUS_CONFIRMED && c.confirmErr != nil {
+		return nil, c.confirmErr
+	}
+	c.statuses[req.Id] = req.Status
+	return &orderpb.Order{Id: req.Id, Status: req.Status}, nil
+}
+
+// fakeProductClient holds the catalog, its stock and the stock reservations of orders
+type fakeProductClient struct {
+	productpb.ProductServiceClient
+	products     map[string]*productpb.Product
+	reserved     map[string]int32
+	reservations map[string]*productpb.Reservation
+}
+
+func (c *fakeProductClient) UpdateStock(ctx context.Context, req *productpb.UpdateStockRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	product := c.products[req.ProductId]
+	product.StockQuantity += req.QuantityChange
+	return product, nil
+}
+
+func (c *fakeProductClient) ReserveStock(ctx context.Context, req *productpb.ReserveStockRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	if reservation, ok := c.reservations[req.OrderId]; ok {
+		if reservation.Status != productpb.ReservationStatus_RESERVATION_STATUS_HELD {
+			return nil, status.Errorf(codes.FailedPrecondition, "reservation is %v", reservation.Status)
+		}
+		return reservation, nil
+	}
+
+	for _, item := range req.Items {
+		product, ok := c.products[item.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "product %s not found", item.ProductId)
+		}
+		if product.StockQuantity-c.reserved[item.ProductId] < item.Quantity {
+			return nil, status.Errorf(codes.FailedPrecondition, "insufficient stock: %s", item.ProductId)
+		}
+	}
+	for _, item := range req.Items {
+		c.reserved[item.ProductId] += item.Quantity
+	}
+
+	reservation := &productpb.Reservation{
+		OrderId: req.OrderId,
+		Status:  productpb.ReservationStatus_RESERVATION_STATUS_HELD,
+		Items:   req.Items,
+	}
+	c.reservations[req.OrderId] = reservation
+	return reservation, nil
+}
+
+func (c *fakeProductClient) CommitReservation(ctx context.Context, req *productpb.CommitReservationRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	return c.close(req.OrderId, productpb.ReservationStatus_RESERVATION_STATUS_COMMITTED)
+}
+
+func (c *fakeProductClient) ReleaseReservation(ctx context.Context, req *productpb.ReleaseReservationRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	return c.close(req.OrderId, productpb.ReservationStatus_RESERVATION_STATUS_RELEASED)
+}
+
+// expire releases a held reservation as the product service's sweeper would
+func (c *fakeProductClient) expire(orderID string) {
+	reservation := c.reservations[orderID]
+	for _, item := range reservation.Items {
+		c.reserved[item.ProductId] -= item.Quantity
+	}
+	reservation.Status = productpb.ReservationStatus_RESERVATION_STATUS_EXPIRED
+}
+
+func (c *fakeProductClient) close(orderID string, to productpb.ReservationStatus) (*productpb.Reservation, error) {
+	reservation, ok := c.reservations[orderID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "reservation not found")
+	}
+
+	switch {
+	case reservation.Status == to:
+		return reservation, nil
+	case reservation.Status == productpb.ReservationStatus_RESERVATION_STATUS_EXPIRED && to == productpb.ReservationStatus_RESERVATION_STATUS_RELEASED:
+		return reservation, nil
+	case reservation.Status != productpb.ReservationStatus_RESERVATION_STATUS_HELD:
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %v", reservation.Status)
+	}
+
+	for _, item := range reservation.Items {
+		c.reserved[item.ProductId] -= item.Quantity
+		if to == productpb.ReservationStatus_RESERVATION_STATUS_COMMITTED {
+			c.products[item.ProductId].StockQuantity -= item.Quantity
+		}
+	}
+	reservation.Status = to
+	return reservation, nil
+}
+
+// fakePaymentClient processes payments, declining declinedPaymentToken
+type fakePaymentClient struct {
+	paymentpb.PaymentServiceClient
+	payments map[string]*paymentpb.Payment
+	refunds  map[string]int64
+}
+
+func (c *fakePaymentClient) CreatePayment(ctx context.Context, req *paymentpb.CreatePaymentRequest, opts ...grpc.CallOption) (*paymentpb.Payment, error) {
+	payment := &paymentpb.Payment{
+		Id:      "payment-1",
+		OrderId: req.OrderId,
+		UserId:  req.UserId,
+		Amount:  req.Amount,
+		Method:  req.Method,
+		Status:  paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING,
+	}
+	c.payments[payment.Id] = payment
+	return payment, nil
+}
+
+func (c *fakePaymentClient) ProcessPayment(ctx context.Context, req *paymentpb.ProcessPaymentRequest, opts ...grpc.CallOption) (*paymentpb.ProcessPaymentResponse, error) {
+	payment := c.payments[req.PaymentId]
+	if req.PaymentToken == declinedPaymentToken {
+		payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		return &paymentpb.ProcessPaymentResponse{Success: false, Message: "card declined"}, nil
+	}
+	payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED
+	payment.TransactionId = "txn-1"
+	return &paymentpb.ProcessPaymentResponse{Success: true, TransactionId: payment.TransactionId}, nil
+}
+
+func (c *fakePaymentClient) GetPaymentStatus(ctx context.Context, req *paymentpb.GetPaymentStatusRequest, opts ...grpc.CallOption) (*paymentpb.GetPaymentStatusResponse, error) {
+	payment := c.payments[req.PaymentId]
+	return &paymentpb.GetPaymentStatusResponse{Status: payment.Status, TransactionId: payment.TransactionId}, nil
+}
+
+func (c *fakePaymentClient) RefundPayment(ctx context.Context, req *paymentpb.RefundPaymentRequest, opts ...grpc.CallOption) (*paymentpb.RefundPaymentResponse, error) {
+	c.refunds[req.PaymentId] += req.Amount.Amount
+	c.payments[req.PaymentId].Status = paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	return &paymentpb.RefundPaymentResponse{Success: true, RefundId: "refund-1"}, nil
+}
+
+type testEnv struct {
+	store       *memoryStore
+	orders      *fakeOrderClient
+	products    *fakeProductClient
+	payments    *fakePaymentClient
+	coordinator *Coordinator
+}
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		store: newMemoryStore(),
+		orders: &fakeOrderClient{
+			statuses: make(map[string]orderpb.OrderStatus),
+			byKey:    make(map[string]*orderpb.Order),
+		},
+		products: &fakeProductClient{
+			products: map[string]*productpb.Product{
+				"book": {Id: "book", Name: "Book", Price: &commonpb.Money{Currency: "JPY", Amount: 1500}, StockQuantity: 10, IsActive: true},
+				"pen":  {Id: "pen", Name: "Pen", Price: &commonpb.Money{Currency: "JPY", Amount: 200}, StockQuantity: 3, IsActive: true},
+				"old":  {Id: "old", Name: "Old", Price: &commonpb.Money{Currency: "JPY", Amount: 100}, StockQuantity: 5, IsActive: false},
+				"usd":  {Id: "usd", Name: "Import", Price: &commonpb.Money{Currency: "USD", Amount: 10}, StockQuantity: 5, IsActive: true},
+			},
+			reserved:     make(map[string]int32),
+			reservations: make(map[string]*productpb.Reservation),
+		},
+		payments: &fakePaymentClient{
+			payments: make(map[string]*paymentpb.Payment),
+			refunds:  make(map[string]int64),
+		},
+	}
+	env.orders.catalog = env.products
+	env.coordinator = NewCoordinator(env.store, env.orders, env.products, env.payments)
+	return env
+}
+
+func newSaga(items ...SagaItem) *Saga {
+	return &Saga{
+		ID:              "checkout-1",
+		UserID:          "user-1",
+		Items:           items,
+		ShippingAddress: &commonpb.Address{PostalCode: "100-0001", Prefecture: "東京都"},
+		PaymentMethod:   paymentpb.PaymentMethod_PAYMENT_METHOD_CREDIT_CARD,
+	}
+}
+
+// assertStock checks the stock of each product and that no stock is left held
+func (env *testEnv) assertStock(t *testing.T, want map[string]int32) {
+	t.Helper()
+
+	for id, stock := range want {
+		if got := env.products.products[id].StockQuantity; got != stock {
+			t.Errorf("stock of %s = %d, want %d", id, got, stock)
+		}
+		if held := env.products.reserved[id]; held != 0 {
+			t.Errorf("held stock of %s = %d, want 0", id, held)
+		}
+	}
+}
+
+func TestCoordinator_Start(t *testing.T) {
+	env := newTestEnv()
+	saga := newSaga(SagaItem{ProductID: "book", Quantity: 2}, SagaItem{ProductID: "pen", Quantity: 3})
+
+	if err := env.coordinator.Start(context.Background(), saga, "tok_visa"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	if saga.Step != StepCompleted {
+		t.Errorf("step = %s, want %s", saga.Step, StepCompleted)
+	}
+	if saga.TotalAmount.Amount != 3600 {
+		t.Errorf("total amount = %d, want 3600", saga.TotalAmount.Amount)
+	}
+	if saga.TransactionID != "txn-1" {
+		t.Errorf("transaction id = %q, want %q", saga.TransactionID, "txn-1")
+	}
+	if got := env.orders.statuses[saga.OrderID]; got != orderpb.OrderStatus_ORDER_STATUS_CONFIRMED {
+		t.Errorf("order status = %v, want confirmed", got)
+	}
+	env.assertStock(t, map[string]int32{"book": 8, "pen": 0})
+
+	stored, _ := env.store.Get(context.Background(), saga.ID)
+	if stored.Step != StepCompleted {
+		t.Errorf("stored step = %s, want %s", stored.Step, StepCompleted)
+	}
+}
+
+func TestCoordinator_Start_Compensates(t *testing.T) {
+	tests := []struct {
+		name         string
+		items        []SagaItem
+		paymentToken string
+		confirmErr   error
+		wantFailure  pb.CheckoutFailure
+		wantRefund   int64
+		wantCancel   bool
+	}{
+		{
+			name:        "unknown product",
+			items:       []SagaItem{{ProductID: "missing", Quantity: 1}},
+			wantFailure: pb.CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE,
+		},
+		{
+			name:        "inactive product",
+			items:       []SagaItem{{ProductID: "book", Quantity: 1}, {ProductID: "old", Quantity: 1}},
+			wantFailure: pb.CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE,
+		},
+		{
+			name:        "mixed currencies",
+			items:       []SagaItem{{ProductID: "book", Quantity: 1}, {ProductID: "usd", Quantity: 1}},
+			wantFailure: pb.CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE,
+		},
+		{
+			name:        "out of stock reserves nothing",
+			items:       []SagaItem{{ProductID: "book", Quantity: 2}, {ProductID: "pen", Quantity: 4}},
+			wantFailure: pb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK,
+			wantCancel:  true,
+		},
+		{
+			name:         "declined payment",
+			items:        []SagaItem{{ProductID: "book", Quantity: 2}},
+			paymentToken: declinedPaymentToken,
+			wantFailure:  pb.CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED,
+			wantCancel:   true,
+		},
+		{
+			name:        "order cannot be confirmed after payment",
+			items:       []SagaItem{{ProductID: "book", Quantity: 2}},
+			confirmErr:  status.Error(codes.Unavailable, "order service is down"),
+			wantFailure: pb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL,
+			wantRefund:  3000,
+			wantCancel:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv()
+			env.orders.confirmErr = tt.confirmErr
+			saga := newSaga(tt.items...)
+			token := tt.paymentToken
+			if token == "" {
+				token = "tok_visa"
+			}
+
+			if err := env.coordinator.Start(context.Background(), saga, token); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			if saga.Step != StepFailed {
+				t.Errorf("step = %s, want %s", saga.Step, StepFailed)
+			}
+			if saga.Failure != tt.wantFailure {
+				t.Errorf("failure = %v, want %v", saga.Failure, tt.wantFailure)
+			}
+			if len(saga.ReservedItems) != 0 {
+				t.Errorf("reserved items = %v, want none", saga.ReservedItems)
+			}
+			env.assertStock(t, map[string]int32{"book": 10, "pen": 3})
+
+			if got := env.payments.refunds["payment-1"]; got != tt.wantRefund {
+				t.Errorf("refunded = %d, want %d", got, tt.wantRefund)
+			}
+			cancelled := env.orders.statuses["order-1"] == orderpb.OrderStatus_ORDER_STATUS_CANCELLED
+			if cancelled != tt.wantCancel {
+				t.Errorf("order cancelled = %v, want %v", cancelled, tt.wantCancel)
+			}
+		})
+	}
+}
+
+// interrupt runs the saga up to a step and leaves it as a crashed coordinator would
+func interrupt(t *testing.T, env *testEnv, saga *Saga, until Step) {
+	t.Helper()
+
+	ctx := context.Background()
+	saga.Step = StepStarted
+	if err := env.store.Create(ctx, saga); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	steps := []struct {
+		step Step
+		run  func() error
+	}{
+		{StepOrderCreated, func() error { return env.coordinator.createOrder(ctx, saga) }},
+		{StepStockReserved, func() error { return env.coordinator.reserveStock(ctx, saga) }},
+		{StepPaymentCreated, func() error { return env.coordinator.createPayment(ctx, saga) }},
+		{StepPaymentProcessed, func() error { return env.coordinator.processPayment(ctx, saga, "tok_visa") }},
+	}
+	for _, s := range steps {
+		if err := s.run(); err != nil {
+			t.Fatalf("%s error = %v", s.step, err)
+		}
+		if s.step == until {
+			return
+		}
+	}
+}
+
+func TestCoordinator_Resume(t *testing.T) {
+	tests := []struct {
+		name           string
+		until          Step
+		paymentDone    bool
+		wantStep       Step
+		wantStock      int32
+		wantOrder      orderpb.OrderStatus
+		wantPaidStatus paymentpb.PaymentStatus
+	}{
+		{
+			name:      "before payment is compensated",
+			until:     StepStockReserved,
+			wantStep:  StepFailed,
+			wantStock: 10,
+			wantOrder: orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+		},
+		{
+			name:           "payment created but not processed is compensated",
+			until:          StepPaymentCreated,
+			wantStep:       StepFailed,
+			wantStock:      10,
+			wantOrder:      orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+			wantPaidStatus: paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING,
+		},
+		{
+			name:           "payment processed without saving the step is completed",
+			until:          StepPaymentCreated,
+			paymentDone:    true,
+			wantStep:       StepCompleted,
+			wantStock:      8,
+			wantOrder:      orderpb.OrderStatus_ORDER_STATUS_CONFIRMED,
+			wantPaidStatus: paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED,
+		},
+		{
+			name:           "after payment is completed",
+			until:          StepPaymentProcessed,
+			wantStep:       StepCompleted,
+			wantStock:      8,
+			wantOrder:      orderpb.OrderStatus_ORDER_STATUS_CONFIRMED,
+			wantPaidStatus: paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv()
+			ctx := context.Background()
+			saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+			interrupt(t, env, saga, tt.until)
+			if tt.paymentDone {
+				// The payment went through but the coordinator stopped before saving it
+				env.payments.payments[saga.PaymentID].Status = paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED
+			}
+
+			if err := env.coordinator.Resume(ctx, -time.Second); err != nil {
+				t.Fatalf("Resume() error = %v", err)
+			}
+
+			stored, _ := env.store.Get(ctx, saga.ID)
+			if stored.Step != tt.wantStep {
+				t.Errorf("step = %s, want %s", stored.Step, tt.wantStep)
+			}
+			env.assertStock(t, map[string]int32{"book": tt.wantStock})
+			if got := env.orders.statuses[stored.OrderID]; got != tt.wantOrder {
+				t.Errorf("order status = %v, want %v", got, tt.wantOrder)
+			}
+			if payment, ok := env.payments.payments["payment-1"]; ok && payment.Status != tt.wantPaidStatus {
+				t.Errorf("payment status = %v, want %v", payment.Status, tt.wantPaidStatus)
+			}
+			if len(env.payments.refunds) != 0 {
+				t.Errorf("refunds = %v, want none", env.payments.refunds)
+			}
+
+			// Finished checkouts are not resumed again
+			if sagas, _ := env.store.ListStale(ctx, time.Now().Add(time.Second)); len(sagas) != 0 {
+				t.Errorf("unfinished checkouts = %d, want 0", len(sagas))
+			}
+		})
+	}
+}
+
+func TestCoordinator_Resume_SkipsClaimedCheckout(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+	saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+	interrupt(t, env, saga, StepStockReserved)
+
+	// Two coordinators find the same checkout; the other one claims it first
+	stale, _ := env.store.ListStale(ctx, time.Now().Add(time.Second))
+	claimed, _ := env.store.Get(ctx, saga.ID)
+	if err := env.store.Update(ctx, claimed); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := env.coordinator.resume(ctx, stale[0]); !errors.Is(err, ErrSagaConflict) {
+		t.Errorf("resume() error = %v, want %v", err, ErrSagaConflict)
+	}
+	if held := env.products.reserved["book"]; held != 2 {
+		t.Errorf("held stock of book = %d, want 2", held)
+	}
+	if got := env.orders.statuses[saga.OrderID]; got != orderpb.OrderStatus_ORDER_STATUS_PENDING {
+		t.Errorf("order status = %v, want pending", got)
+	}
+}
+
+func TestCoordinator_Start_ReservationExpired(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+	saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+	interrupt(t, env, saga, StepPaymentProcessed)
+
+	// The payment took longer than the hold and the sweeper released it,
+	// so CommitReservation fails with FailedPrecondition
+	env.products.expire(saga.OrderID)
+
+	if err := env.coordinator.run(ctx, saga, ""); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	if saga.Step != StepFailed || saga.Failure != pb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK {
+		t.Errorf("step = %s (%v), want %s (out of stock)", saga.Step, saga.Failure, StepFailed)
+	}
+	if got := env.orders.statuses[saga.OrderID]; got != orderpb.OrderStatus_ORDER_STATUS_CANCELLED {
+		t.Errorf("order status = %v, want cancelled", got)
+	}
+	if got := env.payments.refunds["payment-1"]; got != 3000 {
+		t.Errorf("refunded = %d, want 3000", got)
+	}
+	// The stock was never taken, so none is sold or returned
+	env.assertStock(t, map[string]int32{"book": 10})
+}
+
+func TestCoordinator_OrderCreatedWithoutReply(t *testing.T) {
+	t.Run("reply lost", func(t *testing.T) {
+		env := newTestEnv()
+		env.orders.lostReply = true
+		saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+
+		if err := env.coordinator.Start(context.Background(), saga, "tok_visa"); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if saga.Step != StepFailed || saga.OrderID != "order-1" {
+			t.Errorf("step = %s, order = %q, want failed with order-1", saga.Step, saga.OrderID)
+		}
+		if got := env.orders.statuses["order-1"]; got != orderpb.OrderStatus_ORDER_STATUS_CANCELLED {
+			t.Errorf("order status = %v, want cancelled", got)
+		}
+	})
+
+	t.Run("coordinator stopped before saving the order", func(t *testing.T) {
+		env := newTestEnv()
+		ctx := context.Background()
+		saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+		saga.Step = StepStarted
+		if err := env.store.Create(ctx, saga); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := env.orders.CreateOrder(ctx, &orderpb.CreateOrderRequest{
+			UserId:         saga.UserID,
+			Items:          []*orderpb.OrderItem{{ProductId: "book", Quantity: 2}},
+			IdempotencyKey: saga.ID,
+		}); err != nil {
+			t.Fatalf("CreateOrder() error = %v", err)
+		}
+
+		if err := env.coordinator.Resume(ctx, -time.Second); err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+
+		stored, _ := env.store.Get(ctx, saga.ID)
+		if stored.Step != StepFailed || stored.OrderID != "order-1" {
+			t.Errorf("step = %s, order = %q, want failed with order-1", stored.Step, stored.OrderID)
+		}
+		if got := env.orders.statuses["order-1"]; got != orderpb.OrderStatus_ORDER_STATUS_CANCELLED {
+			t.Errorf("order status = %v, want cancelled", got)
+		}
+	})
+}
+
+func TestCoordinator_Resume_PaymentStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    paymentpb.PaymentStatus
+		wantStep  Step
+		wantOrder orderpb.OrderStatus
+	}{
+		{
+			name:      "processing is checked again later",
+			status:    paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING,
+			wantStep:  StepPaymentCreated,
+			wantOrder: orderpb.OrderStatus_ORDER_STATUS_PENDING,
+		},
+		{
+			name:      "failed is compensated",
+			status:    paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED,
+			wantStep:  StepFailed,
+			wantOrder: orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv()
+			ctx := context.Background()
+			saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+			interrupt(t, env, saga, StepPaymentCreated)
+			env.payments.payments[saga.PaymentID].Status = tt.status
+
+			if err := env.coordinator.Resume(ctx, -time.Second); err != nil {
+				t.Fatalf("Resume() error = %v", err)
+			}
+
+			stored, _ := env.store.Get(ctx, saga.ID)
+			if stored.Step != tt.wantStep {
+				t.Errorf("step = %s, want %s", stored.Step, tt.wantStep)
+			}
+			if got := env.orders.statuses[saga.OrderID]; got != tt.wantOrder {
+				t.Errorf("order status = %v, want %v", got, tt.wantOrder)
+			}
+			if len(env.payments.refunds) != 0 {
+				t.Errorf("refunds = %v, want none", env.payments.refunds)
+			}
+		})
+	}
+
+	// A processing payment that completes later finishes the checkout
+	env := newTestEnv()
+	ctx := context.Background()
+	saga := newSaga(SagaItem{ProductID: "book", Quantity: 2})
+	interrupt(t, env, saga, StepPaymentCreated)
+	payment := env.payments.payments[saga.PaymentID]
+	payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING
+	if err := env.coordinator.Resume(ctx, -time.Second); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED
+	payment.TransactionId = "txn-1"
+	if err := env.coordinator.Resume(ctx, -time.Second); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	stored, _ := env.store.Get(ctx, saga.ID)
+	if stored.Step != StepCompleted {
+		t.Errorf("step = %s, want %s", stored.Step, StepCompleted)
+	}
+	env.assertStock(t, map[string]int32{"book": 8})
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/address"
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	pb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkoutTimeout チェックアウト1件を実行する時間の上限
+const checkoutTimeout = 30 * time.Second
+
+type CheckoutServer struct {
+	pb.UnimplementedCheckoutServiceServer
+	coordinator *Coordinator
+	store       SagaStore
+}
+
+func NewCheckoutServer(coordinator *Coordinator, store SagaStore) *CheckoutServer {
+	return &CheckoutServer{
+		coordinator: coordinator,
+		store:       store,
+	}
+}
+
+// canAccess 呼び出し元が指定ユーザーのチェックアウトを扱えるか判定する
+// 本人、スタッフと管理者のみ
+func canAccess(ctx context.Context, userID string) bool {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return claims.UserID == userID || auth.HasAnyRole(claims.Roles, auth.RoleStaff, auth.RoleAdmin)
+}
+
+func (s *CheckoutServer) Checkout(ctx context.Context, req *pb.CheckoutRequest) (*pb.CheckoutResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if !canAccess(ctx, req.UserId) {
+		return nil, status.Error(codes.PermissionDenied, "cannot check out for another user")
+	}
+	if len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one item is required")
+	}
+	if req.ShippingAddress == nil {
+		return nil, status.Error(codes.InvalidArgument, "shipping_address is required")
+	}
+	// 注文サービスと同じ規則で検証し、不正な住所で注文作成まで進めない
+	shipping := req.ShippingAddress
+	if _, err := address.New(shipping.PostalCode, shipping.Prefecture, shipping.City, shipping.AddressLine1, shipping.AddressLine2, shipping.PhoneNumber); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid shipping_address: %v", err)
+	}
+	if req.PaymentMethod == paymentpb.PaymentMethod_PAYMENT_METHOD_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "payment_method is required")
+	}
+	if req.PaymentToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_token is required")
+	}
+
+	items := make([]SagaItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId == "" || item.Quantity <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "each item needs a product_id and a positive quantity")
+		}
+		items = append(items, SagaItem{ProductID: item.ProductId, Quantity: item.Quantity})
+	}
+
+	saga := &Saga{
+		ID:              uuid.New().String(),
+		UserID:          req.UserId,
+		Items:           items,
+		ShippingAddress: req.ShippingAddress,
+		PaymentMethod:   req.PaymentMethod,
+	}
+
+	// 呼び出し元が切断しても途中で止めず、補償まで実行する
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkoutTimeout)
+	defer cancel()
+
+	if err := s.coordinator.Start(runCtx, saga, req.PaymentToken); err != nil {
+		// 保存済みのステップから再開処理が完了または取り消しまで進める
+		log.Printf("checkout %s stopped at %s: %v", saga.ID, saga.Step, err)
+		return nil, status.Errorf(codes.Internal, "checkout %s could not be finished and will be resumed", saga.ID)
+	}
+
+	return toPBCheckout(saga), nil
+}
+
+func (s *CheckoutServer) GetCheckout(ctx context.Context, req *pb.GetCheckoutRequest) (*pb.CheckoutResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	saga, err := s.store.Get(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "checkout not found")
+	}
+	// 他人のチェックアウトは存在しないものとして扱う
+	if !canAccess(ctx, saga.UserID) {
+		return nil, status.Error(codes.NotFound, "checkout not found")
+	}
+
+	return toPBCheckout(saga), nil
+}
+
+// toPBCheckout サガをレスポンスに変換する
+func toPBCheckout(saga *Saga) *pb.CheckoutResponse {
+	checkoutStatus := pb.CheckoutStatus_CHECKOUT_STATUS_IN_PROGRESS
+	switch saga.Step {
+	case StepCompleted:
+		checkoutStatus = pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED
+	case StepFailed:
+		checkoutStatus = pb.CheckoutStatus_CHECKOUT_STATUS_FAILED
+	}
+
+	return &pb.CheckoutResponse{
+		Id:             saga.ID,
+		UserId:         saga.UserID,
+		Status:         checkoutStatus,
+		OrderId:        saga.OrderID,
+		PaymentId:      saga.PaymentID,
+		TransactionId:  saga.TransactionID,
+		Failure:        saga.Failure,
+		FailureMessage: saga.FailureMessage,
+		CreatedAt:      &commonpb.Timestamp{Seconds: saga.CreatedAt.Unix()},
+		UpdatedAt:      &commonpb.Timestamp{Seconds: saga.UpdatedAt.Unix()},
+	}
+}
@@ -6,9 +6,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	appcheckout "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/checkout"
+	apporder "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/order"
+	apppayment "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/payment"
+	appproduct "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/product"
 	appuser "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/user"
 	"github.com/Riku-KANO/kube-ec/services/gateway/internal/infrastructure/grpc"
 	httpserver "github.com/Riku-KANO/kube-ec/services/gateway/internal/presentation/http"
@@ -19,8 +25,18 @@ import (
 func main() {
 	// Load configuration from environment variables
 	config := grpc.ClientConfig{
-		AuthServiceAddr: getEnv("AUTH_SERVICE_ADDR", "localhost:50052"),
-		UserServiceAddr: getEnv("USER_SERVICE_ADDR", "localhost:50051"),
+		AuthServiceAddr:     getEnv("AUTH_SERVICE_ADDR", "localhost:50052"),
+		UserServiceAddr:     getEnv("USER_SERVICE_ADDR", "localhost:50051"),
+		ProductServiceAddr:  getEnv("PRODUCT_SERVICE_ADDR", "localhost:50053"),
+		OrderServiceAddr:    getEnv("ORDER_SERVICE_ADDR", "localhost:50054"),
+		PaymentServiceAddr:  getEnv("PAYMENT_SERVICE_ADDR", "localhost:50055"),
+		CheckoutServiceAddr: getEnv("CHECKOUT_SERVICE_ADDR", "localhost:50056"),
+	}
+
+	// The email verification policy must match the one of the auth service
+	verificationPolicy, err := auth.ParseEmailVerificationPolicy(os.Getenv("EMAIL_VERIFICATION_POLICY"))
+	if err != nil {
+		log.Fatalf("Invalid EMAIL_VERIFICATION_POLICY: %v", err)
 	}
 
 	// Initialize infrastructure layer (gRPC clients)
@@ -33,16 +49,40 @@ func main() {
 	// Initialize repositories
 	authRepo := grpc.NewAuthRepository(grpcClients.AuthClient)
 	userRepo := grpc.NewUserRepository(grpcClients.UserClient)
+	productRepo := grpc.NewProductRepository(grpcClients.ProductClient)
+	orderRepo := grpc.NewOrderRepository(grpcClients.OrderClient)
+	paymentRepo := grpc.NewPaymentRepository(grpcClients.PaymentClient)
+	checkoutRepo := grpc.NewCheckoutRepository(grpcClients.CheckoutClient)
 
 	// Initialize application services
 	// Use authRepo for authentication operations and userRepo for user management
 	userService := appuser.NewService(authRepo, userRepo)
+	productService := appproduct.NewService(productRepo)
+	orderService := apporder.NewService(orderRepo, verificationPolicy)
+	paymentService := apppayment.NewService(paymentRepo, orderRepo)
+	checkoutService := appcheckout.NewService(checkoutRepo, verificationPolicy)
 
 	// Initialize presentation layer (HTTP handlers)
 	userHandler := handler.NewUserHandler(userService)
+	productHandler := handler.NewProductHandler(productService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	checkoutHandler := handler.NewCheckoutHandler(checkoutService)
+	jwksHandler := handler.NewJWKSHandler(authRepo)
 
 	// Setup router
-	router := httpserver.SetupRouter(userHandler)
+	router := httpserver.SetupRouter(userHandler, productHandler, orderHandler, paymentHandler, checkoutHandler, jwksHandler, userService)
+
+	// Only trust X-Forwarded-For from known proxies, so clients cannot pick the
+	// IP their failed logins are counted under. Gin trusts every proxy by
+	// default, so without TRUSTED_PROXIES the header is ignored altogether.
+	var trustedProxies []string
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Configure CORS
 	router.Use(cors.New(cors.Config{
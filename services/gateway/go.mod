@@ -3,6 +3,7 @@ module github.com/Riku-KANO/kube-ec/services/gateway
 go 1.24.0
 
 require (
+	github.com/Riku-KANO/kube-ec/pkg v0.0.0
 	github.com/Riku-KANO/kube-ec/proto v0.0.0
 	github.com/getkin/kin-openapi v0.132.0
 	github.com/gin-contrib/cors v1.7.2
@@ -26,6 +27,7 @@ require (
 	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/go-playground/validator/v10 v10.20.0 // indirect
 	github.com/goccy/go-json v0.10.2 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
@@ -52,3 +54,5 @@ require (
 )
 
 replace github.com/Riku-KANO/kube-ec/proto => ../../proto
+
+replace github.com/Riku-KANO/kube-ec/pkg => ../../pkg
@@ -0,0 +1,41 @@
+package checkout
+
+import "time"
+
+// CheckoutInput チェックアウトの入力DTO
+// 価格は商品カタログから取得するため数量のみ受け取る
+type CheckoutInput struct {
+	Items           []CheckoutItemInput
+	ShippingAddress AddressInput
+	PaymentMethod   string
+	PaymentToken    string
+}
+
+// CheckoutItemInput チェックアウトする商品の入力DTO
+type CheckoutItemInput struct {
+	ProductID string
+	Quantity  int32
+}
+
+// AddressInput 住所の入力DTO
+type AddressInput struct {
+	PostalCode   string
+	Prefecture   string
+	City         string
+	AddressLine1 string
+	AddressLine2 string
+	PhoneNumber  string
+}
+
+// CheckoutOutput チェックアウト情報の出力DTO
+type CheckoutOutput struct {
+	ID             string
+	Status         string
+	OrderID        string
+	PaymentID      string
+	TransactionID  string
+	Failure        string
+	FailureMessage string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
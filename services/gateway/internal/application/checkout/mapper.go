@@ -0,0 +1,20 @@
+package checkout
+
+import (
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/checkout"
+)
+
+// ToCheckoutOutput converts domain Checkout to CheckoutOutput DTO
+func ToCheckoutOutput(c *checkout.Checkout) CheckoutOutput {
+	return CheckoutOutput{
+		ID:             c.ID(),
+		Status:         c.Status().String(),
+		OrderID:        c.OrderID(),
+		PaymentID:      c.PaymentID(),
+		TransactionID:  c.TransactionID(),
+		Failure:        c.Failure().String(),
+		FailureMessage: c.FailureMessage(),
+		CreatedAt:      c.CreatedAt(),
+		UpdatedAt:      c.UpdatedAt(),
+	}
+}
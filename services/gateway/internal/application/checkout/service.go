@@ -0,0 +1,112 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/checkout"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+)
+
+// maxCheckoutItems 1 回のチェックアウトあたりの商品数の上限
+const maxCheckoutItems = 100
+
+// Service チェックアウトのアプリケーションサービス
+// 注文作成から決済までの手続きはチェックアウトサービスが実行する
+type Service struct {
+	checkoutRepo       checkout.CheckoutRepository
+	verificationPolicy auth.EmailVerificationPolicy
+}
+
+// NewService creates a new checkout application service.
+// verificationPolicy must match the policy of the auth service.
+func NewService(checkoutRepo checkout.CheckoutRepository, verificationPolicy auth.EmailVerificationPolicy) *Service {
+	return &Service{
+		checkoutRepo:       checkoutRepo,
+		verificationPolicy: verificationPolicy,
+	}
+}
+
+// Checkout places and pays for an order for the principal.
+// A failed checkout has been compensated when its error is returned.
+func (s *Service) Checkout(ctx context.Context, principal *user.Principal, input CheckoutInput) (CheckoutOutput, error) {
+	if s.verificationPolicy.RequiredForOrders() && !principal.EmailVerified() {
+		return CheckoutOutput{}, errors.ErrEmailNotVerified
+	}
+
+	if len(input.Items) == 0 || len(input.Items) > maxCheckoutItems || input.PaymentToken == "" {
+		return CheckoutOutput{}, errors.ErrInvalidInput
+	}
+
+	items := make([]checkout.Item, 0, len(input.Items))
+	for _, item := range input.Items {
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return CheckoutOutput{}, errors.ErrInvalidInput
+		}
+		items = append(items, checkout.Item{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	shippingAddress, err := order.NewAddress(
+		input.ShippingAddress.PostalCode,
+		input.ShippingAddress.Prefecture,
+		input.ShippingAddress.City,
+		input.ShippingAddress.AddressLine1,
+		input.ShippingAddress.AddressLine2,
+		input.ShippingAddress.PhoneNumber,
+	)
+	if err != nil {
+		return CheckoutOutput{}, errors.ErrInvalidInput
+	}
+
+	method, err := payment.ParseMethod(input.PaymentMethod)
+	if err != nil {
+		return CheckoutOutput{}, errors.ErrInvalidInput
+	}
+
+	result, err := s.checkoutRepo.Checkout(ctx, principal.UserID(), items, shippingAddress, method, input.PaymentToken)
+	if err != nil {
+		return CheckoutOutput{}, err
+	}
+
+	if result.Status() == checkout.StatusFailed {
+		return CheckoutOutput{}, failureError(result)
+	}
+
+	return ToCheckoutOutput(result), nil
+}
+
+// GetCheckout retrieves a checkout the principal may access: their own, or
+// any checkout for staff and admins. Checkouts of other users are reported as
+// not found so their IDs cannot be probed.
+func (s *Service) GetCheckout(ctx context.Context, principal *user.Principal, checkoutID string) (CheckoutOutput, error) {
+	if checkoutID == "" {
+		return CheckoutOutput{}, errors.ErrInvalidInput
+	}
+
+	found, err := s.checkoutRepo.FindByID(ctx, checkoutID)
+	if err != nil {
+		return CheckoutOutput{}, err
+	}
+
+	if found.UserID() != principal.UserID() && !auth.HasAnyRole(principal.Roles(), auth.RoleStaff, auth.RoleAdmin) {
+		return CheckoutOutput{}, errors.ErrNotFound
+	}
+
+	return ToCheckoutOutput(found), nil
+}
+
+// failureError maps the reason a checkout failed to a domain error
+func failureError(c *checkout.Checkout) error {
+	switch c.Failure() {
+	case checkout.FailurePaymentDeclined:
+		return errors.ErrPaymentDeclined
+	case checkout.FailureProductUnavailable, checkout.FailureOutOfStock:
+		return errors.ErrConflict
+	default:
+		return fmt.Errorf("checkout %s failed: %s", c.ID(), c.FailureMessage())
+	}
+}
@@ -0,0 +1,100 @@
+package order
+
+import "time"
+
+// CreateOrderInput 注文作成の入力DTO
+// 価格は商品カタログから取得するため数量のみ受け取る
+// BillingAddress が nil の場合は配送先に請求する
+type CreateOrderInput struct {
+	Items           []OrderItemInput
+	ShippingAddress AddressInput
+	BillingAddress  *AddressInput
+}
+
+// ChangeAddressInput 注文の住所変更の入力DTO
+// BillingAddress が nil の場合は配送先に請求する
+type ChangeAddressInput struct {
+	ShippingAddress AddressInput
+	BillingAddress  *AddressInput
+}
+
+// OrderItemInput 注文明細の入力DTO
+type OrderItemInput struct {
+	ProductID string
+	Quantity  int32
+}
+
+// AddressInput 住所の入力DTO
+type AddressInput struct {
+	PostalCode   string
+	Prefecture   string
+	City         string
+	AddressLine1 string
+	AddressLine2 string
+	PhoneNumber  string
+}
+
+// ListOrdersInput 注文一覧の入力DTO
+type ListOrdersInput struct {
+	Status   string
+	Page     int32
+	PageSize int32
+}
+
+// OrderOutput 注文情報の出力DTO
+type OrderOutput struct {
+	ID              string
+	UserID          string
+	Items           []OrderItemOutput
+	TotalAmount     MoneyOutput
+	Status          string
+	ShippingAddress AddressOutput
+	BillingAddress  *AddressOutput // 配送先に請求する場合は nil
+	PaymentID       string
+	History         []StatusChangeOutput // 注文詳細でのみ設定される
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// StatusChangeOutput 注文ステータスの変更履歴の出力DTO
+type StatusChangeOutput struct {
+	From      string
+	To        string
+	ActorType string
+	ActorID   string
+	Reason    string
+	ChangedAt time.Time
+}
+
+// OrderItemOutput 注文明細の出力DTO
+type OrderItemOutput struct {
+	ProductID   string
+	ProductName string
+	Quantity    int32
+	UnitPrice   MoneyOutput
+	Subtotal    MoneyOutput
+}
+
+// MoneyOutput 金額の出力DTO
+type MoneyOutput struct {
+	Amount   int64
+	Currency string
+}
+
+// AddressOutput 住所の出力DTO
+type AddressOutput struct {
+	PostalCode   string
+	Prefecture   string
+	City         string
+	AddressLine1 string
+	AddressLine2 string
+	PhoneNumber  string
+}
+
+// OrderListOutput 注文一覧の出力DTO
+type OrderListOutput struct {
+	Orders      []OrderOutput
+	TotalCount  int32
+	TotalPages  int32
+	CurrentPage int32
+}
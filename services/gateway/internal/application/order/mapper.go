@@ -0,0 +1,86 @@
+package order
+
+import (
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// ToOrderOutput converts domain Order to OrderOutput DTO
+func ToOrderOutput(o *order.Order) OrderOutput {
+	output := OrderOutput{
+		ID:              o.ID(),
+		UserID:          o.UserID(),
+		Items:           make([]OrderItemOutput, 0, len(o.Items())),
+		TotalAmount:     toMoneyOutput(o.TotalAmount()),
+		Status:          o.Status().String(),
+		ShippingAddress: toAddressOutput(o.ShippingAddress()),
+		PaymentID:       o.PaymentID(),
+		CreatedAt:       o.CreatedAt(),
+		UpdatedAt:       o.UpdatedAt(),
+	}
+	if !o.BillingAddress().IsZero() {
+		billingAddress := toAddressOutput(o.BillingAddress())
+		output.BillingAddress = &billingAddress
+	}
+
+	for _, item := range o.Items() {
+		output.Items = append(output.Items, OrderItemOutput{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   toMoneyOutput(item.UnitPrice),
+			Subtotal:    toMoneyOutput(item.Subtotal),
+		})
+	}
+
+	return output
+}
+
+// toAddressOutput converts domain Address to AddressOutput DTO
+func toAddressOutput(a order.Address) AddressOutput {
+	return AddressOutput{
+		PostalCode:   a.PostalCode(),
+		Prefecture:   a.Prefecture(),
+		City:         a.City(),
+		AddressLine1: a.AddressLine1(),
+		AddressLine2: a.AddressLine2(),
+		PhoneNumber:  a.PhoneNumber(),
+	}
+}
+
+// ToStatusChangeOutputs converts domain StatusChanges to StatusChangeOutput DTOs
+func ToStatusChangeOutputs(changes []order.StatusChange) []StatusChangeOutput {
+	outputs := make([]StatusChangeOutput, 0, len(changes))
+	for _, change := range changes {
+		outputs = append(outputs, StatusChangeOutput{
+			From:      change.From.String(),
+			To:        change.To.String(),
+			ActorType: change.ActorType,
+			ActorID:   change.ActorID,
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return outputs
+}
+
+// ToOrderListOutput converts a domain Page to OrderListOutput DTO
+func ToOrderListOutput(page *order.Page) OrderListOutput {
+	output := OrderListOutput{
+		Orders:      make([]OrderOutput, 0, len(page.Orders)),
+		TotalCount:  page.TotalCount,
+		TotalPages:  page.TotalPages,
+		CurrentPage: page.CurrentPage,
+	}
+	for _, o := range page.Orders {
+		output.Orders = append(output.Orders, ToOrderOutput(o))
+	}
+	return output
+}
+
+func toMoneyOutput(m product.Money) MoneyOutput {
+	return MoneyOutput{
+		Amount:   m.Amount(),
+		Currency: m.Currency(),
+	}
+}
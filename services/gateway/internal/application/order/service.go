@@ -0,0 +1,211 @@
+package order
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+)
+
+// maxOrderItems 1 注文あたりの明細数の上限
+const maxOrderItems = 100
+
+// Service 注文のアプリケーションサービス
+// 呼び出し元は常に認証済みの Principal
+type Service struct {
+	orderRepo          order.OrderRepository
+	verificationPolicy auth.EmailVerificationPolicy
+}
+
+// NewService creates a new order application service.
+// verificationPolicy must match the policy of the auth service.
+func NewService(
+	orderRepo order.OrderRepository,
+	verificationPolicy auth.EmailVerificationPolicy,
+) *Service {
+	return &Service{
+		orderRepo:          orderRepo,
+		verificationPolicy: verificationPolicy,
+	}
+}
+
+// CreateOrder places an order for the principal at the current catalog prices
+func (s *Service) CreateOrder(ctx context.Context, principal *user.Principal, input CreateOrderInput) (OrderOutput, error) {
+	if s.verificationPolicy.RequiredForOrders() && !principal.EmailVerified() {
+		return OrderOutput{}, errors.ErrEmailNotVerified
+	}
+
+	if len(input.Items) == 0 || len(input.Items) > maxOrderItems {
+		return OrderOutput{}, errors.ErrInvalidInput
+	}
+
+	shippingAddress, billingAddress, err := toAddresses(input.ShippingAddress, input.BillingAddress)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	items, err := toLineItems(input.Items)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	created, err := s.orderRepo.Create(ctx, principal.UserID(), items, shippingAddress, billingAddress)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	return ToOrderOutput(created), nil
+}
+
+// toAddresses validates the shipping address and the optional billing address.
+// A nil billing address becomes the zero address, billing the shipping address.
+func toAddresses(shipping AddressInput, billing *AddressInput) (order.Address, order.Address, error) {
+	shippingAddress, err := toAddress(shipping)
+	if err != nil {
+		return order.Address{}, order.Address{}, err
+	}
+	if billing == nil {
+		return shippingAddress, order.Address{}, nil
+	}
+
+	billingAddress, err := toAddress(*billing)
+	if err != nil {
+		return order.Address{}, order.Address{}, err
+	}
+	return shippingAddress, billingAddress, nil
+}
+
+// toAddress validates an address input
+func toAddress(input AddressInput) (order.Address, error) {
+	address, err := order.NewAddress(
+		input.PostalCode,
+		input.Prefecture,
+		input.City,
+		input.AddressLine1,
+		input.AddressLine2,
+		input.PhoneNumber,
+	)
+	if err != nil {
+		return order.Address{}, errors.ErrInvalidInput
+	}
+	return address, nil
+}
+
+// toLineItems validates the requested products and quantities.
+// Prices are decided by the order service from the product catalog.
+func toLineItems(inputs []OrderItemInput) ([]order.LineItem, error) {
+	items := make([]order.LineItem, 0, len(inputs))
+	for _, input := range inputs {
+		if input.ProductID == "" || input.Quantity <= 0 {
+			return nil, errors.ErrInvalidInput
+		}
+		items = append(items, order.LineItem{ProductID: input.ProductID, Quantity: input.Quantity})
+	}
+	return items, nil
+}
+
+// ListMyOrders returns a page of the principal's orders, newest first
+func (s *Service) ListMyOrders(ctx context.Context, principal *user.Principal, input ListOrdersInput) (OrderListOutput, error) {
+	if input.Page < 0 || input.PageSize < 0 {
+		return OrderListOutput{}, errors.ErrInvalidInput
+	}
+
+	query := order.ListQuery{
+		UserID:   principal.UserID(),
+		Page:     input.Page,
+		PageSize: input.PageSize,
+	}
+	if input.Status != "" {
+		status, err := order.ParseStatus(input.Status)
+		if err != nil {
+			return OrderListOutput{}, errors.ErrInvalidInput
+		}
+		query.Status = status
+	}
+
+	page, err := s.orderRepo.ListByUser(ctx, query)
+	if err != nil {
+		return OrderListOutput{}, err
+	}
+
+	return ToOrderListOutput(page), nil
+}
+
+// GetOrder retrieves an order of the principal with its status history
+func (s *Service) GetOrder(ctx context.Context, principal *user.Principal, orderID string) (OrderOutput, error) {
+	found, err := s.findOrder(ctx, principal, orderID)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	history, err := s.orderRepo.History(ctx, orderID)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	output := ToOrderOutput(found)
+	output.History = ToStatusChangeOutputs(history)
+	return output, nil
+}
+
+// CancelOrder cancels an order of the principal.
+// Customers can only cancel orders that are still pending. Confirmed orders
+// are paid and their stock is committed, so cancelling them needs a refund
+// and a restock and is left to staff.
+func (s *Service) CancelOrder(ctx context.Context, principal *user.Principal, orderID string, reason string) (OrderOutput, error) {
+	found, err := s.findOrder(ctx, principal, orderID)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+	if found.Status() != order.StatusPending && !auth.HasAnyRole(principal.Roles(), auth.RoleStaff, auth.RoleAdmin) {
+		return OrderOutput{}, errors.ErrConflict
+	}
+
+	cancelled, err := s.orderRepo.Cancel(ctx, orderID, reason)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	return ToOrderOutput(cancelled), nil
+}
+
+// ChangeAddress replaces the addresses of an order of the principal that is
+// still pending or confirmed
+func (s *Service) ChangeAddress(ctx context.Context, principal *user.Principal, orderID string, input ChangeAddressInput) (OrderOutput, error) {
+	shippingAddress, billingAddress, err := toAddresses(input.ShippingAddress, input.BillingAddress)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	if _, err := s.findOrder(ctx, principal, orderID); err != nil {
+		return OrderOutput{}, err
+	}
+
+	changed, err := s.orderRepo.ChangeAddress(ctx, orderID, shippingAddress, billingAddress)
+	if err != nil {
+		return OrderOutput{}, err
+	}
+
+	return ToOrderOutput(changed), nil
+}
+
+// findOrder retrieves an order the principal may access: their own, or any
+// order for staff and admins. Orders of other users are reported as not
+// found so their IDs cannot be probed.
+func (s *Service) findOrder(ctx context.Context, principal *user.Principal, orderID string) (*order.Order, error) {
+	if orderID == "" {
+		return nil, errors.ErrInvalidInput
+	}
+
+	found, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if found.UserID() != principal.UserID() && !auth.HasAnyRole(principal.Roles(), auth.RoleStaff, auth.RoleAdmin) {
+		return nil, errors.ErrNotFound
+	}
+	return found, nil
+}
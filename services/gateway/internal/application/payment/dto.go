@@ -0,0 +1,59 @@
+package payment
+
+import "time"
+
+// CreatePaymentInput 決済開始の入力DTO
+// 金額は注文の合計金額を使うため受け取らない
+type CreatePaymentInput struct {
+	OrderID string
+	Method  string
+}
+
+// ProcessPaymentInput 決済処理の入力DTO
+type ProcessPaymentInput struct {
+	PaymentToken string
+}
+
+// RefundPaymentInput 返金の入力DTO
+// Amount が nil の場合は全額返金
+type RefundPaymentInput struct {
+	Amount *int64
+	Reason string
+}
+
+// PaymentOutput 決済情報の出力DTO
+type PaymentOutput struct {
+	ID            string
+	OrderID       string
+	UserID        string
+	Amount        MoneyOutput
+	Status        string
+	Method        string
+	TransactionID string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// MoneyOutput 金額の出力DTO
+type MoneyOutput struct {
+	Amount   int64
+	Currency string
+}
+
+// PaymentStatusOutput 決済ステータスの出力DTO
+type PaymentStatusOutput struct {
+	Status        string
+	TransactionID string
+}
+
+// ChargeOutput 決済処理結果の出力DTO
+type ChargeOutput struct {
+	TransactionID string
+	Message       string
+}
+
+// RefundOutput 返金結果の出力DTO
+type RefundOutput struct {
+	RefundID string
+	Message  string
+}
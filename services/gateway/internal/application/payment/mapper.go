@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+)
+
+// ToPaymentOutput converts domain Payment to PaymentOutput DTO
+func ToPaymentOutput(p *payment.Payment) PaymentOutput {
+	return PaymentOutput{
+		ID:      p.ID(),
+		OrderID: p.OrderID(),
+		UserID:  p.UserID(),
+		Amount: MoneyOutput{
+			Amount:   p.Amount().Amount(),
+			Currency: p.Amount().Currency(),
+		},
+		Status:        p.Status().String(),
+		Method:        p.Method().String(),
+		TransactionID: p.TransactionID(),
+		CreatedAt:     p.CreatedAt(),
+		UpdatedAt:     p.UpdatedAt(),
+	}
+}
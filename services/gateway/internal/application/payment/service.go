@@ -0,0 +1,165 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+)
+
+// Service 決済のアプリケーションサービス
+type Service struct {
+	paymentRepo payment.PaymentRepository
+	orderRepo   order.OrderRepository
+}
+
+// NewService creates a new payment application service
+func NewService(paymentRepo payment.PaymentRepository, orderRepo order.OrderRepository) *Service {
+	return &Service{
+		paymentRepo: paymentRepo,
+		orderRepo:   orderRepo,
+	}
+}
+
+// CreatePayment starts paying for a pending order of the principal.
+// The payment is for the order total, whatever the client believes it to be.
+func (s *Service) CreatePayment(ctx context.Context, principal *user.Principal, input CreatePaymentInput) (PaymentOutput, error) {
+	if input.OrderID == "" {
+		return PaymentOutput{}, errors.ErrInvalidInput
+	}
+	method, err := payment.ParseMethod(input.Method)
+	if err != nil {
+		return PaymentOutput{}, errors.ErrInvalidInput
+	}
+
+	found, err := s.orderRepo.FindByID(ctx, input.OrderID)
+	if err != nil {
+		return PaymentOutput{}, err
+	}
+	if !canAccess(principal, found.UserID()) {
+		return PaymentOutput{}, errors.ErrNotFound
+	}
+	if found.Status() != order.StatusPending {
+		return PaymentOutput{}, errors.ErrConflict
+	}
+
+	created, err := s.paymentRepo.Create(ctx, found.ID(), found.UserID(), found.TotalAmount(), method)
+	if err != nil {
+		return PaymentOutput{}, err
+	}
+
+	return ToPaymentOutput(created), nil
+}
+
+// GetPayment retrieves a payment of the principal
+func (s *Service) GetPayment(ctx context.Context, principal *user.Principal, paymentID string) (PaymentOutput, error) {
+	found, err := s.findPayment(ctx, principal, paymentID)
+	if err != nil {
+		return PaymentOutput{}, err
+	}
+
+	return ToPaymentOutput(found), nil
+}
+
+// GetPaymentStatus returns the status of a payment of the principal
+func (s *Service) GetPaymentStatus(ctx context.Context, principal *user.Principal, paymentID string) (PaymentStatusOutput, error) {
+	found, err := s.findPayment(ctx, principal, paymentID)
+	if err != nil {
+		return PaymentStatusOutput{}, err
+	}
+
+	return PaymentStatusOutput{
+		Status:        found.Status().String(),
+		TransactionID: found.TransactionID(),
+	}, nil
+}
+
+// ProcessPayment charges a pending payment of the principal
+func (s *Service) ProcessPayment(ctx context.Context, principal *user.Principal, paymentID string, input ProcessPaymentInput) (ChargeOutput, error) {
+	if input.PaymentToken == "" {
+		return ChargeOutput{}, errors.ErrInvalidInput
+	}
+
+	found, err := s.findPayment(ctx, principal, paymentID)
+	if err != nil {
+		return ChargeOutput{}, err
+	}
+	if found.Status() != payment.StatusPending {
+		return ChargeOutput{}, errors.ErrConflict
+	}
+
+	charge, err := s.paymentRepo.Process(ctx, found.ID(), input.PaymentToken)
+	if err != nil {
+		return ChargeOutput{}, err
+	}
+
+	return ChargeOutput{
+		TransactionID: charge.TransactionID,
+		Message:       charge.Message,
+	}, nil
+}
+
+// RefundPayment refunds a completed payment, in full unless an amount is given.
+// Only admins may refund, which the router enforces.
+func (s *Service) RefundPayment(ctx context.Context, paymentID string, input RefundPaymentInput) (RefundOutput, error) {
+	if paymentID == "" {
+		return RefundOutput{}, errors.ErrInvalidInput
+	}
+
+	found, err := s.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return RefundOutput{}, err
+	}
+	if found.Status() != payment.StatusCompleted {
+		return RefundOutput{}, errors.ErrConflict
+	}
+
+	amount := found.Amount()
+	if input.Amount != nil {
+		if *input.Amount <= 0 || *input.Amount > amount.Amount() {
+			return RefundOutput{}, errors.ErrInvalidInput
+		}
+		amount, err = product.NewMoney(*input.Amount, amount.Currency())
+		if err != nil {
+			return RefundOutput{}, errors.ErrInvalidInput
+		}
+	}
+
+	refund, err := s.paymentRepo.Refund(ctx, found.ID(), amount, input.Reason)
+	if err != nil {
+		return RefundOutput{}, err
+	}
+
+	return RefundOutput{
+		RefundID: refund.RefundID,
+		Message:  refund.Message,
+	}, nil
+}
+
+// findPayment retrieves a payment the principal may access. Payments of
+// other users are reported as not found, as orders are.
+func (s *Service) findPayment(ctx context.Context, principal *user.Principal, paymentID string) (*payment.Payment, error) {
+	if paymentID == "" {
+		return nil, errors.ErrInvalidInput
+	}
+
+	found, err := s.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canAccess(principal, found.UserID()) {
+		return nil, errors.ErrNotFound
+	}
+	return found, nil
+}
+
+// canAccess reports whether the principal may act on a resource of a user:
+// their own, or any for staff and admins
+func canAccess(principal *user.Principal, userID string) bool {
+	return userID == principal.UserID() || auth.HasAnyRole(principal.Roles(), auth.RoleStaff, auth.RoleAdmin)
+}
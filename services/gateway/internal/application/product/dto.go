@@ -0,0 +1,69 @@
+package product
+
+import "time"
+
+// CreateProductInput 商品登録の入力DTO
+type CreateProductInput struct {
+	Name          string
+	Description   string
+	Price         MoneyInput
+	StockQuantity int32
+	Category      string
+	ImageURLs     []string
+	SKU           string
+}
+
+// UpdateProductInput 商品更新の入力DTO
+// nil のフィールドは変更しない
+type UpdateProductInput struct {
+	Name        *string
+	Description *string
+	Price       *MoneyInput
+	Category    *string
+	ImageURLs   *[]string
+	SKU         *string
+	IsActive    *bool
+}
+
+// MoneyInput 金額の入力DTO
+type MoneyInput struct {
+	Amount   int64
+	Currency string
+}
+
+// ListProductsInput 商品一覧の入力DTO
+type ListProductsInput struct {
+	Page     int32
+	PageSize int32
+	Category string
+	Search   string
+}
+
+// ProductOutput 商品情報の出力DTO
+type ProductOutput struct {
+	ID            string
+	Name          string
+	Description   string
+	Price         MoneyOutput
+	StockQuantity int32
+	Category      string
+	ImageURLs     []string
+	SKU           string
+	IsActive      bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// MoneyOutput 金額の出力DTO
+type MoneyOutput struct {
+	Amount   int64
+	Currency string
+}
+
+// ProductListOutput 商品一覧の出力DTO
+type ProductListOutput struct {
+	Products    []ProductOutput
+	TotalCount  int32
+	TotalPages  int32
+	CurrentPage int32
+}
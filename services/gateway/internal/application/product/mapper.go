@@ -0,0 +1,44 @@
+package product
+
+import (
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// ToProductOutput converts domain Product to ProductOutput DTO
+func ToProductOutput(p *product.Product) ProductOutput {
+	imageURLs := p.ImageURLs()
+	if imageURLs == nil {
+		imageURLs = []string{}
+	}
+
+	return ProductOutput{
+		ID:          p.ID(),
+		Name:        p.Name(),
+		Description: p.Description(),
+		Price: MoneyOutput{
+			Amount:   p.Price().Amount(),
+			Currency: p.Price().Currency(),
+		},
+		StockQuantity: p.StockQuantity(),
+		Category:      p.Category(),
+		ImageURLs:     imageURLs,
+		SKU:           p.SKU(),
+		IsActive:      p.IsActive(),
+		CreatedAt:     p.CreatedAt(),
+		UpdatedAt:     p.UpdatedAt(),
+	}
+}
+
+// ToProductListOutput converts a domain Page to ProductListOutput DTO
+func ToProductListOutput(page *product.Page) ProductListOutput {
+	output := ProductListOutput{
+		Products:    make([]ProductOutput, 0, len(page.Products)),
+		TotalCount:  page.TotalCount,
+		TotalPages:  page.TotalPages,
+		CurrentPage: page.CurrentPage,
+	}
+	for _, p := range page.Products {
+		output.Products = append(output.Products, ToProductOutput(p))
+	}
+	return output
+}
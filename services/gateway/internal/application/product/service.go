@@ -0,0 +1,169 @@
+package product
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// Service 商品カタログのアプリケーションサービス
+type Service struct {
+	productRepo product.ProductRepository
+}
+
+// NewService creates a new product application service
+func NewService(productRepo product.ProductRepository) *Service {
+	return &Service{
+		productRepo: productRepo,
+	}
+}
+
+// ListProducts returns a page of products
+func (s *Service) ListProducts(ctx context.Context, input ListProductsInput) (ProductListOutput, error) {
+	if input.Page < 0 || input.PageSize < 0 {
+		return ProductListOutput{}, errors.ErrInvalidInput
+	}
+
+	page, err := s.productRepo.List(ctx, product.ListQuery{
+		Page:     input.Page,
+		PageSize: input.PageSize,
+		Category: input.Category,
+		Search:   strings.TrimSpace(input.Search),
+	})
+	if err != nil {
+		return ProductListOutput{}, err
+	}
+
+	return ToProductListOutput(page), nil
+}
+
+// GetProduct retrieves a product by ID
+func (s *Service) GetProduct(ctx context.Context, productID string) (ProductOutput, error) {
+	if productID == "" {
+		return ProductOutput{}, errors.ErrInvalidInput
+	}
+
+	found, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	return ToProductOutput(found), nil
+}
+
+// CreateProduct registers a new product
+func (s *Service) CreateProduct(ctx context.Context, input CreateProductInput) (ProductOutput, error) {
+	price, err := newPrice(input.Price)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	if input.Name == "" || input.StockQuantity < 0 {
+		return ProductOutput{}, errors.ErrInvalidInput
+	}
+
+	created, err := s.productRepo.Create(ctx, product.Details{
+		Name:        input.Name,
+		Description: input.Description,
+		Price:       price,
+		Category:    input.Category,
+		ImageURLs:   input.ImageURLs,
+		SKU:         input.SKU,
+	}, input.StockQuantity)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	return ToProductOutput(created), nil
+}
+
+// UpdateProduct updates the given attributes of a product
+func (s *Service) UpdateProduct(ctx context.Context, productID string, input UpdateProductInput) (ProductOutput, error) {
+	if productID == "" {
+		return ProductOutput{}, errors.ErrInvalidInput
+	}
+
+	existing, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	details := existing.Details()
+	if input.Name != nil {
+		if *input.Name == "" {
+			return ProductOutput{}, errors.ErrInvalidInput
+		}
+		details.Name = *input.Name
+	}
+	if input.Description != nil {
+		details.Description = *input.Description
+	}
+	if input.Price != nil {
+		price, err := newPrice(*input.Price)
+		if err != nil {
+			return ProductOutput{}, err
+		}
+		details.Price = price
+	}
+	if input.Category != nil {
+		details.Category = *input.Category
+	}
+	if input.ImageURLs != nil {
+		details.ImageURLs = *input.ImageURLs
+	}
+	if input.SKU != nil {
+		details.SKU = *input.SKU
+	}
+
+	isActive := existing.IsActive()
+	if input.IsActive != nil {
+		isActive = *input.IsActive
+	}
+
+	existing.UpdateDetails(details, isActive)
+
+	updated, err := s.productRepo.Update(ctx, existing)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	return ToProductOutput(updated), nil
+}
+
+// DeleteProduct deletes a product
+func (s *Service) DeleteProduct(ctx context.Context, productID string) error {
+	if productID == "" {
+		return errors.ErrInvalidInput
+	}
+
+	return s.productRepo.Delete(ctx, productID)
+}
+
+// UpdateStock adds quantityChange, which may be negative, to the stock of a product
+func (s *Service) UpdateStock(ctx context.Context, productID string, quantityChange int32) (ProductOutput, error) {
+	if productID == "" || quantityChange == 0 {
+		return ProductOutput{}, errors.ErrInvalidInput
+	}
+
+	updated, err := s.productRepo.UpdateStock(ctx, productID, quantityChange)
+	if err != nil {
+		return ProductOutput{}, err
+	}
+
+	return ToProductOutput(updated), nil
+}
+
+// newPrice validates a product price, which must be positive
+func newPrice(input MoneyInput) (product.Money, error) {
+	if input.Amount <= 0 {
+		return product.Money{}, errors.ErrInvalidInput
+	}
+
+	price, err := product.NewMoney(input.Amount, input.Currency)
+	if err != nil {
+		return product.Money{}, errors.ErrInvalidInput
+	}
+	return price, nil
+}
@@ -16,6 +16,12 @@ type LoginInput struct {
 	Password string
 }
 
+// ChangePasswordInput パスワード変更の入力DTO
+type ChangePasswordInput struct {
+	OldPassword string
+	NewPassword string
+}
+
 // UpdateUserInput ユーザー更新の入力DTO
 type UpdateUserInput struct {
 	Name        *string
@@ -32,9 +38,36 @@ type UserOutput struct {
 	UpdatedAt   time.Time
 }
 
+// VerifyMFAInput MFA によるログイン完了の入力DTO
+type VerifyMFAInput struct {
+	MFAToken string
+	Code     string
+}
+
 // AuthOutput 認証結果の出力DTO
+// MFARequired の場合はトークンの代わりに MFAToken を返す
 type AuthOutput struct {
-	User         UserOutput
+	User          UserOutput
+	AccessToken   string
+	RefreshToken  string
+	EmailVerified bool
+	MFARequired   bool
+	MFAToken      string
+}
+
+// TokensOutput トークン更新の出力DTO
+type TokensOutput struct {
 	AccessToken  string
 	RefreshToken string
 }
+
+// MFAEnrollmentOutput TOTP 登録の出力DTO
+type MFAEnrollmentOutput struct {
+	Secret     string
+	OTPAuthURI string
+}
+
+// RecoveryCodesOutput リカバリーコードの出力DTO
+type RecoveryCodesOutput struct {
+	RecoveryCodes []string
+}
@@ -25,8 +25,11 @@ func ToUserOutput(u *user.User) UserOutput {
 // ToAuthOutput converts domain User and AuthTokens to AuthOutput DTO
 func ToAuthOutput(u *user.User, tokens user.AuthTokens) AuthOutput {
 	return AuthOutput{
-		User:         ToUserOutput(u),
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
+		User:          ToUserOutput(u),
+		AccessToken:   tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		EmailVerified: tokens.EmailVerified,
+		MFARequired:   tokens.MFARequired(),
+		MFAToken:      tokens.MFAToken,
 	}
 }
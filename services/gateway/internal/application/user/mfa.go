@@ -0,0 +1,75 @@
+package user
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+)
+
+// VerifyMFA completes a login that returned an MFA challenge
+func (s *Service) VerifyMFA(ctx context.Context, input VerifyMFAInput) (AuthOutput, error) {
+	if input.MFAToken == "" || input.Code == "" {
+		return AuthOutput{}, errors.ErrInvalidInput
+	}
+
+	authenticatedUser, tokens, err := s.authRepo.VerifyMFA(ctx, input.MFAToken, input.Code)
+	if err != nil {
+		return AuthOutput{}, err
+	}
+
+	return ToAuthOutput(authenticatedUser, tokens), nil
+}
+
+// EnrollMFA starts a TOTP enrollment
+func (s *Service) EnrollMFA(ctx context.Context, userID string) (MFAEnrollmentOutput, error) {
+	if userID == "" {
+		return MFAEnrollmentOutput{}, errors.ErrInvalidInput
+	}
+
+	enrollment, err := s.authRepo.EnrollMFA(ctx, userID)
+	if err != nil {
+		return MFAEnrollmentOutput{}, err
+	}
+
+	return MFAEnrollmentOutput{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.OTPAuthURI,
+	}, nil
+}
+
+// ConfirmMFA enables MFA with a code from the authenticator app
+func (s *Service) ConfirmMFA(ctx context.Context, userID string, code string) (RecoveryCodesOutput, error) {
+	if userID == "" || code == "" {
+		return RecoveryCodesOutput{}, errors.ErrInvalidInput
+	}
+
+	codes, err := s.authRepo.ConfirmMFA(ctx, userID, code)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+
+	return RecoveryCodesOutput{RecoveryCodes: codes}, nil
+}
+
+// GenerateRecoveryCodes replaces the one-time recovery codes
+func (s *Service) GenerateRecoveryCodes(ctx context.Context, userID string, code string) (RecoveryCodesOutput, error) {
+	if userID == "" || code == "" {
+		return RecoveryCodesOutput{}, errors.ErrInvalidInput
+	}
+
+	codes, err := s.authRepo.GenerateRecoveryCodes(ctx, userID, code)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+
+	return RecoveryCodesOutput{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns MFA off
+func (s *Service) DisableMFA(ctx context.Context, userID string, code string) error {
+	if userID == "" || code == "" {
+		return errors.ErrInvalidInput
+	}
+
+	return s.authRepo.DisableMFA(ctx, userID, code)
+}
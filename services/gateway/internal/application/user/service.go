@@ -126,3 +126,12 @@ func (s *Service) DeleteUser(ctx context.Context, userID string) error {
 
 	return s.userRepo.Delete(ctx, userID)
 }
+
+// Authenticate validates an access token and returns its principal
+func (s *Service) Authenticate(ctx context.Context, accessToken string) (*user.Principal, error) {
+	if accessToken == "" {
+		return nil, errors.ErrUnauthorized
+	}
+
+	return s.authRepo.VerifyToken(ctx, accessToken)
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+)
+
+// RefreshToken exchanges a refresh token for new tokens
+func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (TokensOutput, error) {
+	if refreshToken == "" {
+		return TokensOutput{}, errors.ErrInvalidInput
+	}
+
+	tokens, err := s.authRepo.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return TokensOutput{}, err
+	}
+
+	return TokensOutput{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// Logout revokes the access token of a user and the refresh tokens of its session
+func (s *Service) Logout(ctx context.Context, userID string, accessToken string) error {
+	if userID == "" || accessToken == "" {
+		return errors.ErrInvalidInput
+	}
+
+	return s.authRepo.Logout(ctx, userID, accessToken)
+}
+
+// ChangePassword changes the password of a user and logs out every session
+func (s *Service) ChangePassword(ctx context.Context, userID string, input ChangePasswordInput) error {
+	if userID == "" || input.OldPassword == "" {
+		return errors.ErrInvalidInput
+	}
+
+	// Validate the new password (auth service will handle hashing)
+	if _, err := user.NewPassword(input.NewPassword); err != nil {
+		return errors.ErrInvalidInput
+	}
+
+	return s.authRepo.ChangePassword(ctx, userID, input.OldPassword, input.NewPassword)
+}
@@ -0,0 +1,57 @@
+package checkout
+
+import "time"
+
+// Checkout チェックアウトドメインエンティティ
+// 注文作成、在庫確保、決済、注文確定を1つの手続きとして扱う
+type Checkout struct {
+	id             string
+	userID         string
+	status         Status
+	orderID        string
+	paymentID      string
+	transactionID  string
+	failure        Failure
+	failureMessage string
+	createdAt      time.Time
+	updatedAt      time.Time
+}
+
+// NewCheckout creates a new Checkout entity
+func NewCheckout(
+	id string,
+	userID string,
+	status Status,
+	orderID string,
+	paymentID string,
+	transactionID string,
+	failure Failure,
+	failureMessage string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *Checkout {
+	return &Checkout{
+		id:             id,
+		userID:         userID,
+		status:         status,
+		orderID:        orderID,
+		paymentID:      paymentID,
+		transactionID:  transactionID,
+		failure:        failure,
+		failureMessage: failureMessage,
+		createdAt:      createdAt,
+		updatedAt:      updatedAt,
+	}
+}
+
+// Getters
+func (c *Checkout) ID() string             { return c.id }
+func (c *Checkout) UserID() string         { return c.userID }
+func (c *Checkout) Status() Status         { return c.status }
+func (c *Checkout) OrderID() string        { return c.orderID }
+func (c *Checkout) PaymentID() string      { return c.paymentID }
+func (c *Checkout) TransactionID() string  { return c.transactionID }
+func (c *Checkout) Failure() Failure       { return c.failure }
+func (c *Checkout) FailureMessage() string { return c.failureMessage }
+func (c *Checkout) CreatedAt() time.Time   { return c.createdAt }
+func (c *Checkout) UpdatedAt() time.Time   { return c.updatedAt }
@@ -0,0 +1,25 @@
+package checkout
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+)
+
+// CheckoutRepository defines the interface for checkout operations
+type CheckoutRepository interface {
+	// Checkout places and pays for an order for a user. It returns once the
+	// checkout completed or failed and was compensated.
+	Checkout(
+		ctx context.Context,
+		userID string,
+		items []Item,
+		shippingAddress order.Address,
+		method payment.Method,
+		paymentToken string,
+	) (*Checkout, error)
+
+	// FindByID retrieves a checkout by ID
+	FindByID(ctx context.Context, id string) (*Checkout, error)
+}
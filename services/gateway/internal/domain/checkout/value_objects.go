@@ -0,0 +1,36 @@
+package checkout
+
+// Status チェックアウトステータスの値オブジェクト
+type Status string
+
+const (
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+	StatusFailed     Status = "failed" // 失敗し、完了済みのステップは取り消された
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Failure チェックアウトが失敗した理由の値オブジェクト
+type Failure string
+
+const (
+	FailureNone               Failure = ""
+	FailureProductUnavailable Failure = "product_unavailable"
+	FailureOutOfStock         Failure = "out_of_stock"
+	FailurePaymentDeclined    Failure = "payment_declined"
+	FailureInternal           Failure = "internal"
+)
+
+func (f Failure) String() string {
+	return string(f)
+}
+
+// Item チェックアウトする商品と数量
+// 価格はチェックアウトサービスが商品カタログから取得する
+type Item struct {
+	ProductID string
+	Quantity  int32
+}
@@ -4,10 +4,15 @@ import "errors"
 
 var (
 	// Domain errors
-	ErrInvalidInput  = errors.New("invalid input")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrNotFound      = errors.New("not found")
-	ErrUnauthorized  = errors.New("unauthorized")
-	ErrEmailExists   = errors.New("email already exists")
-	ErrInternalError = errors.New("internal server error")
+	ErrInvalidInput     = errors.New("invalid input")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrNotFound         = errors.New("not found")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrForbidden        = errors.New("forbidden")
+	ErrEmailExists      = errors.New("email already exists")
+	ErrConflict         = errors.New("request conflicts with current state")
+	ErrEmailNotVerified = errors.New("email not verified")
+	ErrTooManyRequests  = errors.New("too many attempts, try again later")
+	ErrPaymentDeclined  = errors.New("payment declined")
+	ErrInternalError    = errors.New("internal server error")
 )
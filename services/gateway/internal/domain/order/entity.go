@@ -0,0 +1,71 @@
+package order
+
+import (
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// Order 注文ドメインエンティティ
+// billingAddress がゼロ値の場合は配送先に請求する
+type Order struct {
+	id              string
+	userID          string
+	items           []Item
+	totalAmount     product.Money
+	status          Status
+	shippingAddress Address
+	billingAddress  Address
+	paymentID       string
+	createdAt       time.Time
+	updatedAt       time.Time
+}
+
+// NewOrder creates a new Order entity
+func NewOrder(
+	id string,
+	userID string,
+	items []Item,
+	totalAmount product.Money,
+	status Status,
+	shippingAddress Address,
+	billingAddress Address,
+	paymentID string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *Order {
+	return &Order{
+		id:              id,
+		userID:          userID,
+		items:           items,
+		totalAmount:     totalAmount,
+		status:          status,
+		shippingAddress: shippingAddress,
+		billingAddress:  billingAddress,
+		paymentID:       paymentID,
+		createdAt:       createdAt,
+		updatedAt:       updatedAt,
+	}
+}
+
+// Getters
+func (o *Order) ID() string                 { return o.id }
+func (o *Order) UserID() string             { return o.userID }
+func (o *Order) Items() []Item              { return o.items }
+func (o *Order) TotalAmount() product.Money { return o.totalAmount }
+func (o *Order) Status() Status             { return o.status }
+func (o *Order) ShippingAddress() Address   { return o.shippingAddress }
+func (o *Order) BillingAddress() Address    { return o.billingAddress }
+func (o *Order) PaymentID() string          { return o.paymentID }
+func (o *Order) CreatedAt() time.Time       { return o.createdAt }
+func (o *Order) UpdatedAt() time.Time       { return o.updatedAt }
+
+// Item 注文明細
+// UnitPrice と ProductName は注文時点の商品情報
+type Item struct {
+	ProductID   string
+	ProductName string
+	Quantity    int32
+	UnitPrice   product.Money
+	Subtotal    product.Money
+}
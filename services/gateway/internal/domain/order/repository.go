@@ -0,0 +1,29 @@
+package order
+
+import "context"
+
+// OrderRepository defines the interface for order operations
+type OrderRepository interface {
+	// Create places an order for a user at the current catalog prices.
+	// A zero billing address bills the order to the shipping address.
+	// It returns ErrInvalidInput for unknown products and ErrConflict for
+	// products that are not for sale or priced in different currencies.
+	Create(ctx context.Context, userID string, items []LineItem, shippingAddress, billingAddress Address) (*Order, error)
+
+	// FindByID retrieves an order by ID
+	FindByID(ctx context.Context, id string) (*Order, error)
+
+	// ListByUser returns a page of the orders of a user, newest first
+	ListByUser(ctx context.Context, query ListQuery) (*Page, error)
+
+	// Cancel cancels an order. It returns ErrConflict once the order has shipped.
+	Cancel(ctx context.Context, id string, reason string) (*Order, error)
+
+	// ChangeAddress replaces the addresses of an order. A zero billing address
+	// bills the order to the shipping address. It returns ErrConflict once the
+	// order is being prepared for shipping.
+	ChangeAddress(ctx context.Context, id string, shippingAddress, billingAddress Address) (*Order, error)
+
+	// History returns the status changes of an order, oldest first
+	History(ctx context.Context, id string) ([]StatusChange, error)
+}
@@ -0,0 +1,79 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/address"
+)
+
+// Status 注文ステータスの値オブジェクト
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusConfirmed  Status = "confirmed"
+	StatusProcessing Status = "processing"
+	StatusShipped    Status = "shipped"
+	StatusDelivered  Status = "delivered"
+	StatusCancelled  Status = "cancelled"
+)
+
+// ParseStatus parses a status name
+func ParseStatus(name string) (Status, error) {
+	switch status := Status(name); status {
+	case StatusPending, StatusConfirmed, StatusProcessing, StatusShipped, StatusDelivered, StatusCancelled:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown order status %q", name)
+	}
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Address 配送先・請求先の値オブジェクト
+// 郵便番号と都道府県の検証は各サービスと共通
+type Address = address.Address
+
+// NewAddress creates a new Address value object.
+// AddressLine2 and PhoneNumber are optional.
+func NewAddress(postalCode, prefecture, city, addressLine1, addressLine2, phoneNumber string) (Address, error) {
+	return address.New(postalCode, prefecture, city, addressLine1, addressLine2, phoneNumber)
+}
+
+// LineItem 注文する商品と数量
+// 名前と価格は注文サービスが商品カタログから決める
+type LineItem struct {
+	ProductID string
+	Quantity  int32
+}
+
+// ListQuery 注文一覧の検索条件
+// Status が空の場合はすべてのステータスを返す
+type ListQuery struct {
+	UserID   string
+	Status   Status
+	Page     int32
+	PageSize int32
+}
+
+// Page 注文一覧の 1 ページ分
+type Page struct {
+	Orders      []*Order
+	TotalCount  int32
+	TotalPages  int32
+	CurrentPage int32
+}
+
+// StatusChange 注文ステータスの変更履歴の1件
+// 注文の作成では From が空になる
+type StatusChange struct {
+	From      Status
+	To        Status
+	ActorType string // user または service
+	ActorID   string
+	Reason    string
+	ChangedAt time.Time
+}
@@ -0,0 +1,56 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// Payment 決済ドメインエンティティ
+type Payment struct {
+	id            string
+	orderID       string
+	userID        string
+	amount        product.Money
+	status        Status
+	method        Method
+	transactionID string
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+// NewPayment creates a new Payment entity
+func NewPayment(
+	id string,
+	orderID string,
+	userID string,
+	amount product.Money,
+	status Status,
+	method Method,
+	transactionID string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *Payment {
+	return &Payment{
+		id:            id,
+		orderID:       orderID,
+		userID:        userID,
+		amount:        amount,
+		status:        status,
+		method:        method,
+		transactionID: transactionID,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+	}
+}
+
+// Getters
+func (p *Payment) ID() string            { return p.id }
+func (p *Payment) OrderID() string       { return p.orderID }
+func (p *Payment) UserID() string        { return p.userID }
+func (p *Payment) Amount() product.Money { return p.amount }
+func (p *Payment) Status() Status        { return p.status }
+func (p *Payment) Method() Method        { return p.method }
+func (p *Payment) TransactionID() string { return p.transactionID }
+func (p *Payment) CreatedAt() time.Time  { return p.createdAt }
+func (p *Payment) UpdatedAt() time.Time  { return p.updatedAt }
@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// PaymentRepository defines the interface for payment operations
+type PaymentRepository interface {
+	// Create starts a pending payment for an order
+	Create(ctx context.Context, orderID string, userID string, amount product.Money, method Method) (*Payment, error)
+
+	// FindByID retrieves a payment by ID
+	FindByID(ctx context.Context, id string) (*Payment, error)
+
+	// Process charges a pending payment with a token from the payment provider.
+	// It returns ErrPaymentDeclined when the charge does not go through.
+	Process(ctx context.Context, id string, paymentToken string) (Charge, error)
+
+	// Refund refunds the given amount of a completed payment.
+	// It returns ErrConflict when the payment cannot be refunded.
+	Refund(ctx context.Context, id string, amount product.Money, reason string) (Refund, error)
+}
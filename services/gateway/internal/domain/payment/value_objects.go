@@ -0,0 +1,54 @@
+package payment
+
+import "fmt"
+
+// Status 決済ステータスの値オブジェクト
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusProcessing Status = "processing"
+	StatusCompleted  Status = "completed"
+	StatusFailed     Status = "failed"
+	StatusRefunded   Status = "refunded"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Method 決済方法の値オブジェクト
+type Method string
+
+const (
+	MethodCreditCard       Method = "credit_card"
+	MethodBankTransfer     Method = "bank_transfer"
+	MethodConvenienceStore Method = "convenience_store"
+	MethodElectronicMoney  Method = "electronic_money"
+)
+
+// ParseMethod parses a payment method name
+func ParseMethod(name string) (Method, error) {
+	switch method := Method(name); method {
+	case MethodCreditCard, MethodBankTransfer, MethodConvenienceStore, MethodElectronicMoney:
+		return method, nil
+	default:
+		return "", fmt.Errorf("unknown payment method %q", name)
+	}
+}
+
+func (m Method) String() string {
+	return string(m)
+}
+
+// Charge 決済処理の結果
+type Charge struct {
+	TransactionID string
+	Message       string
+}
+
+// Refund 返金処理の結果
+type Refund struct {
+	RefundID string
+	Message  string
+}
@@ -0,0 +1,79 @@
+package product
+
+import "time"
+
+// Product 商品ドメインエンティティ
+type Product struct {
+	id            string
+	name          string
+	description   string
+	price         Money
+	stockQuantity int32
+	category      string
+	imageURLs     []string
+	sku           string
+	isActive      bool
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+// NewProduct creates a new Product entity
+func NewProduct(
+	id string,
+	details Details,
+	stockQuantity int32,
+	isActive bool,
+	createdAt time.Time,
+	updatedAt time.Time,
+) *Product {
+	return &Product{
+		id:            id,
+		name:          details.Name,
+		description:   details.Description,
+		price:         details.Price,
+		stockQuantity: stockQuantity,
+		category:      details.Category,
+		imageURLs:     details.ImageURLs,
+		sku:           details.SKU,
+		isActive:      isActive,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
+	}
+}
+
+// Getters
+func (p *Product) ID() string           { return p.id }
+func (p *Product) Name() string         { return p.name }
+func (p *Product) Description() string  { return p.description }
+func (p *Product) Price() Money         { return p.price }
+func (p *Product) StockQuantity() int32 { return p.stockQuantity }
+func (p *Product) Category() string     { return p.category }
+func (p *Product) ImageURLs() []string  { return p.imageURLs }
+func (p *Product) SKU() string          { return p.sku }
+func (p *Product) IsActive() bool       { return p.isActive }
+func (p *Product) CreatedAt() time.Time { return p.createdAt }
+func (p *Product) UpdatedAt() time.Time { return p.updatedAt }
+
+// Details returns the attributes set when the product is created or updated
+func (p *Product) Details() Details {
+	return Details{
+		Name:        p.name,
+		Description: p.description,
+		Price:       p.price,
+		Category:    p.category,
+		ImageURLs:   p.imageURLs,
+		SKU:         p.sku,
+	}
+}
+
+// UpdateDetails replaces the product attributes and its availability
+func (p *Product) UpdateDetails(details Details, isActive bool) {
+	p.name = details.Name
+	p.description = details.Description
+	p.price = details.Price
+	p.category = details.Category
+	p.imageURLs = details.ImageURLs
+	p.sku = details.SKU
+	p.isActive = isActive
+	p.updatedAt = time.Now()
+}
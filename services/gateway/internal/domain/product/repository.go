@@ -0,0 +1,24 @@
+package product
+
+import "context"
+
+// ProductRepository defines the interface for product catalog operations
+type ProductRepository interface {
+	// Create registers a new product
+	Create(ctx context.Context, details Details, stockQuantity int32) (*Product, error)
+
+	// FindByID retrieves a product by ID
+	FindByID(ctx context.Context, id string) (*Product, error)
+
+	// List returns a page of products matching the query
+	List(ctx context.Context, query ListQuery) (*Page, error)
+
+	// Update saves the attributes and availability of a product
+	Update(ctx context.Context, product *Product) (*Product, error)
+
+	// Delete removes a product
+	Delete(ctx context.Context, id string) error
+
+	// UpdateStock adds quantityChange, which may be negative, to the stock of a product
+	UpdateStock(ctx context.Context, id string, quantityChange int32) (*Product, error)
+}
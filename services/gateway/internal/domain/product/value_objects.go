@@ -0,0 +1,61 @@
+package product
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Money 金額の値オブジェクト
+// Amount は通貨の最小単位（円の場合は円）
+type Money struct {
+	amount   int64
+	currency string
+}
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NewMoney creates a new Money value object with an ISO 4217 currency code
+func NewMoney(amount int64, currency string) (Money, error) {
+	if amount < 0 {
+		return Money{}, fmt.Errorf("amount cannot be negative: %d", amount)
+	}
+	if !currencyRegex.MatchString(currency) {
+		return Money{}, fmt.Errorf("invalid currency code: %s", currency)
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+func (m Money) Amount() int64    { return m.amount }
+func (m Money) Currency() string { return m.currency }
+
+// Multiply returns the amount for the given quantity
+func (m Money) Multiply(quantity int32) Money {
+	return Money{amount: m.amount * int64(quantity), currency: m.currency}
+}
+
+// Details 商品の登録・更新で指定する属性
+type Details struct {
+	Name        string
+	Description string
+	Price       Money
+	Category    string
+	ImageURLs   []string
+	SKU         string
+}
+
+// ListQuery 商品一覧の検索条件
+// Page は 1 始まり、PageSize の上限は商品サービスが決める
+type ListQuery struct {
+	Page     int32
+	PageSize int32
+	Category string
+	Search   string
+}
+
+// Page 商品一覧の 1 ページ分
+type Page struct {
+	Products    []*Product
+	TotalCount  int32
+	TotalPages  int32
+	CurrentPage int32
+}
@@ -0,0 +1,50 @@
+package user
+
+import "context"
+
+// Principal 認証済みの呼び出し元を表す値オブジェクト
+type Principal struct {
+	userID        string
+	email         string
+	emailVerified bool
+	roles         []string
+}
+
+// NewPrincipal creates a new Principal value object
+func NewPrincipal(userID, email string, emailVerified bool, roles []string) *Principal {
+	return &Principal{
+		userID:        userID,
+		email:         email,
+		emailVerified: emailVerified,
+		roles:         roles,
+	}
+}
+
+// Getters
+func (p *Principal) UserID() string      { return p.userID }
+func (p *Principal) Email() string       { return p.email }
+func (p *Principal) EmailVerified() bool { return p.emailVerified }
+func (p *Principal) Roles() []string     { return p.roles }
+
+// HasRole reports whether the principal was granted the role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated principal
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
@@ -7,8 +7,37 @@ type AuthRepository interface {
 	// Register creates a new user and returns auth tokens
 	Register(ctx context.Context, email Email, password string, name string, phoneNumber *PhoneNumber) (*User, AuthTokens, error)
 
-	// Login authenticates a user and returns auth tokens
+	// Login authenticates a user and returns auth tokens, or an MFA challenge
+	// when the user has MFA enabled
 	Login(ctx context.Context, email Email, password string) (*User, AuthTokens, error)
+
+	// VerifyToken validates an access token and returns its principal.
+	// It returns ErrUnauthorized if the token is invalid.
+	VerifyToken(ctx context.Context, token string) (*Principal, error)
+
+	// RefreshToken exchanges a refresh token for new tokens
+	RefreshToken(ctx context.Context, refreshToken string) (AuthTokens, error)
+
+	// Logout revokes an access token and the refresh tokens of its session
+	Logout(ctx context.Context, userID string, accessToken string) error
+
+	// ChangePassword changes the password and logs out every session of the user
+	ChangePassword(ctx context.Context, userID string, oldPassword string, newPassword string) error
+
+	// VerifyMFA completes a login with the MFA challenge token and a TOTP or recovery code
+	VerifyMFA(ctx context.Context, mfaToken string, code string) (*User, AuthTokens, error)
+
+	// EnrollMFA starts a TOTP enrollment
+	EnrollMFA(ctx context.Context, userID string) (MFAEnrollment, error)
+
+	// ConfirmMFA enables MFA and returns the recovery codes
+	ConfirmMFA(ctx context.Context, userID string, code string) ([]string, error)
+
+	// GenerateRecoveryCodes replaces the recovery codes
+	GenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error)
+
+	// DisableMFA turns MFA off
+	DisableMFA(ctx context.Context, userID string, code string) error
 }
 
 // UserRepository defines the interface for user management operations
@@ -54,9 +54,13 @@ func (p PhoneNumber) String() string {
 }
 
 // AuthTokens 認証トークンの値オブジェクト
+// MFA が有効なユーザーのログインでは AccessToken/RefreshToken の代わりに MFAToken が入る
+// EmailVerified はトークン発行時点のメールアドレス確認状態
 type AuthTokens struct {
-	AccessToken  string
-	RefreshToken string
+	AccessToken   string
+	RefreshToken  string
+	MFAToken      string
+	EmailVerified bool
 }
 
 // NewAuthTokens creates a new AuthTokens value object
@@ -66,3 +70,21 @@ func NewAuthTokens(accessToken, refreshToken string) AuthTokens {
 		RefreshToken: refreshToken,
 	}
 }
+
+// NewMFAChallenge creates AuthTokens holding only an MFA challenge token
+func NewMFAChallenge(mfaToken string) AuthTokens {
+	return AuthTokens{
+		MFAToken: mfaToken,
+	}
+}
+
+// MFARequired reports whether the login must be completed with a second factor
+func (t AuthTokens) MFARequired() bool {
+	return t.MFAToken != ""
+}
+
+// MFAEnrollment TOTP 登録情報の値オブジェクト
+type MFAEnrollment struct {
+	Secret     string
+	OTPAuthURI string
+}
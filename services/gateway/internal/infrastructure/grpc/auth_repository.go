@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
 	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
 )
 
@@ -51,6 +53,7 @@ func (r *AuthRepository) Register(
 	}
 
 	tokens := user.NewAuthTokens(resp.AccessToken, resp.RefreshToken)
+	tokens.EmailVerified = resp.EmailVerified
 
 	return domainUser, tokens, nil
 }
@@ -74,18 +77,93 @@ func (r *AuthRepository) Login(
 		return nil, user.AuthTokens{}, mapGRPCError(err)
 	}
 
-	domainUser, err := loginResponseToDomainUser(resp)
+	return loginResponseToDomain(resp)
+}
+
+// VerifyMFA completes a login with a second factor via auth service
+func (r *AuthRepository) VerifyMFA(ctx context.Context, mfaToken string, code string) (*user.User, user.AuthTokens, error) {
+	req := &authpb.VerifyMFARequest{
+		MfaToken: mfaToken,
+		Code:     code,
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.VerifyMFA(ctx, req)
 	if err != nil {
-		return nil, user.AuthTokens{}, err
+		return nil, user.AuthTokens{}, mapGRPCError(err)
 	}
 
-	tokens := user.NewAuthTokens(resp.AccessToken, resp.RefreshToken)
+	return loginResponseToDomain(resp)
+}
 
-	return domainUser, tokens, nil
+// EnrollMFA starts a TOTP enrollment via auth service
+func (r *AuthRepository) EnrollMFA(ctx context.Context, userID string) (user.MFAEnrollment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.EnrollMFA(ctx, &authpb.EnrollMFARequest{UserId: userID})
+	if err != nil {
+		return user.MFAEnrollment{}, mapGRPCError(err)
+	}
+
+	return user.MFAEnrollment{
+		Secret:     resp.Secret,
+		OTPAuthURI: resp.OtpauthUri,
+	}, nil
+}
+
+// ConfirmMFA enables MFA via auth service
+func (r *AuthRepository) ConfirmMFA(ctx context.Context, userID string, code string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.ConfirmMFA(ctx, &authpb.ConfirmMFARequest{
+		UserId: userID,
+		Code:   code,
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return resp.RecoveryCodes, nil
+}
+
+// GenerateRecoveryCodes replaces the recovery codes via auth service
+func (r *AuthRepository) GenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GenerateRecoveryCodes(ctx, &authpb.GenerateRecoveryCodesRequest{
+		UserId: userID,
+		Code:   code,
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return resp.RecoveryCodes, nil
+}
+
+// DisableMFA turns MFA off via auth service
+func (r *AuthRepository) DisableMFA(ctx context.Context, userID string, code string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.client.DisableMFA(ctx, &authpb.DisableMFARequest{
+		UserId: userID,
+		Code:   code,
+	})
+	if err != nil {
+		return mapGRPCError(err)
+	}
+
+	return nil
 }
 
 // VerifyToken verifies a JWT token via auth service
-func (r *AuthRepository) VerifyToken(ctx context.Context, token string) (bool, string, error) {
+func (r *AuthRepository) VerifyToken(ctx context.Context, token string) (*user.Principal, error) {
 	req := &authpb.VerifyTokenRequest{
 		Token: token,
 	}
@@ -95,10 +173,13 @@ func (r *AuthRepository) VerifyToken(ctx context.Context, token string) (bool, s
 
 	resp, err := r.client.VerifyToken(ctx, req)
 	if err != nil {
-		return false, "", mapGRPCError(err)
+		return nil, mapGRPCError(err)
+	}
+	if !resp.Valid {
+		return nil, errors.ErrUnauthorized
 	}
 
-	return resp.Valid, resp.UserId, nil
+	return user.NewPrincipal(resp.UserId, resp.Email, resp.EmailVerified, resp.Roles), nil
 }
 
 // RefreshToken generates new tokens via auth service
@@ -119,6 +200,66 @@ func (r *AuthRepository) RefreshToken(ctx context.Context, refreshToken string)
 	return tokens, nil
 }
 
+// Logout revokes an access token via auth service
+func (r *AuthRepository) Logout(ctx context.Context, userID string, accessToken string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.client.Logout(ctx, &authpb.LogoutRequest{
+		UserId: userID,
+		Token:  accessToken,
+	})
+	if err != nil {
+		return mapGRPCError(err)
+	}
+
+	return nil
+}
+
+// ChangePassword changes a user's password via auth service
+func (r *AuthRepository) ChangePassword(ctx context.Context, userID string, oldPassword string, newPassword string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.client.ChangePassword(ctx, &authpb.ChangePasswordRequest{
+		UserId:      userID,
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		return mapGRPCError(err)
+	}
+
+	return nil
+}
+
+// GetJWKS retrieves the public token verification keys via auth service
+func (r *AuthRepository) GetJWKS(ctx context.Context) (auth.JWKS, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetJWKS(ctx, &authpb.GetJWKSRequest{})
+	if err != nil {
+		return auth.JWKS{}, mapGRPCError(err)
+	}
+
+	jwks := auth.JWKS{Keys: make([]auth.JWK, 0, len(resp.Keys))}
+	for _, key := range resp.Keys {
+		jwks.Keys = append(jwks.Keys, auth.JWK{
+			KeyType:   key.Kty,
+			KeyID:     key.Kid,
+			Use:       key.Use,
+			Algorithm: key.Alg,
+			N:         key.N,
+			E:         key.E,
+			Curve:     key.Crv,
+			X:         key.X,
+		})
+	}
+
+	return jwks, nil
+}
+
 // Helper functions for auth repository
 
 // authResponseToDomainUser converts auth RegisterResponse to domain User
@@ -147,6 +288,23 @@ func authResponseToDomainUser(resp *authpb.RegisterResponse) (*user.User, error)
 	), nil
 }
 
+// loginResponseToDomain converts auth LoginResponse to domain User and the
+// issued tokens, or the MFA challenge when a second factor is required
+func loginResponseToDomain(resp *authpb.LoginResponse) (*user.User, user.AuthTokens, error) {
+	domainUser, err := loginResponseToDomainUser(resp)
+	if err != nil {
+		return nil, user.AuthTokens{}, err
+	}
+
+	tokens := user.NewAuthTokens(resp.AccessToken, resp.RefreshToken)
+	if resp.MfaRequired {
+		tokens = user.NewMFAChallenge(resp.MfaToken)
+	}
+	tokens.EmailVerified = resp.EmailVerified
+
+	return domainUser, tokens, nil
+}
+
 // loginResponseToDomainUser converts auth LoginResponse to domain User
 func loginResponseToDomainUser(resp *authpb.LoginResponse) (*user.User, error) {
 	email, err := user.NewEmail(resp.Email)
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	checkoutpb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/checkout"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+)
+
+// checkoutTimeout is longer than the other calls because a checkout calls the
+// order, product and payment services in turn. It stays within the HTTP
+// server's write timeout; the checkout service finishes or compensates a
+// checkout the gateway gave up waiting for.
+const checkoutTimeout = 12 * time.Second
+
+// checkoutStatuses maps protobuf checkout statuses to domain statuses
+var checkoutStatuses = map[checkoutpb.CheckoutStatus]checkout.Status{
+	checkoutpb.CheckoutStatus_CHECKOUT_STATUS_IN_PROGRESS: checkout.StatusInProgress,
+	checkoutpb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED:   checkout.StatusCompleted,
+	checkoutpb.CheckoutStatus_CHECKOUT_STATUS_FAILED:      checkout.StatusFailed,
+}
+
+// checkoutFailures maps protobuf checkout failures to domain failures
+var checkoutFailures = map[checkoutpb.CheckoutFailure]checkout.Failure{
+	checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_UNSPECIFIED:         checkout.FailureNone,
+	checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_PRODUCT_UNAVAILABLE: checkout.FailureProductUnavailable,
+	checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK:        checkout.FailureOutOfStock,
+	checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED:    checkout.FailurePaymentDeclined,
+	checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_INTERNAL:            checkout.FailureInternal,
+}
+
+// CheckoutRepository implements checkout.CheckoutRepository using gRPC
+type CheckoutRepository struct {
+	client checkoutpb.CheckoutServiceClient
+}
+
+// NewCheckoutRepository creates a new CheckoutRepository
+func NewCheckoutRepository(client checkoutpb.CheckoutServiceClient) *CheckoutRepository {
+	return &CheckoutRepository{
+		client: client,
+	}
+}
+
+// Checkout runs a checkout via checkout service
+func (r *CheckoutRepository) Checkout(
+	ctx context.Context,
+	userID string,
+	items []checkout.Item,
+	shippingAddress order.Address,
+	method payment.Method,
+	paymentToken string,
+) (*checkout.Checkout, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkoutTimeout)
+	defer cancel()
+
+	pbItems := make([]*checkoutpb.CheckoutItem, 0, len(items))
+	for _, item := range items {
+		pbItems = append(pbItems, &checkoutpb.CheckoutItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	resp, err := r.client.Checkout(ctx, &checkoutpb.CheckoutRequest{
+		UserId:          userID,
+		Items:           pbItems,
+		ShippingAddress: toPBAddress(shippingAddress),
+		PaymentMethod:   paymentMethods[method],
+		PaymentToken:    paymentToken,
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainCheckout(resp), nil
+}
+
+// FindByID retrieves a checkout by ID via checkout service
+func (r *CheckoutRepository) FindByID(ctx context.Context, id string) (*checkout.Checkout, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetCheckout(ctx, &checkoutpb.GetCheckoutRequest{Id: id})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainCheckout(resp), nil
+}
+
+// toDomainCheckout converts protobuf CheckoutResponse to domain Checkout
+func toDomainCheckout(resp *checkoutpb.CheckoutResponse) *checkout.Checkout {
+	return checkout.NewCheckout(
+		resp.Id,
+		resp.UserId,
+		checkoutStatuses[resp.Status],
+		resp.OrderId,
+		resp.PaymentId,
+		resp.TransactionId,
+		checkoutFailures[resp.Failure],
+		resp.FailureMessage,
+		timestampToTime(resp.CreatedAt),
+		timestampToTime(resp.UpdatedAt),
+	)
+}
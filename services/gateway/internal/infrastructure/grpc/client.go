@@ -6,30 +6,54 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/pkg/clientip"
 	authpb "github.com/Riku-KANO/kube-ec/proto/auth"
+	checkoutpb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
 	userpb "github.com/Riku-KANO/kube-ec/proto/user"
 )
 
 // ClientConfig holds gRPC client configuration
 type ClientConfig struct {
-	AuthServiceAddr string
-	UserServiceAddr string
+	AuthServiceAddr     string
+	UserServiceAddr     string
+	ProductServiceAddr  string
+	OrderServiceAddr    string
+	PaymentServiceAddr  string
+	CheckoutServiceAddr string
 }
 
 // Clients holds all gRPC clients and connections
 type Clients struct {
-	AuthClient authpb.AuthServiceClient
-	UserClient userpb.UserServiceClient
-	authConn   *grpc.ClientConn
-	userConn   *grpc.ClientConn
+	AuthClient     authpb.AuthServiceClient
+	UserClient     userpb.UserServiceClient
+	ProductClient  productpb.ProductServiceClient
+	OrderClient    orderpb.OrderServiceClient
+	PaymentClient  paymentpb.PaymentServiceClient
+	CheckoutClient checkoutpb.CheckoutServiceClient
+	authConn       *grpc.ClientConn
+	userConn       *grpc.ClientConn
+	productConn    *grpc.ClientConn
+	orderConn      *grpc.ClientConn
+	paymentConn    *grpc.ClientConn
+	checkoutConn   *grpc.ClientConn
 }
 
 // NewClients creates new gRPC clients
 func NewClients(config ClientConfig) (*Clients, error) {
 	// Connect to auth service
+	// The client IP is forwarded so the auth service can throttle failed logins per IP,
+	// and the caller's access token so it can check who changes account settings
 	authConn, err := grpc.Dial(
 		config.AuthServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			clientip.UnaryClientInterceptor(),
+			auth.UnaryClientInterceptor(),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to auth service: %w", err)
@@ -45,11 +69,77 @@ func NewClients(config ClientConfig) (*Clients, error) {
 		return nil, fmt.Errorf("failed to connect to user service: %w", err)
 	}
 
+	// Connect to product service
+	// The caller's access token is forwarded so the product service can authorize staff operations
+	productConn, err := grpc.Dial(
+		config.ProductServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		authConn.Close()
+		userConn.Close()
+		return nil, fmt.Errorf("failed to connect to product service: %w", err)
+	}
+
+	// Connect to order service
+	// The caller's access token is forwarded so the order service can check who owns an order
+	orderConn, err := grpc.Dial(
+		config.OrderServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		authConn.Close()
+		userConn.Close()
+		productConn.Close()
+		return nil, fmt.Errorf("failed to connect to order service: %w", err)
+	}
+
+	// Connect to payment service
+	// The caller's access token is forwarded so the payment service can check who owns a payment
+	paymentConn, err := grpc.Dial(
+		config.PaymentServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		authConn.Close()
+		userConn.Close()
+		productConn.Close()
+		orderConn.Close()
+		return nil, fmt.Errorf("failed to connect to payment service: %w", err)
+	}
+
+	// Connect to checkout service
+	// The caller's access token is forwarded so the checkout service places the order for the caller
+	checkoutConn, err := grpc.Dial(
+		config.CheckoutServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		authConn.Close()
+		userConn.Close()
+		productConn.Close()
+		orderConn.Close()
+		paymentConn.Close()
+		return nil, fmt.Errorf("failed to connect to checkout service: %w", err)
+	}
+
 	return &Clients{
-		AuthClient: authpb.NewAuthServiceClient(authConn),
-		UserClient: userpb.NewUserServiceClient(userConn),
-		authConn:   authConn,
-		userConn:   userConn,
+		AuthClient:     authpb.NewAuthServiceClient(authConn),
+		UserClient:     userpb.NewUserServiceClient(userConn),
+		ProductClient:  productpb.NewProductServiceClient(productConn),
+		OrderClient:    orderpb.NewOrderServiceClient(orderConn),
+		PaymentClient:  paymentpb.NewPaymentServiceClient(paymentConn),
+		CheckoutClient: checkoutpb.NewCheckoutServiceClient(checkoutConn),
+		authConn:       authConn,
+		userConn:       userConn,
+		productConn:    productConn,
+		orderConn:      orderConn,
+		paymentConn:    paymentConn,
+		checkoutConn:   checkoutConn,
 	}, nil
 }
 
@@ -66,5 +156,25 @@ func (c *Clients) Close() error {
 			err = closeErr
 		}
 	}
+	if c.productConn != nil {
+		if closeErr := c.productConn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	if c.orderConn != nil {
+		if closeErr := c.orderConn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	if c.paymentConn != nil {
+		if closeErr := c.paymentConn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	if c.checkoutConn != nil {
+		if closeErr := c.checkoutConn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
 	return err
 }
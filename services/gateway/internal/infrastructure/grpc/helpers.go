@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
 	"github.com/Riku-KANO/kube-ec/proto/common"
 	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
 )
@@ -40,7 +41,15 @@ func mapGRPCError(err error) error {
 	case codes.Unauthenticated:
 		return errors.ErrUnauthorized
 	case codes.PermissionDenied:
-		return errors.ErrUnauthorized
+		return errors.ErrForbidden
+	case codes.FailedPrecondition:
+		// 認証サービスはメール未確認を FailedPrecondition で返すためメッセージで区別する
+		if st.Message() == pkgerrors.ErrEmailNotVerified.Message {
+			return errors.ErrEmailNotVerified
+		}
+		return errors.ErrConflict
+	case codes.ResourceExhausted:
+		return errors.ErrTooManyRequests
 	case codes.DeadlineExceeded:
 		return errors.ErrInternalError
 	case codes.Unavailable:
@@ -0,0 +1,255 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/order"
+)
+
+// orderStatuses maps domain order statuses to protobuf statuses
+var orderStatuses = map[order.Status]orderpb.OrderStatus{
+	order.StatusPending:    orderpb.OrderStatus_ORDER_STATUS_PENDING,
+	order.StatusConfirmed:  orderpb.OrderStatus_ORDER_STATUS_CONFIRMED,
+	order.StatusProcessing: orderpb.OrderStatus_ORDER_STATUS_PROCESSING,
+	order.StatusShipped:    orderpb.OrderStatus_ORDER_STATUS_SHIPPED,
+	order.StatusDelivered:  orderpb.OrderStatus_ORDER_STATUS_DELIVERED,
+	order.StatusCancelled:  orderpb.OrderStatus_ORDER_STATUS_CANCELLED,
+}
+
+// OrderRepository implements order.OrderRepository using gRPC
+type OrderRepository struct {
+	client orderpb.OrderServiceClient
+}
+
+// NewOrderRepository creates a new OrderRepository
+func NewOrderRepository(client orderpb.OrderServiceClient) *OrderRepository {
+	return &OrderRepository{
+		client: client,
+	}
+}
+
+// Create places an order via order service
+func (r *OrderRepository) Create(
+	ctx context.Context,
+	userID string,
+	items []order.LineItem,
+	shippingAddress order.Address,
+	billingAddress order.Address,
+) (*order.Order, error) {
+	req := &orderpb.CreateOrderRequest{
+		UserId:          userID,
+		Items:           make([]*orderpb.OrderItem, 0, len(items)),
+		ShippingAddress: toPBAddress(shippingAddress),
+		BillingAddress:  toOptionalPBAddress(billingAddress),
+	}
+	for _, item := range items {
+		req.Items = append(req.Items, &orderpb.OrderItem{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.CreateOrder(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainOrder(resp)
+}
+
+// FindByID retrieves an order by ID via order service
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetOrder(ctx, &orderpb.GetOrderRequest{Id: id})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainOrder(resp)
+}
+
+// ListByUser returns a page of the orders of a user via order service
+func (r *OrderRepository) ListByUser(ctx context.Context, query order.ListQuery) (*order.Page, error) {
+	req := &orderpb.ListOrdersRequest{
+		UserId: query.UserID,
+		Pagination: &common.Pagination{
+			Page:     query.Page,
+			PageSize: query.PageSize,
+		},
+	}
+	if query.Status != "" {
+		req.Status = orderStatuses[query.Status]
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.ListOrders(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	page := &order.Page{
+		Orders: make([]*order.Order, 0, len(resp.Orders)),
+	}
+	for _, pbOrder := range resp.Orders {
+		o, err := toDomainOrder(pbOrder)
+		if err != nil {
+			return nil, err
+		}
+		page.Orders = append(page.Orders, o)
+	}
+	if resp.Pagination != nil {
+		page.TotalCount = resp.Pagination.TotalCount
+		page.TotalPages = resp.Pagination.TotalPages
+		page.CurrentPage = resp.Pagination.CurrentPage
+	}
+
+	return page, nil
+}
+
+// Cancel cancels an order via order service
+func (r *OrderRepository) Cancel(ctx context.Context, id string, reason string) (*order.Order, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.CancelOrder(ctx, &orderpb.CancelOrderRequest{
+		Id:     id,
+		Reason: reason,
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainOrder(resp)
+}
+
+// ChangeAddress replaces the addresses of an order via order service
+func (r *OrderRepository) ChangeAddress(ctx context.Context, id string, shippingAddress, billingAddress order.Address) (*order.Order, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.UpdateOrderAddress(ctx, &orderpb.UpdateOrderAddressRequest{
+		Id:              id,
+		ShippingAddress: toPBAddress(shippingAddress),
+		BillingAddress:  toOptionalPBAddress(billingAddress),
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainOrder(resp)
+}
+
+// History retrieves the status history of an order via order service
+func (r *OrderRepository) History(ctx context.Context, id string) ([]order.StatusChange, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetOrderHistory(ctx, &orderpb.GetOrderHistoryRequest{OrderId: id})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	changes := make([]order.StatusChange, 0, len(resp.Changes))
+	for _, change := range resp.Changes {
+		changes = append(changes, order.StatusChange{
+			From:      toDomainOrderStatus(change.FromStatus),
+			To:        toDomainOrderStatus(change.ToStatus),
+			ActorType: change.ActorType,
+			ActorID:   change.ActorId,
+			Reason:    change.Reason,
+			ChangedAt: timestampToTime(change.ChangedAt),
+		})
+	}
+	return changes, nil
+}
+
+// toDomainOrder converts protobuf Order to domain Order
+func toDomainOrder(pbOrder *orderpb.Order) (*order.Order, error) {
+	items := make([]order.Item, 0, len(pbOrder.Items))
+	for _, pbItem := range pbOrder.Items {
+		unitPrice, err := toDomainMoney(pbItem.UnitPrice)
+		if err != nil {
+			return nil, err
+		}
+		subtotal, err := toDomainMoney(pbItem.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, order.Item{
+			ProductID:   pbItem.ProductId,
+			ProductName: pbItem.ProductName,
+			Quantity:    pbItem.Quantity,
+			UnitPrice:   unitPrice,
+			Subtotal:    subtotal,
+		})
+	}
+
+	totalAmount, err := toDomainMoney(pbOrder.TotalAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return order.NewOrder(
+		pbOrder.Id,
+		pbOrder.UserId,
+		items,
+		totalAmount,
+		toDomainOrderStatus(pbOrder.Status),
+		toDomainAddress(pbOrder.ShippingAddress),
+		toDomainAddress(pbOrder.BillingAddress),
+		pbOrder.PaymentId,
+		timestampToTime(pbOrder.CreatedAt),
+		timestampToTime(pbOrder.UpdatedAt),
+	), nil
+}
+
+// toDomainOrderStatus converts a protobuf status to a domain status.
+// Unspecified statuses become the empty status.
+func toDomainOrderStatus(status orderpb.OrderStatus) order.Status {
+	for domainStatus, pbStatus := range orderStatuses {
+		if pbStatus == status {
+			return domainStatus
+		}
+	}
+	return ""
+}
+
+// toDomainAddress converts protobuf Address to domain Address.
+// Addresses stored by the order service were validated when the order was placed.
+func toDomainAddress(a *common.Address) order.Address {
+	if a == nil {
+		return order.Address{}
+	}
+	address, _ := order.NewAddress(a.PostalCode, a.Prefecture, a.City, a.AddressLine1, a.AddressLine2, a.PhoneNumber)
+	return address
+}
+
+// toOptionalPBAddress converts domain Address to protobuf Address,
+// leaving a zero address unset
+func toOptionalPBAddress(a order.Address) *common.Address {
+	if a.IsZero() {
+		return nil
+	}
+	return toPBAddress(a)
+}
+
+// toPBAddress converts domain Address to protobuf Address
+func toPBAddress(a order.Address) *common.Address {
+	return &common.Address{
+		PostalCode:   a.PostalCode(),
+		Prefecture:   a.Prefecture(),
+		City:         a.City(),
+		AddressLine1: a.AddressLine1(),
+		AddressLine2: a.AddressLine2(),
+		PhoneNumber:  a.PhoneNumber(),
+	}
+}
@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/payment"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// paymentStatuses maps protobuf payment statuses to domain statuses
+var paymentStatuses = map[paymentpb.PaymentStatus]payment.Status{
+	paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING:    payment.StatusPending,
+	paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING: payment.StatusProcessing,
+	paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED:  payment.StatusCompleted,
+	paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED:     payment.StatusFailed,
+	paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED:   payment.StatusRefunded,
+}
+
+// paymentMethods maps domain payment methods to protobuf methods
+var paymentMethods = map[payment.Method]paymentpb.PaymentMethod{
+	payment.MethodCreditCard:       paymentpb.PaymentMethod_PAYMENT_METHOD_CREDIT_CARD,
+	payment.MethodBankTransfer:     paymentpb.PaymentMethod_PAYMENT_METHOD_BANK_TRANSFER,
+	payment.MethodConvenienceStore: paymentpb.PaymentMethod_PAYMENT_METHOD_CONVENIENCE_STORE,
+	payment.MethodElectronicMoney:  paymentpb.PaymentMethod_PAYMENT_METHOD_ELECTRONIC_MONEY,
+}
+
+// PaymentRepository implements payment.PaymentRepository using gRPC
+type PaymentRepository struct {
+	client paymentpb.PaymentServiceClient
+}
+
+// NewPaymentRepository creates a new PaymentRepository
+func NewPaymentRepository(client paymentpb.PaymentServiceClient) *PaymentRepository {
+	return &PaymentRepository{
+		client: client,
+	}
+}
+
+// Create starts a payment via payment service
+func (r *PaymentRepository) Create(
+	ctx context.Context,
+	orderID string,
+	userID string,
+	amount product.Money,
+	method payment.Method,
+) (*payment.Payment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.CreatePayment(ctx, &paymentpb.CreatePaymentRequest{
+		OrderId: orderID,
+		UserId:  userID,
+		Amount:  toPBMoney(amount),
+		Method:  paymentMethods[method],
+	})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainPayment(resp)
+}
+
+// FindByID retrieves a payment by ID via payment service
+func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*payment.Payment, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetPayment(ctx, &paymentpb.GetPaymentRequest{Id: id})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainPayment(resp)
+}
+
+// Process charges a payment via payment service
+func (r *PaymentRepository) Process(ctx context.Context, id string, paymentToken string) (payment.Charge, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.ProcessPayment(ctx, &paymentpb.ProcessPaymentRequest{
+		PaymentId:    id,
+		PaymentToken: paymentToken,
+	})
+	if err != nil {
+		return payment.Charge{}, mapGRPCError(err)
+	}
+
+	// 決済サービスは決済の失敗をエラーではなく Success=false で返す
+	if !resp.Success {
+		return payment.Charge{}, errors.ErrPaymentDeclined
+	}
+
+	return payment.Charge{
+		TransactionID: resp.TransactionId,
+		Message:       resp.Message,
+	}, nil
+}
+
+// Refund refunds a payment via payment service
+func (r *PaymentRepository) Refund(ctx context.Context, id string, amount product.Money, reason string) (payment.Refund, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{
+		PaymentId: id,
+		Amount:    toPBMoney(amount),
+		Reason:    reason,
+	})
+	if err != nil {
+		return payment.Refund{}, mapGRPCError(err)
+	}
+
+	// 返金できない状態の決済は Success=false で返される
+	if !resp.Success {
+		return payment.Refund{}, errors.ErrConflict
+	}
+
+	return payment.Refund{
+		RefundID: resp.RefundId,
+		Message:  resp.Message,
+	}, nil
+}
+
+// toDomainPayment converts protobuf Payment to domain Payment
+func toDomainPayment(pbPayment *paymentpb.Payment) (*payment.Payment, error) {
+	amount, err := toDomainMoney(pbPayment.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment.NewPayment(
+		pbPayment.Id,
+		pbPayment.OrderId,
+		pbPayment.UserId,
+		amount,
+		paymentStatuses[pbPayment.Status],
+		toDomainPaymentMethod(pbPayment.Method),
+		pbPayment.TransactionId,
+		timestampToTime(pbPayment.CreatedAt),
+		timestampToTime(pbPayment.UpdatedAt),
+	), nil
+}
+
+// toDomainPaymentMethod converts a protobuf payment method to a domain method.
+// Unspecified methods become the empty method.
+func toDomainPaymentMethod(method paymentpb.PaymentMethod) payment.Method {
+	for domainMethod, pbMethod := range paymentMethods {
+		if pbMethod == method {
+			return domainMethod
+		}
+	}
+	return ""
+}
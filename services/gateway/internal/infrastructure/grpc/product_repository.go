@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/product"
+)
+
+// ProductRepository implements product.ProductRepository using gRPC
+type ProductRepository struct {
+	client productpb.ProductServiceClient
+}
+
+// NewProductRepository creates a new ProductRepository
+func NewProductRepository(client productpb.ProductServiceClient) *ProductRepository {
+	return &ProductRepository{
+		client: client,
+	}
+}
+
+// Create registers a new product via product service
+func (r *ProductRepository) Create(ctx context.Context, details product.Details, stockQuantity int32) (*product.Product, error) {
+	req := &productpb.CreateProductRequest{
+		Name:          details.Name,
+		Description:   details.Description,
+		Price:         toPBMoney(details.Price),
+		StockQuantity: stockQuantity,
+		Category:      details.Category,
+		ImageUrls:     details.ImageURLs,
+		Sku:           details.SKU,
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.CreateProduct(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainProduct(resp)
+}
+
+// FindByID retrieves a product by ID via product service
+func (r *ProductRepository) FindByID(ctx context.Context, id string) (*product.Product, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.GetProduct(ctx, &productpb.GetProductRequest{Id: id})
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainProduct(resp)
+}
+
+// List returns a page of products via product service
+func (r *ProductRepository) List(ctx context.Context, query product.ListQuery) (*product.Page, error) {
+	req := &productpb.ListProductsRequest{
+		Pagination: &common.Pagination{
+			Page:     query.Page,
+			PageSize: query.PageSize,
+		},
+		Category:    query.Category,
+		SearchQuery: query.Search,
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.ListProducts(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	page := &product.Page{
+		Products: make([]*product.Product, 0, len(resp.Products)),
+	}
+	for _, pbProduct := range resp.Products {
+		p, err := toDomainProduct(pbProduct)
+		if err != nil {
+			return nil, err
+		}
+		page.Products = append(page.Products, p)
+	}
+	if resp.Pagination != nil {
+		page.TotalCount = resp.Pagination.TotalCount
+		page.TotalPages = resp.Pagination.TotalPages
+		page.CurrentPage = resp.Pagination.CurrentPage
+	}
+
+	return page, nil
+}
+
+// Update saves the details of a product via product service.
+// The stock is not sent; it changes only through UpdateStock and reservations.
+func (r *ProductRepository) Update(ctx context.Context, p *product.Product) (*product.Product, error) {
+	req := &productpb.UpdateProductRequest{
+		Id:          p.ID(),
+		Name:        p.Name(),
+		Description: p.Description(),
+		Price:       toPBMoney(p.Price()),
+		Category:    p.Category(),
+		ImageUrls:   p.ImageURLs(),
+		Sku:         p.SKU(),
+		IsActive:    p.IsActive(),
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.UpdateProduct(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainProduct(resp)
+}
+
+// Delete removes a product via product service
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.client.DeleteProduct(ctx, &productpb.DeleteProductRequest{Id: id})
+	if err != nil {
+		return mapGRPCError(err)
+	}
+
+	return nil
+}
+
+// UpdateStock changes the stock of a product via product service
+func (r *ProductRepository) UpdateStock(ctx context.Context, id string, quantityChange int32) (*product.Product, error) {
+	req := &productpb.UpdateStockRequest{
+		ProductId:      id,
+		QuantityChange: quantityChange,
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	resp, err := r.client.UpdateStock(ctx, req)
+	if err != nil {
+		return nil, mapGRPCError(err)
+	}
+
+	return toDomainProduct(resp)
+}
+
+// toDomainProduct converts protobuf Product to domain Product
+func toDomainProduct(pbProduct *productpb.Product) (*product.Product, error) {
+	price, err := toDomainMoney(pbProduct.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	return product.NewProduct(
+		pbProduct.Id,
+		product.Details{
+			Name:        pbProduct.Name,
+			Description: pbProduct.Description,
+			Price:       price,
+			Category:    pbProduct.Category,
+			ImageURLs:   pbProduct.ImageUrls,
+			SKU:         pbProduct.Sku,
+		},
+		pbProduct.StockQuantity,
+		pbProduct.IsActive,
+		timestampToTime(pbProduct.CreatedAt),
+		timestampToTime(pbProduct.UpdatedAt),
+	), nil
+}
+
+// toDomainMoney converts protobuf Money to domain Money
+func toDomainMoney(m *common.Money) (product.Money, error) {
+	if m == nil {
+		return product.Money{}, nil
+	}
+	return product.NewMoney(m.Amount, m.Currency)
+}
+
+// toPBMoney converts domain Money to protobuf Money
+func toPBMoney(m product.Money) *common.Money {
+	return &common.Money{
+		Currency: m.Currency(),
+		Amount:   m.Amount(),
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	appcheckout "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/checkout"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckoutHandler handles HTTP requests for checkouts.
+// Every route requires an access token.
+type CheckoutHandler struct {
+	checkoutService *appcheckout.Service
+}
+
+// NewCheckoutHandler creates a new CheckoutHandler
+func NewCheckoutHandler(checkoutService *appcheckout.Service) *CheckoutHandler {
+	return &CheckoutHandler{
+		checkoutService: checkoutService,
+	}
+}
+
+// Checkout implements POST /checkout
+func (h *CheckoutHandler) Checkout(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.checkoutService.Checkout(c.Request.Context(), principal, toCheckoutInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCheckoutResponse(output))
+}
+
+// GetCheckout implements GET /checkout/{id}
+func (h *CheckoutHandler) GetCheckout(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.checkoutService.GetCheckout(c.Request.Context(), principal, id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCheckoutResponse(output))
+}
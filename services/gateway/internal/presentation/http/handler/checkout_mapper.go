@@ -0,0 +1,48 @@
+package handler
+
+import (
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	appcheckout "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/checkout"
+)
+
+// toCheckoutInput converts OpenAPI CheckoutRequest to application CheckoutInput
+func toCheckoutInput(req api.CheckoutRequest) appcheckout.CheckoutInput {
+	input := appcheckout.CheckoutInput{
+		Items:           make([]appcheckout.CheckoutItemInput, 0, len(req.Items)),
+		ShippingAddress: appcheckout.AddressInput(toAddressInput(req.ShippingAddress)),
+		PaymentMethod:   string(req.PaymentMethod),
+		PaymentToken:    req.PaymentToken,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, appcheckout.CheckoutItemInput{
+			ProductID: item.ProductId,
+			Quantity:  item.Quantity,
+		})
+	}
+	return input
+}
+
+// toCheckoutResponse converts application CheckoutOutput to OpenAPI Checkout
+func toCheckoutResponse(output appcheckout.CheckoutOutput) api.Checkout {
+	resp := api.Checkout{
+		Id:        output.ID,
+		Status:    api.CheckoutStatus(output.Status),
+		CreatedAt: output.CreatedAt,
+		UpdatedAt: output.UpdatedAt,
+	}
+	if output.OrderID != "" {
+		resp.OrderId = &output.OrderID
+	}
+	if output.PaymentID != "" {
+		resp.PaymentId = &output.PaymentID
+	}
+	if output.TransactionID != "" {
+		resp.TransactionId = &output.TransactionID
+	}
+	if output.Failure != "" {
+		failure := api.CheckoutFailure(output.Failure)
+		resp.Failure = &failure
+		resp.FailureMessage = &output.FailureMessage
+	}
+	return resp
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksCacheControl lets verifiers cache the key set between key rotations
+const jwksCacheControl = "public, max-age=300"
+
+// JWKSProvider provides the public keys that verify access tokens
+type JWKSProvider interface {
+	GetJWKS(ctx context.Context) (auth.JWKS, error)
+}
+
+// JWKSHandler serves the JSON Web Key Set so services can verify tokens offline
+type JWKSHandler struct {
+	provider JWKSProvider
+}
+
+// NewJWKSHandler creates a new JWKSHandler
+func NewJWKSHandler(provider JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{
+		provider: provider,
+	}
+}
+
+// GetJWKS implements GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	jwks, err := h.provider.GetJWKS(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, api.Error{Error: "keys unavailable"})
+		return
+	}
+
+	c.Header("Cache-Control", jwksCacheControl)
+	c.JSON(http.StatusOK, jwks)
+}
@@ -52,17 +52,68 @@ func toUserResponse(output appuser.UserOutput) api.User {
 
 // toRegisterResponse converts application AuthOutput to OpenAPI RegisterResponse
 func toRegisterResponse(output appuser.AuthOutput) api.RegisterResponse {
-	return api.RegisterResponse{
-		User:         toUserResponse(output.User),
-		AccessToken:  output.AccessToken,
-		RefreshToken: output.RefreshToken,
+	resp := api.RegisterResponse{
+		User:          toUserResponse(output.User),
+		EmailVerified: output.EmailVerified,
+	}
+	// No tokens are issued until the email is verified when that is required for login
+	if output.AccessToken != "" {
+		resp.AccessToken = &output.AccessToken
+		resp.RefreshToken = &output.RefreshToken
 	}
+	return resp
 }
 
 // toLoginResponse converts application AuthOutput to OpenAPI LoginResponse
 func toLoginResponse(output appuser.AuthOutput) api.LoginResponse {
-	return api.LoginResponse{
-		User:         toUserResponse(output.User),
+	resp := api.LoginResponse{
+		User:          toUserResponse(output.User),
+		EmailVerified: output.EmailVerified,
+		MfaRequired:   output.MFARequired,
+	}
+	if output.MFARequired {
+		resp.MfaToken = &output.MFAToken
+	} else {
+		resp.AccessToken = &output.AccessToken
+		resp.RefreshToken = &output.RefreshToken
+	}
+	return resp
+}
+
+// toVerifyMFAInput converts OpenAPI VerifyMFARequest to application DTO
+func toVerifyMFAInput(req api.VerifyMFARequest) appuser.VerifyMFAInput {
+	return appuser.VerifyMFAInput{
+		MFAToken: req.MfaToken,
+		Code:     req.Code,
+	}
+}
+
+// toMFAEnrollResponse converts application MFAEnrollmentOutput to OpenAPI MFAEnrollResponse
+func toMFAEnrollResponse(output appuser.MFAEnrollmentOutput) api.MFAEnrollResponse {
+	return api.MFAEnrollResponse{
+		Secret:     output.Secret,
+		OtpauthUri: output.OTPAuthURI,
+	}
+}
+
+// toRecoveryCodesResponse converts application RecoveryCodesOutput to OpenAPI RecoveryCodesResponse
+func toRecoveryCodesResponse(output appuser.RecoveryCodesOutput) api.RecoveryCodesResponse {
+	return api.RecoveryCodesResponse{
+		RecoveryCodes: output.RecoveryCodes,
+	}
+}
+
+// toChangePasswordInput converts OpenAPI ChangePasswordRequest to application ChangePasswordInput
+func toChangePasswordInput(req api.ChangePasswordRequest) appuser.ChangePasswordInput {
+	return appuser.ChangePasswordInput{
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	}
+}
+
+// toRefreshTokenResponse converts application TokensOutput to OpenAPI RefreshTokenResponse
+func toRefreshTokenResponse(output appuser.TokensOutput) api.RefreshTokenResponse {
+	return api.RefreshTokenResponse{
 		AccessToken:  output.AccessToken,
 		RefreshToken: output.RefreshToken,
 	}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyMFA implements POST /auth/mfa/verify
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var req api.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.userService.VerifyMFA(c.Request.Context(), toVerifyMFAInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLoginResponse(output))
+}
+
+// EnrollMFA implements POST /auth/mfa/enroll
+func (h *UserHandler) EnrollMFA(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.userService.EnrollMFA(c.Request.Context(), principal.UserID())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMFAEnrollResponse(output))
+}
+
+// ConfirmMFA implements POST /auth/mfa/confirm
+func (h *UserHandler) ConfirmMFA(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.userService.ConfirmMFA(c.Request.Context(), principal.UserID(), req.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecoveryCodesResponse(output))
+}
+
+// GenerateRecoveryCodes implements POST /auth/mfa/recovery-codes
+func (h *UserHandler) GenerateRecoveryCodes(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.userService.GenerateRecoveryCodes(c.Request.Context(), principal.UserID(), req.Code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRecoveryCodesResponse(output))
+}
+
+// DisableMFA implements POST /auth/mfa/disable
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	if err := h.userService.DisableMFA(c.Request.Context(), principal.UserID(), req.Code); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse{Success: true})
+}
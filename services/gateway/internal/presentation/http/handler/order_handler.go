@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	apporder "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/order"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler handles HTTP requests for order operations.
+// Every route requires an access token; orders are placed for the caller.
+type OrderHandler struct {
+	orderService *apporder.Service
+}
+
+// NewOrderHandler creates a new OrderHandler
+func NewOrderHandler(orderService *apporder.Service) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// CreateOrder implements POST /orders
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.orderService.CreateOrder(c.Request.Context(), principal, toCreateOrderInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toOrderResponse(output))
+}
+
+// ListMyOrders implements GET /orders
+func (h *OrderHandler) ListMyOrders(c *gin.Context, params api.ListMyOrdersParams) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.orderService.ListMyOrders(c.Request.Context(), principal, toListOrdersInput(params))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderListResponse(output))
+}
+
+// GetOrder implements GET /orders/{id}
+func (h *OrderHandler) GetOrder(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.orderService.GetOrder(c.Request.Context(), principal, id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderResponse(output))
+}
+
+// CancelOrder implements POST /orders/{id}/cancel
+func (h *OrderHandler) CancelOrder(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	var reason string
+	if req.Reason != nil {
+		reason = *req.Reason
+	}
+
+	output, err := h.orderService.CancelOrder(c.Request.Context(), principal, id, reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderResponse(output))
+}
+
+// ChangeOrderAddress implements PUT /orders/{id}/address
+func (h *OrderHandler) ChangeOrderAddress(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.ChangeOrderAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.orderService.ChangeAddress(c.Request.Context(), principal, id, toChangeAddressInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderResponse(output))
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	apporder "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/order"
+)
+
+// toCreateOrderInput converts OpenAPI CreateOrderRequest to application CreateOrderInput
+func toCreateOrderInput(req api.CreateOrderRequest) apporder.CreateOrderInput {
+	input := apporder.CreateOrderInput{
+		Items:           make([]apporder.OrderItemInput, 0, len(req.Items)),
+		ShippingAddress: toAddressInput(req.ShippingAddress),
+		BillingAddress:  toOptionalAddressInput(req.BillingAddress),
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, apporder.OrderItemInput{
+			ProductID: item.ProductId,
+			Quantity:  item.Quantity,
+		})
+	}
+	return input
+}
+
+// toChangeAddressInput converts OpenAPI ChangeOrderAddressRequest to application ChangeAddressInput
+func toChangeAddressInput(req api.ChangeOrderAddressRequest) apporder.ChangeAddressInput {
+	return apporder.ChangeAddressInput{
+		ShippingAddress: toAddressInput(req.ShippingAddress),
+		BillingAddress:  toOptionalAddressInput(req.BillingAddress),
+	}
+}
+
+// toOptionalAddressInput converts an optional OpenAPI Address to application AddressInput
+func toOptionalAddressInput(a *api.Address) *apporder.AddressInput {
+	if a == nil {
+		return nil
+	}
+	input := toAddressInput(*a)
+	return &input
+}
+
+// toAddressInput converts OpenAPI Address to application AddressInput
+func toAddressInput(a api.Address) apporder.AddressInput {
+	input := apporder.AddressInput{
+		PostalCode:   a.PostalCode,
+		Prefecture:   a.Prefecture,
+		City:         a.City,
+		AddressLine1: a.AddressLine1,
+	}
+	if a.AddressLine2 != nil {
+		input.AddressLine2 = *a.AddressLine2
+	}
+	if a.PhoneNumber != nil {
+		input.PhoneNumber = *a.PhoneNumber
+	}
+	return input
+}
+
+// toListOrdersInput converts OpenAPI ListMyOrdersParams to application ListOrdersInput
+func toListOrdersInput(params api.ListMyOrdersParams) apporder.ListOrdersInput {
+	input := apporder.ListOrdersInput{}
+	if params.Status != nil {
+		input.Status = string(*params.Status)
+	}
+	if params.Page != nil {
+		input.Page = *params.Page
+	}
+	if params.PageSize != nil {
+		input.PageSize = *params.PageSize
+	}
+	return input
+}
+
+// toOrderResponse converts application OrderOutput to OpenAPI Order
+func toOrderResponse(output apporder.OrderOutput) api.Order {
+	resp := api.Order{
+		Id:              output.ID,
+		UserId:          output.UserID,
+		Items:           make([]api.OrderItem, 0, len(output.Items)),
+		TotalAmount:     toOrderMoney(output.TotalAmount),
+		Status:          api.OrderStatus(output.Status),
+		ShippingAddress: toAddressResponse(output.ShippingAddress),
+		CreatedAt:       output.CreatedAt,
+		UpdatedAt:       output.UpdatedAt,
+	}
+	if output.BillingAddress != nil {
+		billingAddress := toAddressResponse(*output.BillingAddress)
+		resp.BillingAddress = &billingAddress
+	}
+	if output.PaymentID != "" {
+		resp.PaymentId = &output.PaymentID
+	}
+	for _, item := range output.Items {
+		resp.Items = append(resp.Items, api.OrderItem{
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   toOrderMoney(item.UnitPrice),
+			Subtotal:    toOrderMoney(item.Subtotal),
+		})
+	}
+	if output.History != nil {
+		history := make([]api.OrderStatusChange, 0, len(output.History))
+		for _, change := range output.History {
+			history = append(history, toStatusChangeResponse(change))
+		}
+		resp.History = &history
+	}
+	return resp
+}
+
+// toStatusChangeResponse converts application StatusChangeOutput to OpenAPI OrderStatusChange
+func toStatusChangeResponse(output apporder.StatusChangeOutput) api.OrderStatusChange {
+	resp := api.OrderStatusChange{
+		ToStatus:  api.OrderStatus(output.To),
+		ActorType: api.OrderStatusChangeActorType(output.ActorType),
+		ActorId:   output.ActorID,
+		ChangedAt: output.ChangedAt,
+	}
+	if output.From != "" {
+		from := api.OrderStatus(output.From)
+		resp.FromStatus = &from
+	}
+	if output.Reason != "" {
+		resp.Reason = &output.Reason
+	}
+	return resp
+}
+
+// toAddressResponse converts application AddressOutput to OpenAPI Address
+func toAddressResponse(output apporder.AddressOutput) api.Address {
+	resp := api.Address{
+		PostalCode:   output.PostalCode,
+		Prefecture:   output.Prefecture,
+		City:         output.City,
+		AddressLine1: output.AddressLine1,
+	}
+	if output.AddressLine2 != "" {
+		resp.AddressLine2 = &output.AddressLine2
+	}
+	if output.PhoneNumber != "" {
+		resp.PhoneNumber = &output.PhoneNumber
+	}
+	return resp
+}
+
+// toOrderMoney converts application MoneyOutput to OpenAPI Money
+func toOrderMoney(output apporder.MoneyOutput) api.Money {
+	return api.Money{
+		Amount:   output.Amount,
+		Currency: output.Currency,
+	}
+}
+
+// toOrderListResponse converts application OrderListOutput to OpenAPI OrderListResponse
+func toOrderListResponse(output apporder.OrderListOutput) api.OrderListResponse {
+	resp := api.OrderListResponse{
+		Orders: make([]api.Order, 0, len(output.Orders)),
+		Pagination: api.PaginationResponse{
+			TotalCount:  output.TotalCount,
+			TotalPages:  output.TotalPages,
+			CurrentPage: output.CurrentPage,
+		},
+	}
+	for _, o := range output.Orders {
+		resp.Orders = append(resp.Orders, toOrderResponse(o))
+	}
+	return resp
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	apppayment "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/payment"
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler handles HTTP requests for payment operations.
+// Every route requires an access token; refunds also require the admin role.
+type PaymentHandler struct {
+	paymentService *apppayment.Service
+}
+
+// NewPaymentHandler creates a new PaymentHandler
+func NewPaymentHandler(paymentService *apppayment.Service) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// CreatePayment implements POST /payments
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.CreatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.paymentService.CreatePayment(c.Request.Context(), principal, toCreatePaymentInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPaymentResponse(output))
+}
+
+// GetPayment implements GET /payments/{id}
+func (h *PaymentHandler) GetPayment(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.paymentService.GetPayment(c.Request.Context(), principal, id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentResponse(output))
+}
+
+// ProcessPayment implements POST /payments/{id}/process
+func (h *PaymentHandler) ProcessPayment(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.ProcessPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.paymentService.ProcessPayment(c.Request.Context(), principal, id, toProcessPaymentInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.ProcessPaymentResponse{
+		TransactionId: output.TransactionID,
+		Message:       output.Message,
+	})
+}
+
+// GetPaymentStatus implements GET /payments/{id}/status
+func (h *PaymentHandler) GetPaymentStatus(c *gin.Context, id string) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	output, err := h.paymentService.GetPaymentStatus(c.Request.Context(), principal, id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentStatusResponse(output))
+}
+
+// RefundPayment implements POST /payments/{id}/refund
+func (h *PaymentHandler) RefundPayment(c *gin.Context, id string) {
+	var req api.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.paymentService.RefundPayment(c.Request.Context(), id, toRefundPaymentInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.RefundPaymentResponse{
+		RefundId: output.RefundID,
+		Message:  output.Message,
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	apppayment "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/payment"
+)
+
+// toCreatePaymentInput converts OpenAPI CreatePaymentRequest to application CreatePaymentInput
+func toCreatePaymentInput(req api.CreatePaymentRequest) apppayment.CreatePaymentInput {
+	return apppayment.CreatePaymentInput{
+		OrderID: req.OrderId,
+		Method:  string(req.Method),
+	}
+}
+
+// toProcessPaymentInput converts OpenAPI ProcessPaymentRequest to application ProcessPaymentInput
+func toProcessPaymentInput(req api.ProcessPaymentRequest) apppayment.ProcessPaymentInput {
+	return apppayment.ProcessPaymentInput{
+		PaymentToken: req.PaymentToken,
+	}
+}
+
+// toRefundPaymentInput converts OpenAPI RefundPaymentRequest to application RefundPaymentInput
+func toRefundPaymentInput(req api.RefundPaymentRequest) apppayment.RefundPaymentInput {
+	input := apppayment.RefundPaymentInput{
+		Amount: req.Amount,
+	}
+	if req.Reason != nil {
+		input.Reason = *req.Reason
+	}
+	return input
+}
+
+// toPaymentResponse converts application PaymentOutput to OpenAPI Payment
+func toPaymentResponse(output apppayment.PaymentOutput) api.Payment {
+	resp := api.Payment{
+		Id:      output.ID,
+		OrderId: output.OrderID,
+		UserId:  output.UserID,
+		Amount: api.Money{
+			Amount:   output.Amount.Amount,
+			Currency: output.Amount.Currency,
+		},
+		Status:    api.PaymentStatus(output.Status),
+		Method:    api.PaymentMethod(output.Method),
+		CreatedAt: output.CreatedAt,
+		UpdatedAt: output.UpdatedAt,
+	}
+	if output.TransactionID != "" {
+		resp.TransactionId = &output.TransactionID
+	}
+	return resp
+}
+
+// toPaymentStatusResponse converts application PaymentStatusOutput to OpenAPI PaymentStatusResponse
+func toPaymentStatusResponse(output apppayment.PaymentStatusOutput) api.PaymentStatusResponse {
+	resp := api.PaymentStatusResponse{
+		Status: api.PaymentStatus(output.Status),
+	}
+	if output.TransactionID != "" {
+		resp.TransactionId = &output.TransactionID
+	}
+	return resp
+}
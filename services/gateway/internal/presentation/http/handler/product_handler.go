@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	appproduct "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/product"
+	"github.com/gin-gonic/gin"
+)
+
+// ProductHandler handles HTTP requests for product catalog operations
+type ProductHandler struct {
+	productService *appproduct.Service
+}
+
+// NewProductHandler creates a new ProductHandler
+func NewProductHandler(productService *appproduct.Service) *ProductHandler {
+	return &ProductHandler{
+		productService: productService,
+	}
+}
+
+// ListProducts implements GET /products
+func (h *ProductHandler) ListProducts(c *gin.Context, params api.ListProductsParams) {
+	output, err := h.productService.ListProducts(c.Request.Context(), toListProductsInput(params))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProductListResponse(output))
+}
+
+// GetProduct implements GET /products/{id}
+func (h *ProductHandler) GetProduct(c *gin.Context, id string) {
+	output, err := h.productService.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProductResponse(output))
+}
+
+// CreateProduct implements POST /products
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	var req api.CreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.productService.CreateProduct(c.Request.Context(), toCreateProductInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toProductResponse(output))
+}
+
+// UpdateProduct implements PUT /products/{id}
+func (h *ProductHandler) UpdateProduct(c *gin.Context, id string) {
+	var req api.UpdateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.productService.UpdateProduct(c.Request.Context(), id, toUpdateProductInput(req))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProductResponse(output))
+}
+
+// DeleteProduct implements DELETE /products/{id}
+func (h *ProductHandler) DeleteProduct(c *gin.Context, id string) {
+	if err := h.productService.DeleteProduct(c.Request.Context(), id); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse{Success: true})
+}
+
+// UpdateProductStock implements POST /products/{id}/stock
+func (h *ProductHandler) UpdateProductStock(c *gin.Context, id string) {
+	var req api.UpdateStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.productService.UpdateStock(c.Request.Context(), id, req.QuantityChange)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProductResponse(output))
+}
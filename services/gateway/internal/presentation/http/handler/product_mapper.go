@@ -0,0 +1,109 @@
+package handler
+
+import (
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	appproduct "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/product"
+)
+
+// toListProductsInput converts OpenAPI ListProductsParams to application ListProductsInput
+func toListProductsInput(params api.ListProductsParams) appproduct.ListProductsInput {
+	input := appproduct.ListProductsInput{}
+	if params.Page != nil {
+		input.Page = *params.Page
+	}
+	if params.PageSize != nil {
+		input.PageSize = *params.PageSize
+	}
+	if params.Category != nil {
+		input.Category = *params.Category
+	}
+	if params.Q != nil {
+		input.Search = *params.Q
+	}
+	return input
+}
+
+// toCreateProductInput converts OpenAPI CreateProductRequest to application CreateProductInput
+func toCreateProductInput(req api.CreateProductRequest) appproduct.CreateProductInput {
+	input := appproduct.CreateProductInput{
+		Name:  req.Name,
+		Price: toMoneyInput(req.Price),
+	}
+	if req.Description != nil {
+		input.Description = *req.Description
+	}
+	if req.StockQuantity != nil {
+		input.StockQuantity = *req.StockQuantity
+	}
+	if req.Category != nil {
+		input.Category = *req.Category
+	}
+	if req.ImageUrls != nil {
+		input.ImageURLs = *req.ImageUrls
+	}
+	if req.Sku != nil {
+		input.SKU = *req.Sku
+	}
+	return input
+}
+
+// toUpdateProductInput converts OpenAPI UpdateProductRequest to application UpdateProductInput
+func toUpdateProductInput(req api.UpdateProductRequest) appproduct.UpdateProductInput {
+	input := appproduct.UpdateProductInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Category:    req.Category,
+		ImageURLs:   req.ImageUrls,
+		SKU:         req.Sku,
+		IsActive:    req.IsActive,
+	}
+	if req.Price != nil {
+		price := toMoneyInput(*req.Price)
+		input.Price = &price
+	}
+	return input
+}
+
+// toMoneyInput converts OpenAPI Money to application MoneyInput
+func toMoneyInput(m api.Money) appproduct.MoneyInput {
+	return appproduct.MoneyInput{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+// toProductResponse converts application ProductOutput to OpenAPI Product
+func toProductResponse(output appproduct.ProductOutput) api.Product {
+	return api.Product{
+		Id:          output.ID,
+		Name:        output.Name,
+		Description: output.Description,
+		Price: api.Money{
+			Amount:   output.Price.Amount,
+			Currency: output.Price.Currency,
+		},
+		StockQuantity: output.StockQuantity,
+		Category:      output.Category,
+		ImageUrls:     output.ImageURLs,
+		Sku:           output.SKU,
+		IsActive:      output.IsActive,
+		CreatedAt:     output.CreatedAt,
+		UpdatedAt:     output.UpdatedAt,
+	}
+}
+
+// toProductListResponse converts application ProductListOutput to OpenAPI ProductListResponse
+func toProductListResponse(output appproduct.ProductListOutput) api.ProductListResponse {
+	resp := api.ProductListResponse{
+		Products: make([]api.Product, 0, len(output.Products)),
+		Pagination: api.PaginationResponse{
+			TotalCount:  output.TotalCount,
+			TotalPages:  output.TotalPages,
+			CurrentPage: output.CurrentPage,
+		},
+	}
+	for _, p := range output.Products {
+		resp.Products = append(resp.Products, toProductResponse(p))
+	}
+	return resp
+}
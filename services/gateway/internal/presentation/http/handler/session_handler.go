@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RefreshToken implements POST /auth/refresh
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req api.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	output, err := h.userService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRefreshTokenResponse(output))
+}
+
+// Logout implements POST /auth/logout
+func (h *UserHandler) Logout(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	accessToken, found := auth.AccessTokenFromContext(c.Request.Context())
+	if !found {
+		handleError(c, errors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.userService.Logout(c.Request.Context(), principal.UserID(), accessToken); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse{Success: true})
+}
+
+// ChangePassword implements PUT /auth/password
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var req api.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), principal.UserID(), toChangePasswordInput(req)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.SuccessResponse{Success: true})
+}
@@ -3,9 +3,11 @@ package handler
 import (
 	"net/http"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
 	appuser "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/user"
 	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
 	"github.com/gin-gonic/gin"
 )
 
@@ -64,8 +66,42 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetCurrentUser implements GET /users/me
+func (h *UserHandler) GetCurrentUser(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	h.GetUser(c, principal.UserID())
+}
+
+// UpdateCurrentUser implements PUT /users/me
+func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	h.UpdateUser(c, principal.UserID())
+}
+
+// DeleteCurrentUser implements DELETE /users/me
+func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	h.DeleteUser(c, principal.UserID())
+}
+
 // GetUser implements GET /users/{id}
 func (h *UserHandler) GetUser(c *gin.Context, id string) {
+	if !authorizeUser(c, id) {
+		return
+	}
+
 	output, err := h.userService.GetUser(c.Request.Context(), id)
 	if err != nil {
 		handleError(c, err)
@@ -78,6 +114,10 @@ func (h *UserHandler) GetUser(c *gin.Context, id string) {
 
 // UpdateUser implements PUT /users/{id}
 func (h *UserHandler) UpdateUser(c *gin.Context, id string) {
+	if !authorizeUser(c, id) {
+		return
+	}
+
 	var req api.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
@@ -98,6 +138,10 @@ func (h *UserHandler) UpdateUser(c *gin.Context, id string) {
 
 // DeleteUser implements DELETE /users/{id}
 func (h *UserHandler) DeleteUser(c *gin.Context, id string) {
+	if !authorizeUser(c, id) {
+		return
+	}
+
 	err := h.userService.DeleteUser(c.Request.Context(), id)
 	if err != nil {
 		handleError(c, err)
@@ -107,17 +151,49 @@ func (h *UserHandler) DeleteUser(c *gin.Context, id string) {
 	c.JSON(http.StatusOK, api.DeleteUserResponse{Success: true})
 }
 
+// currentPrincipal returns the principal authenticated by the Authorize
+// middleware and writes an error response if there is none
+func currentPrincipal(c *gin.Context) (*user.Principal, bool) {
+	principal, ok := user.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		handleError(c, errors.ErrUnauthorized)
+		return nil, false
+	}
+	return principal, true
+}
+
+// authorizeUser checks that the caller is the user with the given ID or an
+// admin, and writes an error response otherwise
+func authorizeUser(c *gin.Context, userID string) bool {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return false
+	}
+
+	if principal.UserID() != userID && !principal.HasRole(auth.RoleAdmin) {
+		handleError(c, errors.ErrForbidden)
+		return false
+	}
+	return true
+}
+
 // handleError maps domain errors to HTTP responses
 func handleError(c *gin.Context, err error) {
 	switch err {
 	case errors.ErrInvalidInput:
 		c.JSON(http.StatusBadRequest, api.Error{Error: err.Error()})
-	case errors.ErrUserNotFound:
+	case errors.ErrUserNotFound, errors.ErrNotFound:
 		c.JSON(http.StatusNotFound, api.Error{Error: err.Error()})
 	case errors.ErrUnauthorized:
 		c.JSON(http.StatusUnauthorized, api.Error{Error: err.Error()})
-	case errors.ErrEmailExists:
+	case errors.ErrEmailExists, errors.ErrConflict:
 		c.JSON(http.StatusConflict, api.Error{Error: err.Error()})
+	case errors.ErrForbidden, errors.ErrEmailNotVerified:
+		c.JSON(http.StatusForbidden, api.Error{Error: err.Error()})
+	case errors.ErrTooManyRequests:
+		c.JSON(http.StatusTooManyRequests, api.Error{Error: err.Error()})
+	case errors.ErrPaymentDeclined:
+		c.JSON(http.StatusPaymentRequired, api.Error{Error: err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, api.Error{Error: "internal server error"})
 	}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	api "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticator resolves the principal of an access token
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*user.Principal, error)
+}
+
+// Authorize enforces a permission table keyed by "METHOD /route/path", such
+// as "GET /api/v1/users/:id". Requests to listed routes must carry a bearer
+// token whose principal has one of the allowed roles; the principal is then
+// put in the request context, where handlers read it with
+// user.PrincipalFromContext, together with the token itself for
+// auth.UnaryClientInterceptor to forward to backend services.
+// Unlisted routes are public.
+func Authorize(authenticator Authenticator, permissions auth.Permissions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := c.Request.Method + " " + c.FullPath()
+		if !permissions.Requires(operation) {
+			c.Next()
+			return
+		}
+
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Error: "missing bearer token"})
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			if err == errors.ErrUnauthorized {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Error: err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Error: "internal server error"})
+			return
+		}
+
+		if !permissions.Allows(operation, principal.Roles()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, api.Error{Error: errors.ErrForbidden.Error()})
+			return
+		}
+
+		ctx := user.NewContext(c.Request.Context(), principal)
+		c.Request = c.Request.WithContext(auth.WithAccessToken(ctx, token))
+		c.Next()
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/Riku-KANO/kube-ec/pkg/clientip"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP stores the IP of the end user in the request context so gRPC calls
+// forward it to the services, which throttle failed logins per IP
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := clientip.NewContext(c.Request.Context(), c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -1,25 +1,101 @@
 package http
 
 import (
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	openapi "github.com/Riku-KANO/kube-ec/services/gateway/internal/api"
 	"github.com/Riku-KANO/kube-ec/services/gateway/internal/presentation/http/handler"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/presentation/http/middleware"
 	"github.com/gin-gonic/gin"
 )
 
+// permissions lists the routes that require an access token and the roles allowed to call them.
+// An empty list lets any authenticated user through.
+// Handlers check that users only access their own account unless they are admins.
+var permissions = auth.Permissions{
+	"POST /api/v1/auth/logout":  {},
+	"PUT /api/v1/auth/password": {},
+
+	"POST /api/v1/auth/mfa/enroll":         {},
+	"POST /api/v1/auth/mfa/confirm":        {},
+	"POST /api/v1/auth/mfa/recovery-codes": {},
+	"POST /api/v1/auth/mfa/disable":        {},
+
+	"GET /api/v1/users/me":     {},
+	"PUT /api/v1/users/me":     {},
+	"DELETE /api/v1/users/me":  {},
+	"GET /api/v1/users/:id":    {},
+	"PUT /api/v1/users/:id":    {},
+	"DELETE /api/v1/users/:id": {},
+
+	// The product service checks the roles again with the forwarded token
+	"POST /api/v1/products":           {auth.RoleStaff},
+	"PUT /api/v1/products/:id":        {auth.RoleStaff},
+	"DELETE /api/v1/products/:id":     {auth.RoleStaff},
+	"POST /api/v1/products/:id/stock": {auth.RoleStaff},
+
+	// Orders are placed for the caller; other users' orders are reserved for staff
+	"GET /api/v1/orders":             {},
+	"POST /api/v1/orders":            {},
+	"GET /api/v1/orders/:id":         {},
+	"POST /api/v1/orders/:id/cancel": {},
+	"PUT /api/v1/orders/:id/address": {},
+
+	// Checkouts place and pay for an order of the caller
+	"POST /api/v1/checkout":    {},
+	"GET /api/v1/checkout/:id": {},
+
+	// Payments are made by the owner of the order; refunds only by admins
+	"POST /api/v1/payments":             {},
+	"GET /api/v1/payments/:id":          {},
+	"POST /api/v1/payments/:id/process": {},
+	"GET /api/v1/payments/:id/status":   {},
+	"POST /api/v1/payments/:id/refund":  {auth.RoleAdmin},
+}
+
+// apiHandler serves the generated OpenAPI routes, which span the handlers of several resources
+type apiHandler struct {
+	*handler.UserHandler
+	*handler.ProductHandler
+	*handler.OrderHandler
+	*handler.PaymentHandler
+	*handler.CheckoutHandler
+}
+
 // SetupRouter configures HTTP routes
-func SetupRouter(userHandler *handler.UserHandler) *gin.Engine {
+func SetupRouter(
+	userHandler *handler.UserHandler,
+	productHandler *handler.ProductHandler,
+	orderHandler *handler.OrderHandler,
+	paymentHandler *handler.PaymentHandler,
+	checkoutHandler *handler.CheckoutHandler,
+	jwksHandler *handler.JWKSHandler,
+	authenticator middleware.Authenticator,
+) *gin.Engine {
 	r := gin.Default()
 
+	// Forward the client IP to the backend services
+	r.Use(middleware.ClientIP())
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Public keys for offline token verification
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// API v1
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.Authorize(authenticator, permissions))
 	{
 		// Register OpenAPI routes using generated handler wrapper
-		openapi.RegisterHandlers(v1, userHandler)
+		openapi.RegisterHandlers(v1, apiHandler{
+			UserHandler:     userHandler,
+			ProductHandler:  productHandler,
+			OrderHandler:    orderHandler,
+			PaymentHandler:  paymentHandler,
+			CheckoutHandler: checkoutHandler,
+		})
 	}
 
 	return r
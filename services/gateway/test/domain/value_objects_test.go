@@ -138,3 +138,18 @@ func TestNewAuthTokens(t *testing.T) {
 		t.Errorf("AuthTokens.RefreshToken = %v, want %v", tokens.RefreshToken, refreshToken)
 	}
 }
+
+func TestNewMFAChallenge(t *testing.T) {
+	tokens := user.NewMFAChallenge("mfa-token-789")
+
+	if !tokens.MFARequired() {
+		t.Error("AuthTokens.MFARequired() = false, want true")
+	}
+	if tokens.AccessToken != "" || tokens.RefreshToken != "" {
+		t.Error("MFA challenge should not carry access or refresh tokens")
+	}
+
+	if user.NewAuthTokens("access-token-123", "refresh-token-456").MFARequired() {
+		t.Error("AuthTokens.MFARequired() = true for issued tokens, want false")
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestUserEndpoints_Authorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "get without token", method: http.MethodGet, path: "/api/v1/users/alice", wantCode: http.StatusUnauthorized},
+		{name: "get with invalid token", method: http.MethodGet, path: "/api/v1/users/alice", token: "bogus", wantCode: http.StatusUnauthorized},
+		{name: "get own account", method: http.MethodGet, path: "/api/v1/users/alice", token: "alice-token", wantCode: http.StatusOK},
+		{name: "get another account", method: http.MethodGet, path: "/api/v1/users/bob", token: "alice-token", wantCode: http.StatusForbidden},
+		{name: "admin gets another account", method: http.MethodGet, path: "/api/v1/users/bob", token: "admin-token", wantCode: http.StatusOK},
+		{name: "update another account", method: http.MethodPut, path: "/api/v1/users/bob", token: "alice-token", body: `{"name":"Mallory"}`, wantCode: http.StatusForbidden},
+		{name: "update own account", method: http.MethodPut, path: "/api/v1/users/alice", token: "alice-token", body: `{"name":"Alice"}`, wantCode: http.StatusOK},
+		{name: "delete another account", method: http.MethodDelete, path: "/api/v1/users/bob", token: "alice-token", wantCode: http.StatusForbidden},
+		{name: "admin deletes another account", method: http.MethodDelete, path: "/api/v1/users/bob", token: "admin-token", wantCode: http.StatusOK},
+		{name: "current user without token", method: http.MethodGet, path: "/api/v1/users/me", wantCode: http.StatusUnauthorized},
+		{name: "enroll MFA without token", method: http.MethodPost, path: "/api/v1/auth/mfa/enroll", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newTestGateway(t)
+
+			w := gw.do(tt.method, tt.path, tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s status = %d, want %d, body = %s", tt.method, tt.path, w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCurrentUser(t *testing.T) {
+	gw := newTestGateway(t)
+
+	w := gw.do(http.MethodGet, "/api/v1/users/me", "bob-token", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /users/me status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.ID != "bob" {
+		t.Errorf("GET /users/me id = %q, want %q", body.ID, "bob")
+	}
+
+	w = gw.do(http.MethodPut, "/api/v1/users/me", "bob-token", `{"name":"Robert"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT /users/me status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := gw.users.users["bob"].Name(); got != "Robert" {
+		t.Errorf("name after PUT /users/me = %q, want %q", got, "Robert")
+	}
+
+	w = gw.do(http.MethodDelete, "/api/v1/users/me", "bob-token", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("DELETE /users/me status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if _, ok := gw.users.users["bob"]; ok {
+		t.Error("DELETE /users/me should delete the caller's account")
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// checkoutBody is the subset of the checkout response checked by the tests
+type checkoutBody struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	OrderID       string `json:"order_id"`
+	PaymentID     string `json:"payment_id"`
+	TransactionID string `json:"transaction_id"`
+	Failure       string `json:"failure"`
+}
+
+// checkoutRequest returns a checkout body for the given items JSON and payment token
+func checkoutRequest(items, paymentToken string) string {
+	return `{"items":` + items + `,"shipping_address":{"postal_code":"150-0001",` +
+		`"prefecture":"Tokyo","city":"Shibuya","address_line1":"1-1-1"},` +
+		`"payment_method":"credit_card","payment_token":"` + paymentToken + `"}`
+}
+
+func TestCheckout(t *testing.T) {
+	gw := newTestGateway(t)
+
+	w := gw.do(http.MethodPost, "/api/v1/checkout", "alice-token", checkoutRequest(`[{"product_id":"p1","quantity":2}]`, "tok_visa"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /checkout status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var checkout checkoutBody
+	if err := json.Unmarshal(w.Body.Bytes(), &checkout); err != nil {
+		t.Fatalf("failed to decode checkout: %v", err)
+	}
+	if checkout.Status != "completed" || checkout.OrderID == "" || checkout.PaymentID == "" || checkout.TransactionID == "" {
+		t.Errorf("checkout = %+v", checkout)
+	}
+
+	// The checkout is placed for the caller
+	req := gw.checkouts.requests[0]
+	if req.UserId != "alice" || req.PaymentToken != "tok_visa" || len(req.Items) != 1 || req.Items[0].Quantity != 2 {
+		t.Errorf("checkout request = %+v", req)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "no token", body: checkoutRequest(`[{"product_id":"p1","quantity":1}]`, "tok_visa"), wantCode: http.StatusUnauthorized},
+		{name: "unverified email", token: "carol-token", body: checkoutRequest(`[{"product_id":"p1","quantity":1}]`, "tok_visa"), wantCode: http.StatusForbidden},
+		{name: "no items", token: "alice-token", body: checkoutRequest(`[]`, "tok_visa"), wantCode: http.StatusBadRequest},
+		{name: "zero quantity", token: "alice-token", body: checkoutRequest(`[{"product_id":"p1","quantity":0}]`, "tok_visa"), wantCode: http.StatusBadRequest},
+		{name: "missing payment token", token: "alice-token", body: checkoutRequest(`[{"product_id":"p1","quantity":1}]`, ""), wantCode: http.StatusBadRequest},
+		{name: "out of stock", token: "alice-token", body: checkoutRequest(`[{"product_id":"sold-out","quantity":1}]`, "tok_visa"), wantCode: http.StatusConflict},
+		{name: "payment declined", token: "alice-token", body: checkoutRequest(`[{"product_id":"p1","quantity":1}]`, declinedPaymentToken), wantCode: http.StatusPaymentRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodPost, "/api/v1/checkout", tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("POST /checkout status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCheckout(t *testing.T) {
+	gw := newTestGateway(t)
+	w := gw.do(http.MethodPost, "/api/v1/checkout", "alice-token", checkoutRequest(`[{"product_id":"p1","quantity":1}]`, declinedPaymentToken))
+	if w.Code != http.StatusPaymentRequired {
+		t.Fatalf("POST /checkout status = %d, want %d", w.Code, http.StatusPaymentRequired)
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		id          string
+		wantCode    int
+		wantFailure string
+	}{
+		{name: "owner sees why it failed", token: "alice-token", id: "checkout-1", wantCode: http.StatusOK, wantFailure: "payment_declined"},
+		{name: "staff", token: "staff-token", id: "checkout-1", wantCode: http.StatusOK, wantFailure: "payment_declined"},
+		{name: "other user", token: "bob-token", id: "checkout-1", wantCode: http.StatusNotFound},
+		{name: "unknown checkout", token: "alice-token", id: "missing", wantCode: http.StatusNotFound},
+		{name: "no token", id: "checkout-1", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodGet, "/api/v1/checkout/"+tt.id, tt.token, "")
+			if w.Code != tt.wantCode {
+				t.Fatalf("GET /checkout/%s status = %d, want %d, body = %s", tt.id, w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var checkout checkoutBody
+			if err := json.Unmarshal(w.Body.Bytes(), &checkout); err != nil {
+				t.Fatalf("failed to decode checkout: %v", err)
+			}
+			if checkout.Status != "failed" || checkout.Failure != tt.wantFailure {
+				t.Errorf("checkout = %+v, want failed with %s", checkout, tt.wantFailure)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	checkoutpb "github.com/Riku-KANO/kube-ec/proto/checkout"
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// soldOutProductID is a product the fake checkout service has no stock of
+const soldOutProductID = "sold-out"
+
+// fakeCheckoutClient implements checkoutpb.CheckoutServiceClient in memory.
+// Like the checkout service, it reports failed checkouts with a FAILED status
+// and the reason instead of an error: declinedPaymentToken is declined and
+// soldOutProductID is out of stock.
+type fakeCheckoutClient struct {
+	checkouts map[string]*checkoutpb.CheckoutResponse
+	requests  []*checkoutpb.CheckoutRequest
+}
+
+func newFakeCheckoutClient() *fakeCheckoutClient {
+	return &fakeCheckoutClient{
+		checkouts: make(map[string]*checkoutpb.CheckoutResponse),
+	}
+}
+
+// authorize checks that the call carries a forwarded access token
+func (c *fakeCheckoutClient) authorize(ctx context.Context) error {
+	if _, ok := auth.AccessTokenFromContext(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	return nil
+}
+
+func (c *fakeCheckoutClient) Checkout(ctx context.Context, in *checkoutpb.CheckoutRequest, opts ...grpc.CallOption) (*checkoutpb.CheckoutResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+	c.requests = append(c.requests, in)
+
+	n := len(c.requests)
+	resp := &checkoutpb.CheckoutResponse{
+		Id:        fmt.Sprintf("checkout-%d", n),
+		UserId:    in.UserId,
+		Status:    checkoutpb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED,
+		OrderId:   fmt.Sprintf("order-%d", n),
+		PaymentId: fmt.Sprintf("payment-%d", n),
+		CreatedAt: &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt: &common.Timestamp{Seconds: 1704067200},
+	}
+
+	for _, item := range in.Items {
+		if item.ProductId == soldOutProductID {
+			resp.Status = checkoutpb.CheckoutStatus_CHECKOUT_STATUS_FAILED
+			resp.Failure = checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_OUT_OF_STOCK
+			resp.FailureMessage = "product sold-out is out of stock"
+		}
+	}
+	if resp.Failure == checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_UNSPECIFIED {
+		if in.PaymentToken == declinedPaymentToken {
+			resp.Status = checkoutpb.CheckoutStatus_CHECKOUT_STATUS_FAILED
+			resp.Failure = checkoutpb.CheckoutFailure_CHECKOUT_FAILURE_PAYMENT_DECLINED
+			resp.FailureMessage = "card declined"
+		} else {
+			resp.TransactionId = fmt.Sprintf("txn-%d", n)
+		}
+	}
+
+	c.checkouts[resp.Id] = resp
+	return resp, nil
+}
+
+func (c *fakeCheckoutClient) GetCheckout(ctx context.Context, in *checkoutpb.GetCheckoutRequest, opts ...grpc.CallOption) (*checkoutpb.CheckoutResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, ok := c.checkouts[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "checkout not found")
+	}
+	return resp, nil
+}
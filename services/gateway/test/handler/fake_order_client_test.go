@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeOrderClient implements orderpb.OrderServiceClient in memory.
+// Like the order service, it rejects calls that do not carry an access
+// token forwarded by the gateway and prices orders from the catalog.
+type fakeOrderClient struct {
+	catalog *fakeProductClient
+	orders  map[string]*orderpb.Order
+	created int
+	reasons map[string]string                       // order ID -> cancellation reason
+	history map[string][]*orderpb.OrderStatusChange // order ID -> status changes
+}
+
+func newFakeOrderClient(catalog *fakeProductClient) *fakeOrderClient {
+	return &fakeOrderClient{
+		catalog: catalog,
+		orders:  make(map[string]*orderpb.Order),
+		reasons: make(map[string]string),
+		history: make(map[string][]*orderpb.OrderStatusChange),
+	}
+}
+
+// recordChange appends a status change made by the owner of an order to its history
+func (c *fakeOrderClient) recordChange(order *orderpb.Order, from orderpb.OrderStatus, reason string) {
+	c.history[order.Id] = append(c.history[order.Id], &orderpb.OrderStatusChange{
+		FromStatus: from,
+		ToStatus:   order.Status,
+		ActorType:  "user",
+		ActorId:    order.UserId,
+		Reason:     reason,
+		ChangedAt:  &common.Timestamp{Seconds: 1704067200},
+	})
+}
+
+// addOrder stores an order of a user in the given status
+func (c *fakeOrderClient) addOrder(id, userID string, orderStatus orderpb.OrderStatus) {
+	c.orders[id] = &orderpb.Order{
+		Id:          id,
+		UserId:      userID,
+		TotalAmount: &common.Money{Currency: "JPY", Amount: 1000},
+		Status:      orderStatus,
+		CreatedAt:   &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt:   &common.Timestamp{Seconds: 1704067200},
+	}
+	c.recordChange(c.orders[id], orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, "")
+}
+
+// authorize checks that the call carries a forwarded access token
+func (c *fakeOrderClient) authorize(ctx context.Context) error {
+	if _, ok := auth.AccessTokenFromContext(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	return nil
+}
+
+func (c *fakeOrderClient) CreateOrder(ctx context.Context, in *orderpb.CreateOrderRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	var total *common.Money
+	items := make([]*orderpb.OrderItem, 0, len(in.Items))
+	for _, item := range in.Items {
+		product, ok := c.catalog.products[item.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "product %s not found", item.ProductId)
+		}
+		if !product.IsActive {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is not for sale", item.ProductId)
+		}
+		if total == nil {
+			total = &common.Money{Currency: product.Price.Currency}
+		}
+		if product.Price.Currency != total.Currency {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %s is priced in %s", item.ProductId, product.Price.Currency)
+		}
+
+		subtotal := product.Price.Amount * int64(item.Quantity)
+		total.Amount += subtotal
+		items = append(items, &orderpb.OrderItem{
+			ProductId:   product.Id,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   product.Price,
+			Subtotal:    &common.Money{Currency: product.Price.Currency, Amount: subtotal},
+		})
+	}
+
+	c.created++
+	order := &orderpb.Order{
+		Id:              fmt.Sprintf("order-%d", c.created),
+		UserId:          in.UserId,
+		Items:           items,
+		TotalAmount:     total,
+		Status:          orderpb.OrderStatus_ORDER_STATUS_PENDING,
+		ShippingAddress: in.ShippingAddress,
+		BillingAddress:  in.BillingAddress,
+		CreatedAt:       &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt:       &common.Timestamp{Seconds: 1704067200},
+	}
+	c.orders[order.Id] = order
+	c.recordChange(order, orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED, "")
+	return order, nil
+}
+
+func (c *fakeOrderClient) GetOrder(ctx context.Context, in *orderpb.GetOrderRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	order, ok := c.orders[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return order, nil
+}
+
+func (c *fakeOrderClient) ListOrders(ctx context.Context, in *orderpb.ListOrdersRequest, opts ...grpc.CallOption) (*orderpb.ListOrdersResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	var matched []*orderpb.Order
+	for _, order := range c.orders {
+		if order.UserId != in.UserId {
+			continue
+		}
+		if in.Status != orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED && order.Status != in.Status {
+			continue
+		}
+		matched = append(matched, order)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Id < matched[j].Id })
+
+	page, pageSize := in.Pagination.Page, in.Pagination.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	start := int((page - 1) * pageSize)
+	end := start + int(pageSize)
+	if start > len(matched) {
+		start = len(matched)
+	}
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return &orderpb.ListOrdersResponse{
+		Orders: matched[start:end],
+		Pagination: &common.PaginationResponse{
+			TotalCount:  int32(len(matched)),
+			TotalPages:  (int32(len(matched)) + pageSize - 1) / pageSize,
+			CurrentPage: page,
+		},
+	}, nil
+}
+
+func (c *fakeOrderClient) UpdateOrderStatus(ctx context.Context, in *orderpb.UpdateOrderStatusRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeOrderClient) CancelOrder(ctx context.Context, in *orderpb.CancelOrderRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	order, ok := c.orders[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	switch order.Status {
+	case orderpb.OrderStatus_ORDER_STATUS_SHIPPED,
+		orderpb.OrderStatus_ORDER_STATUS_DELIVERED,
+		orderpb.OrderStatus_ORDER_STATUS_CANCELLED:
+		return nil, status.Error(codes.FailedPrecondition, "order cannot be cancelled")
+	}
+
+	from := order.Status
+	order.Status = orderpb.OrderStatus_ORDER_STATUS_CANCELLED
+	c.reasons[in.Id] = in.Reason
+	c.recordChange(order, from, in.Reason)
+	return order, nil
+}
+
+func (c *fakeOrderClient) UpdateOrderAddress(ctx context.Context, in *orderpb.UpdateOrderAddressRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	order, ok := c.orders[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	if order.Status != orderpb.OrderStatus_ORDER_STATUS_PENDING && order.Status != orderpb.OrderStatus_ORDER_STATUS_CONFIRMED {
+		return nil, status.Error(codes.FailedPrecondition, "order addresses can no longer be changed")
+	}
+
+	order.ShippingAddress = in.ShippingAddress
+	order.BillingAddress = in.BillingAddress
+	return order, nil
+}
+
+func (c *fakeOrderClient) GetOrderHistory(ctx context.Context, in *orderpb.GetOrderHistoryRequest, opts ...grpc.CallOption) (*orderpb.GetOrderHistoryResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.orders[in.OrderId]; !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &orderpb.GetOrderHistoryResponse{Changes: c.history[in.OrderId]}, nil
+}
+
+func (c *fakeOrderClient) GetOrderByIdempotencyKey(ctx context.Context, in *orderpb.GetOrderByIdempotencyKeyRequest, opts ...grpc.CallOption) (*orderpb.Order, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
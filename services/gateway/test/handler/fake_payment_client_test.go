@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// declinedPaymentToken is a payment token the fake payment provider declines
+const declinedPaymentToken = "tok_declined"
+
+// fakePaymentClient implements paymentpb.PaymentServiceClient in memory.
+// Like the payment service, it reports declined charges and refunds of
+// payments that are not completed with Success=false instead of an error,
+// and rejects a payment for an order that already has a pending or
+// completed one.
+type fakePaymentClient struct {
+	payments map[string]*paymentpb.Payment
+	created  int
+	refunds  map[string]*paymentpb.RefundPaymentRequest // payment ID -> refund request
+}
+
+func newFakePaymentClient() *fakePaymentClient {
+	return &fakePaymentClient{
+		payments: make(map[string]*paymentpb.Payment),
+		refunds:  make(map[string]*paymentpb.RefundPaymentRequest),
+	}
+}
+
+// addPayment stores a payment of a user in the given status
+func (c *fakePaymentClient) addPayment(id, userID string, amount int64, paymentStatus paymentpb.PaymentStatus) {
+	c.payments[id] = &paymentpb.Payment{
+		Id:        id,
+		OrderId:   "order-of-" + id,
+		UserId:    userID,
+		Amount:    &common.Money{Currency: "JPY", Amount: amount},
+		Status:    paymentStatus,
+		Method:    paymentpb.PaymentMethod_PAYMENT_METHOD_CREDIT_CARD,
+		CreatedAt: &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt: &common.Timestamp{Seconds: 1704067200},
+	}
+}
+
+// authorize checks that the call carries a forwarded access token
+func (c *fakePaymentClient) authorize(ctx context.Context) error {
+	if _, ok := auth.AccessTokenFromContext(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	return nil
+}
+
+func (c *fakePaymentClient) CreatePayment(ctx context.Context, in *paymentpb.CreatePaymentRequest, opts ...grpc.CallOption) (*paymentpb.Payment, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, p := range c.payments {
+		if p.OrderId == in.OrderId && isActivePayment(p.Status) {
+			return nil, status.Error(codes.FailedPrecondition, "the order already has a pending or completed payment")
+		}
+	}
+
+	c.created++
+	payment := &paymentpb.Payment{
+		Id:        fmt.Sprintf("payment-%d", c.created),
+		OrderId:   in.OrderId,
+		UserId:    in.UserId,
+		Amount:    in.Amount,
+		Status:    paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING,
+		Method:    in.Method,
+		CreatedAt: &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt: &common.Timestamp{Seconds: 1704067200},
+	}
+	c.payments[payment.Id] = payment
+	return payment, nil
+}
+
+func (c *fakePaymentClient) GetPayment(ctx context.Context, in *paymentpb.GetPaymentRequest, opts ...grpc.CallOption) (*paymentpb.Payment, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	payment, ok := c.payments[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "payment not found")
+	}
+	return payment, nil
+}
+
+func (c *fakePaymentClient) ProcessPayment(ctx context.Context, in *paymentpb.ProcessPaymentRequest, opts ...grpc.CallOption) (*paymentpb.ProcessPaymentResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	payment, ok := c.payments[in.PaymentId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "payment not found")
+	}
+	if in.PaymentToken == declinedPaymentToken {
+		payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_FAILED
+		return &paymentpb.ProcessPaymentResponse{Success: false, Message: "card declined"}, nil
+	}
+
+	payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED
+	payment.TransactionId = "txn_" + payment.Id
+	return &paymentpb.ProcessPaymentResponse{
+		Success:       true,
+		TransactionId: payment.TransactionId,
+		Message:       "payment processed successfully",
+	}, nil
+}
+
+func (c *fakePaymentClient) RefundPayment(ctx context.Context, in *paymentpb.RefundPaymentRequest, opts ...grpc.CallOption) (*paymentpb.RefundPaymentResponse, error) {
+	if err := c.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	payment, ok := c.payments[in.PaymentId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "payment not found")
+	}
+	if payment.Status != paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED {
+		return &paymentpb.RefundPaymentResponse{Success: false, Message: "payment is not completed, cannot refund"}, nil
+	}
+
+	payment.Status = paymentpb.PaymentStatus_PAYMENT_STATUS_REFUNDED
+	c.refunds[in.PaymentId] = in
+	return &paymentpb.RefundPaymentResponse{
+		Success:  true,
+		RefundId: "rfnd_" + payment.Id,
+		Message:  "refund processed successfully",
+	}, nil
+}
+
+func (c *fakePaymentClient) GetPaymentStatus(ctx context.Context, in *paymentpb.GetPaymentStatusRequest, opts ...grpc.CallOption) (*paymentpb.GetPaymentStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+// isActivePayment reports whether a payment blocks another payment for its order
+func isActivePayment(s paymentpb.PaymentStatus) bool {
+	switch s {
+	case paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING,
+		paymentpb.PaymentStatus_PAYMENT_STATUS_PROCESSING,
+		paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED:
+		return true
+	}
+	return false
+}
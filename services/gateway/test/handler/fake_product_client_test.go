@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/proto/common"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeProductClient implements productpb.ProductServiceClient in memory.
+// Like the product service, it rejects writes that do not carry an access
+// token forwarded by the gateway.
+type fakeProductClient struct {
+	products    map[string]*productpb.Product
+	created     int
+	writeTokens []string // access tokens forwarded with writes
+}
+
+func newFakeProductClient() *fakeProductClient {
+	return &fakeProductClient{products: make(map[string]*productpb.Product)}
+}
+
+// addProduct stores an active product priced in JPY
+func (c *fakeProductClient) addProduct(id, name, category string, price int64, stock int32) {
+	c.products[id] = &productpb.Product{
+		Id:            id,
+		Name:          name,
+		Price:         &common.Money{Currency: "JPY", Amount: price},
+		StockQuantity: stock,
+		Category:      category,
+		IsActive:      true,
+		CreatedAt:     &common.Timestamp{Seconds: 1704067200},
+		UpdatedAt:     &common.Timestamp{Seconds: 1704067200},
+	}
+}
+
+// authorizeWrite records the forwarded access token of a write
+func (c *fakeProductClient) authorizeWrite(ctx context.Context) error {
+	token, ok := auth.AccessTokenFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	c.writeTokens = append(c.writeTokens, token)
+	return nil
+}
+
+func (c *fakeProductClient) CreateProduct(ctx context.Context, in *productpb.CreateProductRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	if err := c.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	c.created++
+	product := &productpb.Product{
+		Id:            fmt.Sprintf("product-%d", c.created),
+		Name:          in.Name,
+		Description:   in.Description,
+		Price:         in.Price,
+		StockQuantity: in.StockQuantity,
+		Category:      in.Category,
+		ImageUrls:     in.ImageUrls,
+		Sku:           in.Sku,
+		IsActive:      true,
+	}
+	c.products[product.Id] = product
+	return product, nil
+}
+
+func (c *fakeProductClient) GetProduct(ctx context.Context, in *productpb.GetProductRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	product, ok := c.products[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	return product, nil
+}
+
+func (c *fakeProductClient) ListProducts(ctx context.Context, in *productpb.ListProductsRequest, opts ...grpc.CallOption) (*productpb.ListProductsResponse, error) {
+	page, pageSize := in.Pagination.Page, in.Pagination.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	var matched []*productpb.Product
+	for _, product := range c.products {
+		if in.Category != "" && product.Category != in.Category {
+			continue
+		}
+		if in.SearchQuery != "" && !strings.Contains(product.Name, in.SearchQuery) {
+			continue
+		}
+		matched = append(matched, product)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Id < matched[j].Id })
+
+	total := int32(len(matched))
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	return &productpb.ListProductsResponse{
+		Products: matched[start:end],
+		Pagination: &common.PaginationResponse{
+			TotalCount:  total,
+			TotalPages:  (total + pageSize - 1) / pageSize,
+			CurrentPage: page,
+		},
+	}, nil
+}
+
+func (c *fakeProductClient) UpdateProduct(ctx context.Context, in *productpb.UpdateProductRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	if err := c.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	product, ok := c.products[in.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	product.Name = in.Name
+	product.Description = in.Description
+	product.Price = in.Price
+	product.Category = in.Category
+	product.ImageUrls = in.ImageUrls
+	product.Sku = in.Sku
+	product.IsActive = in.IsActive
+	return product, nil
+}
+
+func (c *fakeProductClient) DeleteProduct(ctx context.Context, in *productpb.DeleteProductRequest, opts ...grpc.CallOption) (*productpb.DeleteProductResponse, error) {
+	if err := c.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.products[in.Id]; !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	delete(c.products, in.Id)
+	return &productpb.DeleteProductResponse{Success: true}, nil
+}
+
+func (c *fakeProductClient) UpdateStock(ctx context.Context, in *productpb.UpdateStockRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	if err := c.authorizeWrite(ctx); err != nil {
+		return nil, err
+	}
+
+	product, ok := c.products[in.ProductId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if product.StockQuantity+in.QuantityChange < 0 {
+		return nil, status.Error(codes.FailedPrecondition, "insufficient stock")
+	}
+	product.StockQuantity += in.QuantityChange
+	return product, nil
+}
+
+func (c *fakeProductClient) CheckStock(ctx context.Context, in *productpb.CheckStockRequest, opts ...grpc.CallOption) (*productpb.CheckStockResponse, error) {
+	product, ok := c.products[in.ProductId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	return &productpb.CheckStockResponse{
+		Available:    product.StockQuantity >= in.RequiredQuantity,
+		CurrentStock: product.StockQuantity,
+	}, nil
+}
+
+func (c *fakeProductClient) ReserveStock(ctx context.Context, in *productpb.ReserveStockRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) CommitReservation(ctx context.Context, in *productpb.CommitReservationRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) ReleaseReservation(ctx context.Context, in *productpb.ReleaseReservationRequest, opts ...grpc.CallOption) (*productpb.Reservation, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) ListInventoryMovements(ctx context.Context, in *productpb.ListInventoryMovementsRequest, opts ...grpc.CallOption) (*productpb.ListInventoryMovementsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) AddProductImage(ctx context.Context, in *productpb.AddProductImageRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) ReorderProductImages(ctx context.Context, in *productpb.ReorderProductImagesRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) RemoveProductImage(ctx context.Context, in *productpb.RemoveProductImageRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) GetProductBySku(ctx context.Context, in *productpb.GetProductBySkuRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) BatchGetProductsBySku(ctx context.Context, in *productpb.BatchGetProductsBySkuRequest, opts ...grpc.CallOption) (*productpb.BatchGetProductsBySkuResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
+
+func (c *fakeProductClient) SearchProducts(ctx context.Context, in *productpb.SearchProductsRequest, opts ...grpc.CallOption) (*productpb.SearchProductsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not used by the gateway")
+}
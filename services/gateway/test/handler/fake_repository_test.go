@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/errors"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/domain/user"
+)
+
+// fakeAuthRepository implements user.AuthRepository with fixed tokens and passwords
+type fakeAuthRepository struct {
+	principals    map[string]*user.Principal // access token -> principal
+	refreshTokens map[string]string          // refresh token -> access token it renews
+	passwords     map[string]string          // user ID -> password
+	issued        int
+}
+
+func newFakeAuthRepository() *fakeAuthRepository {
+	return &fakeAuthRepository{
+		principals:    make(map[string]*user.Principal),
+		refreshTokens: make(map[string]string),
+		passwords:     make(map[string]string),
+	}
+}
+
+// addToken makes an access token valid for the user with the given roles.
+// The user's password is "password123" and "<token>-refresh" renews the token.
+func (r *fakeAuthRepository) addToken(token, userID string, roles ...string) {
+	r.principals[token] = user.NewPrincipal(userID, userID+"@example.com", true, roles)
+	r.refreshTokens[token+"-refresh"] = token
+	r.passwords[userID] = "password123"
+}
+
+// addUnverifiedToken is addToken for a user who has not verified their email
+func (r *fakeAuthRepository) addUnverifiedToken(token, userID string, roles ...string) {
+	r.addToken(token, userID, roles...)
+	r.principals[token] = user.NewPrincipal(userID, userID+"@example.com", false, roles)
+}
+
+// revokeUser invalidates every token of a user
+func (r *fakeAuthRepository) revokeUser(userID string) {
+	for token, principal := range r.principals {
+		if principal.UserID() == userID {
+			delete(r.principals, token)
+		}
+	}
+	for refreshToken, token := range r.refreshTokens {
+		if _, ok := r.principals[token]; !ok {
+			delete(r.refreshTokens, refreshToken)
+		}
+	}
+}
+
+func (r *fakeAuthRepository) Register(ctx context.Context, email user.Email, password string, name string, phoneNumber *user.PhoneNumber) (*user.User, user.AuthTokens, error) {
+	return nil, user.AuthTokens{}, errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) Login(ctx context.Context, email user.Email, password string) (*user.User, user.AuthTokens, error) {
+	return nil, user.AuthTokens{}, errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) VerifyToken(ctx context.Context, token string) (*user.Principal, error) {
+	principal, ok := r.principals[token]
+	if !ok {
+		return nil, errors.ErrUnauthorized
+	}
+	return principal, nil
+}
+
+func (r *fakeAuthRepository) RefreshToken(ctx context.Context, refreshToken string) (user.AuthTokens, error) {
+	token, ok := r.refreshTokens[refreshToken]
+	if !ok {
+		return user.AuthTokens{}, errors.ErrUnauthorized
+	}
+	principal, ok := r.principals[token]
+	if !ok {
+		return user.AuthTokens{}, errors.ErrUnauthorized
+	}
+
+	// Refresh tokens are single-use
+	delete(r.refreshTokens, refreshToken)
+
+	r.issued++
+	newToken := fmt.Sprintf("%s-%d", principal.UserID(), r.issued)
+	r.principals[newToken] = principal
+	r.refreshTokens[newToken+"-refresh"] = newToken
+	return user.NewAuthTokens(newToken, newToken+"-refresh"), nil
+}
+
+func (r *fakeAuthRepository) Logout(ctx context.Context, userID string, accessToken string) error {
+	principal, ok := r.principals[accessToken]
+	if !ok {
+		return errors.ErrUnauthorized
+	}
+	if principal.UserID() != userID {
+		return errors.ErrForbidden
+	}
+
+	delete(r.principals, accessToken)
+	for refreshToken, token := range r.refreshTokens {
+		if token == accessToken {
+			delete(r.refreshTokens, refreshToken)
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuthRepository) ChangePassword(ctx context.Context, userID string, oldPassword string, newPassword string) error {
+	password, ok := r.passwords[userID]
+	if !ok {
+		return errors.ErrNotFound
+	}
+	if password != oldPassword {
+		return errors.ErrUnauthorized
+	}
+
+	r.passwords[userID] = newPassword
+	r.revokeUser(userID)
+	return nil
+}
+
+func (r *fakeAuthRepository) VerifyMFA(ctx context.Context, mfaToken string, code string) (*user.User, user.AuthTokens, error) {
+	return nil, user.AuthTokens{}, errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) EnrollMFA(ctx context.Context, userID string) (user.MFAEnrollment, error) {
+	return user.MFAEnrollment{Secret: "SECRET-" + userID}, nil
+}
+
+func (r *fakeAuthRepository) ConfirmMFA(ctx context.Context, userID string, code string) ([]string, error) {
+	return nil, errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) GenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	return nil, errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) DisableMFA(ctx context.Context, userID string, code string) error {
+	return errors.ErrInternalError
+}
+
+func (r *fakeAuthRepository) GetJWKS(ctx context.Context) (auth.JWKS, error) {
+	return auth.JWKS{Keys: []auth.JWK{}}, nil
+}
+
+// fakeUserRepository implements user.UserRepository in memory
+type fakeUserRepository struct {
+	users map[string]*user.User
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{users: make(map[string]*user.User)}
+}
+
+// addUser stores a user with the given ID
+func (r *fakeUserRepository) addUser(id string) {
+	email, _ := user.NewEmail(id + "@example.com")
+	now := time.Now()
+	r.users[id] = user.NewUser(id, email, "User "+id, nil, now, now)
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, errors.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, id string, name string, phoneNumber *user.PhoneNumber) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, errors.ErrUserNotFound
+	}
+	u.UpdateProfile(name, phoneNumber)
+	return u, nil
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id string) error {
+	if _, ok := r.users[id]; !ok {
+		return errors.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	appcheckout "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/checkout"
+	apporder "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/order"
+	apppayment "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/payment"
+	appproduct "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/product"
+	appuser "github.com/Riku-KANO/kube-ec/services/gateway/internal/application/user"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/infrastructure/grpc"
+	httpserver "github.com/Riku-KANO/kube-ec/services/gateway/internal/presentation/http"
+	"github.com/Riku-KANO/kube-ec/services/gateway/internal/presentation/http/handler"
+	"github.com/gin-gonic/gin"
+)
+
+// testGateway is the gateway router wired to fake backends
+type testGateway struct {
+	router    *gin.Engine
+	users     *fakeUserRepository
+	products  *fakeProductClient
+	orders    *fakeOrderClient
+	payments  *fakePaymentClient
+	checkouts *fakeCheckoutClient
+}
+
+// newTestGateway builds the gateway router over fake backends.
+// The tokens "alice-token" and "bob-token" belong to customers alice and bob,
+// "staff-token" to a staff member and "admin-token" to an admin.
+// "carol-token" belongs to a customer who has not verified their email, which
+// the gateway requires before orders are placed.
+func newTestGateway(t *testing.T) *testGateway {
+	t.Helper()
+
+	authRepo := newFakeAuthRepository()
+	authRepo.addToken("alice-token", "alice", auth.RoleCustomer)
+	authRepo.addToken("bob-token", "bob", auth.RoleCustomer)
+	authRepo.addToken("staff-token", "staff", auth.RoleCustomer, auth.RoleStaff)
+	authRepo.addToken("admin-token", "admin", auth.RoleCustomer, auth.RoleAdmin)
+	authRepo.addUnverifiedToken("carol-token", "carol", auth.RoleCustomer)
+
+	userRepo := newFakeUserRepository()
+	userRepo.addUser("alice")
+	userRepo.addUser("bob")
+	userRepo.addUser("staff")
+	userRepo.addUser("admin")
+
+	productClient := newFakeProductClient()
+	orderClient := newFakeOrderClient(productClient)
+	paymentClient := newFakePaymentClient()
+	checkoutClient := newFakeCheckoutClient()
+
+	userService := appuser.NewService(authRepo, userRepo)
+	productRepo := grpc.NewProductRepository(productClient)
+	productService := appproduct.NewService(productRepo)
+	orderRepo := grpc.NewOrderRepository(orderClient)
+	orderService := apporder.NewService(orderRepo, auth.EmailVerificationRequiredForOrders)
+	paymentService := apppayment.NewService(grpc.NewPaymentRepository(paymentClient), orderRepo)
+	checkoutService := appcheckout.NewService(grpc.NewCheckoutRepository(checkoutClient), auth.EmailVerificationRequiredForOrders)
+	router := httpserver.SetupRouter(
+		handler.NewUserHandler(userService),
+		handler.NewProductHandler(productService),
+		handler.NewOrderHandler(orderService),
+		handler.NewPaymentHandler(paymentService),
+		handler.NewCheckoutHandler(checkoutService),
+		handler.NewJWKSHandler(authRepo),
+		userService,
+	)
+
+	return &testGateway{
+		router:    router,
+		users:     userRepo,
+		products:  productClient,
+		orders:    orderClient,
+		payments:  paymentClient,
+		checkouts: checkoutClient,
+	}
+}
+
+// do sends a request with an optional bearer token and JSON body
+func (g *testGateway) do(method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	g.router.ServeHTTP(w, req)
+	return w
+}
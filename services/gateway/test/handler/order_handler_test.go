@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+)
+
+// orderBody is the subset of the order response checked by the tests
+type orderBody struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+	Items  []struct {
+		ProductID   string `json:"product_id"`
+		ProductName string `json:"product_name"`
+		Quantity    int32  `json:"quantity"`
+		Subtotal    struct {
+			Amount int64 `json:"amount"`
+		} `json:"subtotal"`
+	} `json:"items"`
+	TotalAmount struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"total_amount"`
+	ShippingAddress addressBody  `json:"shipping_address"`
+	BillingAddress  *addressBody `json:"billing_address"`
+}
+
+type addressBody struct {
+	PostalCode string `json:"postal_code"`
+	Prefecture string `json:"prefecture"`
+}
+
+func decodeOrder(t *testing.T, body []byte) orderBody {
+	t.Helper()
+
+	var order orderBody
+	if err := json.Unmarshal(body, &order); err != nil {
+		t.Fatalf("failed to decode order: %v", err)
+	}
+	return order
+}
+
+// orderRequest returns a create order body for the given items JSON
+func orderRequest(items string) string {
+	return `{"items":` + items + `,"shipping_address":{"postal_code":"150-0001",` +
+		`"prefecture":"Tokyo","city":"Shibuya","address_line1":"1-1-1"}}`
+}
+
+func TestCreateOrder(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+	gw.products.addProduct("p2", "Red Mug", "kitchen", 800, 5)
+
+	// Prices come from the catalog and a user id in the body is ignored
+	body := `{"user_id":"bob","items":[{"product_id":"p1","quantity":2},{"product_id":"p2","quantity":1}],` +
+		`"shipping_address":{"postal_code":"150-0001","prefecture":"Tokyo","city":"Shibuya","address_line1":"1-1-1"}}`
+	w := gw.do(http.MethodPost, "/api/v1/orders", "alice-token", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /orders status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	order := decodeOrder(t, w.Body.Bytes())
+	if order.UserID != "alice" {
+		t.Errorf("order user_id = %q, want %q", order.UserID, "alice")
+	}
+	if order.Status != "pending" {
+		t.Errorf("order status = %q, want %q", order.Status, "pending")
+	}
+	if len(order.Items) != 2 || order.Items[0].ProductName != "Blue Mug" || order.Items[0].Subtotal.Amount != 2400 {
+		t.Errorf("order items = %+v", order.Items)
+	}
+	if order.TotalAmount.Amount != 3200 || order.TotalAmount.Currency != "JPY" {
+		t.Errorf("order total = %+v, want 3200 JPY", order.TotalAmount)
+	}
+	// Addresses are normalized and billed to the shipping address by default
+	if order.ShippingAddress.PostalCode != "150-0001" || order.ShippingAddress.Prefecture != "東京都" {
+		t.Errorf("order shipping address = %+v, want 150-0001 東京都", order.ShippingAddress)
+	}
+	if order.BillingAddress != nil {
+		t.Errorf("order billing address = %+v, want none", order.BillingAddress)
+	}
+}
+
+func TestCreateOrder_Rejected(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+	gw.products.addProduct("retired", "Old Mug", "kitchen", 500, 5)
+	gw.products.products["retired"].IsActive = false
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "no token", body: orderRequest(`[{"product_id":"p1","quantity":1}]`), wantCode: http.StatusUnauthorized},
+		{name: "unverified email", token: "carol-token", body: orderRequest(`[{"product_id":"p1","quantity":1}]`), wantCode: http.StatusForbidden},
+		{name: "no items", token: "alice-token", body: orderRequest(`[]`), wantCode: http.StatusBadRequest},
+		{name: "zero quantity", token: "alice-token", body: orderRequest(`[{"product_id":"p1","quantity":0}]`), wantCode: http.StatusBadRequest},
+		{name: "unknown product", token: "alice-token", body: orderRequest(`[{"product_id":"missing","quantity":1}]`), wantCode: http.StatusBadRequest},
+		{name: "inactive product", token: "alice-token", body: orderRequest(`[{"product_id":"retired","quantity":1}]`), wantCode: http.StatusConflict},
+		{
+			name:     "missing address",
+			token:    "alice-token",
+			body:     `{"items":[{"product_id":"p1","quantity":1}],"shipping_address":{"postal_code":"150-0001","prefecture":"","city":"","address_line1":""}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid postal code",
+			token:    "alice-token",
+			body:     `{"items":[{"product_id":"p1","quantity":1}],"shipping_address":{"postal_code":"15-0001","prefecture":"東京都","city":"渋谷区","address_line1":"1-1-1"}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:  "unknown billing prefecture",
+			token: "alice-token",
+			body: `{"items":[{"product_id":"p1","quantity":1}],"shipping_address":{"postal_code":"150-0001","prefecture":"東京都","city":"渋谷区","address_line1":"1-1-1"},` +
+				`"billing_address":{"postal_code":"150-0001","prefecture":"Gotham","city":"渋谷区","address_line1":"1-1-1"}}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodPost, "/api/v1/orders", tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("POST /orders status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+
+	if gw.orders.created != 0 {
+		t.Errorf("orders created = %d, want 0", gw.orders.created)
+	}
+}
+
+func TestListMyOrders(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+	gw.orders.addOrder("o2", "alice", orderpb.OrderStatus_ORDER_STATUS_SHIPPED)
+	gw.orders.addOrder("o3", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+	gw.orders.addOrder("o4", "bob", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []string
+		wantTotal int32
+	}{
+		{name: "all", query: "", wantIDs: []string{"o1", "o2", "o3"}, wantTotal: 3},
+		{name: "status", query: "?status=pending", wantIDs: []string{"o1", "o3"}, wantTotal: 2},
+		{name: "second page", query: "?page=2&page_size=2", wantIDs: []string{"o3"}, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodGet, "/api/v1/orders"+tt.query, "alice-token", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET /orders status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			var body struct {
+				Orders     []orderBody `json:"orders"`
+				Pagination struct {
+					TotalCount int32 `json:"total_count"`
+				} `json:"pagination"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			var ids []string
+			for _, o := range body.Orders {
+				ids = append(ids, o.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("order ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("order ids = %v, want %v", ids, tt.wantIDs)
+					break
+				}
+			}
+			if body.Pagination.TotalCount != tt.wantTotal {
+				t.Errorf("total_count = %d, want %d", body.Pagination.TotalCount, tt.wantTotal)
+			}
+		})
+	}
+
+	for _, query := range []string{"?status=lost", "?page=-1"} {
+		if w := gw.do(http.MethodGet, "/api/v1/orders"+query, "alice-token", ""); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /orders%s status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetOrder_Ownership(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "owner", token: "alice-token", wantCode: http.StatusOK},
+		{name: "staff", token: "staff-token", wantCode: http.StatusOK},
+		{name: "admin", token: "admin-token", wantCode: http.StatusOK},
+		// Other users cannot tell the order exists
+		{name: "other user", token: "bob-token", wantCode: http.StatusNotFound},
+		{name: "no token", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodGet, "/api/v1/orders/o1", tt.token, "")
+			if w.Code != tt.wantCode {
+				t.Errorf("GET /orders/o1 status = %d, want %d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+	gw.orders.addOrder("o2", "alice", orderpb.OrderStatus_ORDER_STATUS_SHIPPED)
+	gw.orders.addOrder("o3", "alice", orderpb.OrderStatus_ORDER_STATUS_CONFIRMED)
+
+	if w := gw.do(http.MethodPost, "/api/v1/orders/o1/cancel", "bob-token", `{"reason":"mine now"}`); w.Code != http.StatusNotFound {
+		t.Errorf("cancel other user's order status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w := gw.do(http.MethodPost, "/api/v1/orders/o1/cancel", "alice-token", `{"reason":"changed my mind"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel order status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if order := decodeOrder(t, w.Body.Bytes()); order.Status != "cancelled" {
+		t.Errorf("order status = %q, want %q", order.Status, "cancelled")
+	}
+	if got := gw.orders.reasons["o1"]; got != "changed my mind" {
+		t.Errorf("cancellation reason = %q, want %q", got, "changed my mind")
+	}
+
+	// Shipped orders can no longer be cancelled
+	if w := gw.do(http.MethodPost, "/api/v1/orders/o2/cancel", "alice-token", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("cancel shipped order status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// Paid orders are cancelled by staff, who also refund the payment
+	if w := gw.do(http.MethodPost, "/api/v1/orders/o3/cancel", "alice-token", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("cancel confirmed order status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := gw.do(http.MethodPost, "/api/v1/orders/o3/cancel", "staff-token", `{"reason":"out of stock"}`); w.Code != http.StatusOK {
+		t.Errorf("cancel confirmed order as staff status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+}
+
+func TestChangeOrderAddress(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("pending", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+	gw.orders.addOrder("confirmed", "alice", orderpb.OrderStatus_ORDER_STATUS_CONFIRMED)
+	gw.orders.addOrder("processing", "alice", orderpb.OrderStatus_ORDER_STATUS_PROCESSING)
+
+	const addresses = `{"shipping_address":{"postal_code":"5300001","prefecture":"Osaka","city":"大阪市北区","address_line1":"梅田1-1-1"},` +
+		`"billing_address":{"postal_code":"100-0001","prefecture":"東京都","city":"千代田区","address_line1":"千代田1-1"}}`
+
+	tests := []struct {
+		name     string
+		token    string
+		orderID  string
+		body     string
+		wantCode int
+	}{
+		{name: "pending order", token: "alice-token", orderID: "pending", body: addresses, wantCode: http.StatusOK},
+		{name: "confirmed order", token: "alice-token", orderID: "confirmed", body: addresses, wantCode: http.StatusOK},
+		{name: "order being prepared", token: "alice-token", orderID: "processing", body: addresses, wantCode: http.StatusConflict},
+		{name: "other user's order", token: "bob-token", orderID: "pending", body: addresses, wantCode: http.StatusNotFound},
+		{
+			name:     "invalid postal code",
+			token:    "alice-token",
+			orderID:  "pending",
+			body:     `{"shipping_address":{"postal_code":"abc-defg","prefecture":"Osaka","city":"大阪市北区","address_line1":"梅田1-1-1"}}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodPut, "/api/v1/orders/"+tt.orderID+"/address", tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Fatalf("PUT /orders/%s/address status = %d, want %d, body = %s", tt.orderID, w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			order := decodeOrder(t, w.Body.Bytes())
+			if order.ShippingAddress.PostalCode != "530-0001" || order.ShippingAddress.Prefecture != "大阪府" {
+				t.Errorf("shipping address = %+v, want 530-0001 大阪府", order.ShippingAddress)
+			}
+			if order.BillingAddress == nil || order.BillingAddress.Prefecture != "東京都" {
+				t.Errorf("billing address = %+v, want 東京都", order.BillingAddress)
+			}
+		})
+	}
+
+	// Addresses of an order being prepared for shipping are left untouched
+	if got := gw.orders.orders["processing"].ShippingAddress; got != nil {
+		t.Errorf("processing order shipping address = %+v, want unchanged", got)
+	}
+}
+
+func TestGetOrder_IncludesHistory(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+
+	w := gw.do(http.MethodPost, "/api/v1/orders/o1/cancel", "alice-token", `{"reason":"changed my mind"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel order status = %d, want %d", w.Code, http.StatusOK)
+	}
+	// Only the order detail carries the history
+	if strings.Contains(w.Body.String(), `"history"`) {
+		t.Errorf("cancel response should not include the history: %s", w.Body.String())
+	}
+
+	// Support staff can see who cancelled the order and why
+	w = gw.do(http.MethodGet, "/api/v1/orders/o1", "staff-token", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /orders/o1 status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var order struct {
+		History []struct {
+			FromStatus string `json:"from_status"`
+			ToStatus   string `json:"to_status"`
+			ActorType  string `json:"actor_type"`
+			ActorID    string `json:"actor_id"`
+			Reason     string `json:"reason"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &order); err != nil {
+		t.Fatalf("failed to decode order: %v", err)
+	}
+
+	if len(order.History) != 2 {
+		t.Fatalf("history = %+v, want creation and cancellation", order.History)
+	}
+	if created := order.History[0]; created.FromStatus != "" || created.ToStatus != "pending" {
+		t.Errorf("first change = %+v, want creation as pending", created)
+	}
+	cancelled := order.History[1]
+	if cancelled.FromStatus != "pending" || cancelled.ToStatus != "cancelled" {
+		t.Errorf("second change = %+v, want pending to cancelled", cancelled)
+	}
+	if cancelled.ActorType != "user" || cancelled.ActorID != "alice" || cancelled.Reason != "changed my mind" {
+		t.Errorf("cancellation = %+v, want alice with her reason", cancelled)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	orderpb "github.com/Riku-KANO/kube-ec/proto/order"
+	paymentpb "github.com/Riku-KANO/kube-ec/proto/payment"
+)
+
+// paymentBody is the subset of the payment response checked by the tests
+type paymentBody struct {
+	ID      string `json:"id"`
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+	Amount  struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+	Status string `json:"status"`
+	Method string `json:"method"`
+}
+
+func TestCreatePayment(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+	gw.orders.addOrder("shipped", "alice", orderpb.OrderStatus_ORDER_STATUS_SHIPPED)
+
+	// The amount is the order total
+	w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /payments status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var payment paymentBody
+	if err := json.Unmarshal(w.Body.Bytes(), &payment); err != nil {
+		t.Fatalf("failed to decode payment: %v", err)
+	}
+	if payment.OrderID != "o1" || payment.UserID != "alice" || payment.Status != "pending" || payment.Method != "credit_card" {
+		t.Errorf("payment = %+v", payment)
+	}
+	if payment.Amount.Amount != 1000 || payment.Amount.Currency != "JPY" {
+		t.Errorf("payment amount = %+v, want 1000 JPY", payment.Amount)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "no token", body: `{"order_id":"o1","method":"credit_card"}`, wantCode: http.StatusUnauthorized},
+		{name: "unknown method", token: "alice-token", body: `{"order_id":"o1","method":"cash"}`, wantCode: http.StatusBadRequest},
+		{name: "other user's order", token: "bob-token", body: `{"order_id":"o1","method":"credit_card"}`, wantCode: http.StatusNotFound},
+		{name: "order not pending", token: "alice-token", body: `{"order_id":"shipped","method":"credit_card"}`, wantCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodPost, "/api/v1/payments", tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("POST /payments status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreatePayment_PayTwice(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+
+	w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("first POST /payments status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var first paymentBody
+	if err := json.Unmarshal(w.Body.Bytes(), &first); err != nil {
+		t.Fatalf("failed to decode payment: %v", err)
+	}
+
+	// A pending payment blocks another one for the same order
+	if w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`); w.Code != http.StatusConflict {
+		t.Errorf("POST /payments with a pending payment status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// So does a completed one
+	if w := gw.do(http.MethodPost, "/api/v1/payments/"+first.ID+"/process", "alice-token", `{"payment_token":"tok_visa"}`); w.Code != http.StatusOK {
+		t.Fatalf("process payment status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`); w.Code != http.StatusConflict {
+		t.Errorf("POST /payments with a completed payment status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestCreatePayment_RetryAfterDecline(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.orders.addOrder("o1", "alice", orderpb.OrderStatus_ORDER_STATUS_PENDING)
+
+	w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("first POST /payments status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var first paymentBody
+	if err := json.Unmarshal(w.Body.Bytes(), &first); err != nil {
+		t.Fatalf("failed to decode payment: %v", err)
+	}
+	gw.do(http.MethodPost, "/api/v1/payments/"+first.ID+"/process", "alice-token", `{"payment_token":"`+declinedPaymentToken+`"}`)
+
+	// A declined payment does not block another attempt
+	if w := gw.do(http.MethodPost, "/api/v1/payments", "alice-token", `{"order_id":"o1","method":"credit_card"}`); w.Code != http.StatusCreated {
+		t.Errorf("POST /payments after a declined payment status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+}
+
+func TestProcessPayment(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.payments.addPayment("pay1", "alice", 1000, paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING)
+	gw.payments.addPayment("pay2", "alice", 1000, paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING)
+
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pay1/process", "bob-token", `{"payment_token":"tok_visa"}`); w.Code != http.StatusNotFound {
+		t.Errorf("process other user's payment status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w := gw.do(http.MethodPost, "/api/v1/payments/pay1/process", "alice-token", `{"payment_token":"tok_visa"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("process payment status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	// The status can be polled once processed
+	w = gw.do(http.MethodGet, "/api/v1/payments/pay1/status", "alice-token", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET payment status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body struct {
+		Status        string `json:"status"`
+		TransactionID string `json:"transaction_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Status != "completed" || body.TransactionID != "txn_pay1" {
+		t.Errorf("payment status = %+v, want completed with txn_pay1", body)
+	}
+
+	// A processed payment cannot be charged again
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pay1/process", "alice-token", `{"payment_token":"tok_visa"}`); w.Code != http.StatusConflict {
+		t.Errorf("process completed payment status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	// A declined charge is an error, not a 200 with success=false
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pay2/process", "alice-token", `{"payment_token":"`+declinedPaymentToken+`"}`); w.Code != http.StatusPaymentRequired {
+		t.Errorf("declined payment status = %d, want %d", w.Code, http.StatusPaymentRequired)
+	}
+
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pay2/process", "alice-token", `{"payment_token":""}`); w.Code != http.StatusBadRequest {
+		t.Errorf("process without token status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetPayment_Ownership(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.payments.addPayment("pay1", "alice", 1000, paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING)
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+	}{
+		{name: "owner", token: "alice-token", wantCode: http.StatusOK},
+		{name: "staff", token: "staff-token", wantCode: http.StatusOK},
+		{name: "other user", token: "bob-token", wantCode: http.StatusNotFound},
+		{name: "no token", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, path := range []string{"/api/v1/payments/pay1", "/api/v1/payments/pay1/status"} {
+				if w := gw.do(http.MethodGet, path, tt.token, ""); w.Code != tt.wantCode {
+					t.Errorf("GET %s status = %d, want %d", path, w.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestRefundPayment(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.payments.addPayment("pay1", "alice", 1000, paymentpb.PaymentStatus_PAYMENT_STATUS_COMPLETED)
+	gw.payments.addPayment("pending", "alice", 1000, paymentpb.PaymentStatus_PAYMENT_STATUS_PENDING)
+
+	// Only admins may refund, even their own payments
+	for _, token := range []string{"alice-token", "staff-token"} {
+		if w := gw.do(http.MethodPost, "/api/v1/payments/pay1/refund", token, `{}`); w.Code != http.StatusForbidden {
+			t.Errorf("refund as %s status = %d, want %d", token, w.Code, http.StatusForbidden)
+		}
+	}
+
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pay1/refund", "admin-token", `{"amount":5000}`); w.Code != http.StatusBadRequest {
+		t.Errorf("refund more than paid status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w := gw.do(http.MethodPost, "/api/v1/payments/pay1/refund", "admin-token", `{"amount":400,"reason":"damaged"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("refund status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	refund := gw.payments.refunds["pay1"]
+	if refund == nil || refund.Amount.Amount != 400 || refund.Amount.Currency != "JPY" || refund.Reason != "damaged" {
+		t.Errorf("refund request = %+v", refund)
+	}
+
+	// Refunds of payments that are not completed are conflicts, not 200s
+	if w := gw.do(http.MethodPost, "/api/v1/payments/pending/refund", "admin-token", `{}`); w.Code != http.StatusConflict {
+		t.Errorf("refund pending payment status = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
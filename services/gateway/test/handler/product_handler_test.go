@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// productBody is the subset of the product response checked by the tests
+type productBody struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"price"`
+	StockQuantity int32    `json:"stock_quantity"`
+	Category      string   `json:"category"`
+	ImageURLs     []string `json:"image_urls"`
+	SKU           string   `json:"sku"`
+	IsActive      bool     `json:"is_active"`
+}
+
+func decodeProduct(t *testing.T, body []byte) productBody {
+	t.Helper()
+
+	var product productBody
+	if err := json.Unmarshal(body, &product); err != nil {
+		t.Fatalf("failed to decode product: %v", err)
+	}
+	return product
+}
+
+func TestListProducts(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+	gw.products.addProduct("p2", "Red Mug", "kitchen", 1200, 5)
+	gw.products.addProduct("p3", "Blue Shirt", "apparel", 3000, 5)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantIDs   []string
+		wantTotal int32
+		wantPages int32
+	}{
+		{name: "all", query: "", wantIDs: []string{"p1", "p2", "p3"}, wantTotal: 3, wantPages: 1},
+		{name: "category", query: "?category=kitchen", wantIDs: []string{"p1", "p2"}, wantTotal: 2, wantPages: 1},
+		{name: "search", query: "?q=Blue", wantIDs: []string{"p1", "p3"}, wantTotal: 2, wantPages: 1},
+		{name: "second page", query: "?page=2&page_size=2", wantIDs: []string{"p3"}, wantTotal: 3, wantPages: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The catalog is public
+			w := gw.do(http.MethodGet, "/api/v1/products"+tt.query, "", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("GET /products status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+			}
+
+			var body struct {
+				Products   []productBody `json:"products"`
+				Pagination struct {
+					TotalCount int32 `json:"total_count"`
+					TotalPages int32 `json:"total_pages"`
+				} `json:"pagination"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			var ids []string
+			for _, p := range body.Products {
+				ids = append(ids, p.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("product ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("product ids = %v, want %v", ids, tt.wantIDs)
+					break
+				}
+			}
+			if body.Pagination.TotalCount != tt.wantTotal || body.Pagination.TotalPages != tt.wantPages {
+				t.Errorf("pagination = %+v, want total %d in %d pages", body.Pagination, tt.wantTotal, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestListProducts_InvalidQuery(t *testing.T) {
+	gw := newTestGateway(t)
+
+	for _, query := range []string{"?page=abc", "?page=-1", "?page_size=-5"} {
+		w := gw.do(http.MethodGet, "/api/v1/products"+query, "", "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /products%s status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+
+	w := gw.do(http.MethodGet, "/api/v1/products/p1", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /products/p1 status = %d, want %d", w.Code, http.StatusOK)
+	}
+	product := decodeProduct(t, w.Body.Bytes())
+	if product.Name != "Blue Mug" || product.Price.Amount != 1200 || product.Price.Currency != "JPY" {
+		t.Errorf("GET /products/p1 = %+v", product)
+	}
+	if product.ImageURLs == nil {
+		t.Error("image_urls should be an empty list, not null")
+	}
+
+	if w := gw.do(http.MethodGet, "/api/v1/products/missing", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET unknown product status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestProductWrites_Authorization(t *testing.T) {
+	const createBody = `{"name":"Mug","price":{"amount":1200,"currency":"JPY"}}`
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "create without token", method: http.MethodPost, path: "/api/v1/products", body: createBody, wantCode: http.StatusUnauthorized},
+		{name: "customer creates", method: http.MethodPost, path: "/api/v1/products", token: "alice-token", body: createBody, wantCode: http.StatusForbidden},
+		{name: "staff creates", method: http.MethodPost, path: "/api/v1/products", token: "staff-token", body: createBody, wantCode: http.StatusCreated},
+		{name: "admin creates", method: http.MethodPost, path: "/api/v1/products", token: "admin-token", body: createBody, wantCode: http.StatusCreated},
+		{name: "customer updates", method: http.MethodPut, path: "/api/v1/products/p1", token: "alice-token", body: `{"name":"Cup"}`, wantCode: http.StatusForbidden},
+		{name: "customer deletes", method: http.MethodDelete, path: "/api/v1/products/p1", token: "alice-token", wantCode: http.StatusForbidden},
+		{name: "customer adjusts stock", method: http.MethodPost, path: "/api/v1/products/p1/stock", token: "alice-token", body: `{"quantity_change":1}`, wantCode: http.StatusForbidden},
+		{name: "staff deletes", method: http.MethodDelete, path: "/api/v1/products/p1", token: "staff-token", wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newTestGateway(t)
+			gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+
+			w := gw.do(tt.method, tt.path, tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s status = %d, want %d, body = %s", tt.method, tt.path, w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateProduct(t *testing.T) {
+	gw := newTestGateway(t)
+
+	w := gw.do(http.MethodPost, "/api/v1/products", "staff-token",
+		`{"name":"Mug","price":{"amount":1200,"currency":"JPY"},"stock_quantity":7,"category":"kitchen","sku":"MUG-1"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /products status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	product := decodeProduct(t, w.Body.Bytes())
+	if product.Name != "Mug" || product.StockQuantity != 7 || product.Category != "kitchen" || !product.IsActive {
+		t.Errorf("POST /products = %+v", product)
+	}
+
+	// The staff member's token is forwarded to the product service
+	if len(gw.products.writeTokens) != 1 || gw.products.writeTokens[0] != "staff-token" {
+		t.Errorf("forwarded tokens = %v, want [staff-token]", gw.products.writeTokens)
+	}
+}
+
+func TestCreateProduct_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing name", body: `{"price":{"amount":1200,"currency":"JPY"}}`},
+		{name: "missing price", body: `{"name":"Mug"}`},
+		{name: "zero price", body: `{"name":"Mug","price":{"amount":0,"currency":"JPY"}}`},
+		{name: "invalid currency", body: `{"name":"Mug","price":{"amount":1200,"currency":"yen"}}`},
+		{name: "negative stock", body: `{"name":"Mug","price":{"amount":1200,"currency":"JPY"},"stock_quantity":-1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newTestGateway(t)
+
+			w := gw.do(http.MethodPost, "/api/v1/products", "staff-token", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("POST /products status = %d, want %d, body = %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+			if len(gw.products.products) != 0 {
+				t.Error("invalid products should not be created")
+			}
+		})
+	}
+}
+
+func TestUpdateProduct_KeepsUnsetFields(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+	gw.products.products["p1"].Sku = "MUG-BLUE"
+
+	w := gw.do(http.MethodPut, "/api/v1/products/p1", "staff-token", `{"price":{"amount":980,"currency":"JPY"},"is_active":false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT /products/p1 status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	product := decodeProduct(t, w.Body.Bytes())
+	if product.Price.Amount != 980 || product.IsActive {
+		t.Errorf("updated fields = %+v, want price 980 and inactive", product)
+	}
+	if product.Name != "Blue Mug" || product.Category != "kitchen" || product.StockQuantity != 5 || product.SKU != "MUG-BLUE" {
+		t.Errorf("unset fields changed: %+v", product)
+	}
+
+	w = gw.do(http.MethodPut, "/api/v1/products/p1", "staff-token", `{"sku":"MUG-NAVY"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT /products/p1 status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if product := decodeProduct(t, w.Body.Bytes()); product.SKU != "MUG-NAVY" {
+		t.Errorf("sku = %q, want MUG-NAVY", product.SKU)
+	}
+
+	if w := gw.do(http.MethodPut, "/api/v1/products/missing", "staff-token", `{"name":"Cup"}`); w.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown product status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpdateProductStock(t *testing.T) {
+	gw := newTestGateway(t)
+	gw.products.addProduct("p1", "Blue Mug", "kitchen", 1200, 5)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantStock int32
+	}{
+		{name: "add stock", body: `{"quantity_change":10}`, wantCode: http.StatusOK, wantStock: 15},
+		{name: "remove stock", body: `{"quantity_change":-3}`, wantCode: http.StatusOK, wantStock: 12},
+		{name: "zero change", body: `{"quantity_change":0}`, wantCode: http.StatusBadRequest, wantStock: 12},
+		{name: "remove more than in stock", body: `{"quantity_change":-20}`, wantCode: http.StatusConflict, wantStock: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := gw.do(http.MethodPost, "/api/v1/products/p1/stock", "staff-token", tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("POST /products/p1/stock status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if got := gw.products.products["p1"].StockQuantity; got != tt.wantStock {
+				t.Errorf("stock = %d, want %d", got, tt.wantStock)
+			}
+		})
+	}
+}
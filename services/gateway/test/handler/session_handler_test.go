@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRefreshToken(t *testing.T) {
+	gw := newTestGateway(t)
+
+	w := gw.do(http.MethodPost, "/api/v1/auth/refresh", "", `{"refresh_token":"alice-token-refresh"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /auth/refresh status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.AccessToken == "" || body.RefreshToken == "" {
+		t.Fatalf("POST /auth/refresh returned empty tokens: %s", w.Body.String())
+	}
+
+	// The new access token is accepted
+	if w := gw.do(http.MethodGet, "/api/v1/users/me", body.AccessToken, ""); w.Code != http.StatusOK {
+		t.Errorf("GET /users/me with refreshed token status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// The refresh token cannot be used twice
+	w = gw.do(http.MethodPost, "/api/v1/auth/refresh", "", `{"refresh_token":"alice-token-refresh"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("POST /auth/refresh again status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRefreshToken_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "missing body", body: "", wantCode: http.StatusBadRequest},
+		{name: "empty refresh token", body: `{"refresh_token":""}`, wantCode: http.StatusBadRequest},
+		{name: "unknown refresh token", body: `{"refresh_token":"bogus"}`, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newTestGateway(t)
+
+			w := gw.do(http.MethodPost, "/api/v1/auth/refresh", "", tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("POST /auth/refresh status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	gw := newTestGateway(t)
+
+	if w := gw.do(http.MethodPost, "/api/v1/auth/logout", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("POST /auth/logout without token status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	w := gw.do(http.MethodPost, "/api/v1/auth/logout", "alice-token", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /auth/logout status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	// Both tokens of the session are revoked
+	if w := gw.do(http.MethodGet, "/api/v1/users/me", "alice-token", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /users/me after logout status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	w = gw.do(http.MethodPost, "/api/v1/auth/refresh", "", `{"refresh_token":"alice-token-refresh"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("POST /auth/refresh after logout status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	// Other users stay logged in
+	if w := gw.do(http.MethodGet, "/api/v1/users/me", "bob-token", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /users/me as another user status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		body     string
+		wantCode int
+	}{
+		{name: "without token", body: `{"old_password":"password123","new_password":"newPassword456"}`, wantCode: http.StatusUnauthorized},
+		{name: "wrong current password", token: "alice-token", body: `{"old_password":"wrong-password","new_password":"newPassword456"}`, wantCode: http.StatusUnauthorized},
+		{name: "new password too short", token: "alice-token", body: `{"old_password":"password123","new_password":"short"}`, wantCode: http.StatusBadRequest},
+		{name: "missing current password", token: "alice-token", body: `{"new_password":"newPassword456"}`, wantCode: http.StatusBadRequest},
+		{name: "success", token: "alice-token", body: `{"old_password":"password123","new_password":"newPassword456"}`, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newTestGateway(t)
+
+			w := gw.do(http.MethodPut, "/api/v1/auth/password", tt.token, tt.body)
+			if w.Code != tt.wantCode {
+				t.Errorf("PUT /auth/password status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestChangePassword_LogsOutSessions(t *testing.T) {
+	gw := newTestGateway(t)
+
+	w := gw.do(http.MethodPut, "/api/v1/auth/password", "alice-token",
+		`{"old_password":"password123","new_password":"newPassword456"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT /auth/password status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if w := gw.do(http.MethodGet, "/api/v1/users/me", "alice-token", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /users/me after password change status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
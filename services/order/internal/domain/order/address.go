@@ -0,0 +1,51 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Riku-KANO/kube-ec/pkg/address"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+)
+
+// ErrAddressLocked is returned when the addresses of an order can no longer be changed
+var ErrAddressLocked = errors.New("order addresses can no longer be changed")
+
+// CanChangeAddress reports whether the addresses of an order in the given
+// status may be changed. Once the order is being prepared for shipping the
+// addresses are fixed.
+func CanChangeAddress(status pb.OrderStatus) bool {
+	return status == pb.OrderStatus_ORDER_STATUS_PENDING || status == pb.OrderStatus_ORDER_STATUS_CONFIRMED
+}
+
+// ValidateAddressChange returns ErrAddressLocked, naming the status, when
+// the addresses of an order in the given status may not be changed
+func ValidateAddressChange(status pb.OrderStatus) error {
+	if !CanChangeAddress(status) {
+		return fmt.Errorf("%w: order is %s", ErrAddressLocked, status)
+	}
+	return nil
+}
+
+// NormalizeAddress validates an address with the shared address rules and
+// returns it in the normalized form stored on orders
+func NormalizeAddress(a *commonpb.Address) (*commonpb.Address, error) {
+	if a == nil {
+		return nil, address.ErrRequired
+	}
+
+	normalized, err := address.New(a.PostalCode, a.Prefecture, a.City, a.AddressLine1, a.AddressLine2, a.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commonpb.Address{
+		PostalCode:   normalized.PostalCode(),
+		Prefecture:   normalized.Prefecture(),
+		City:         normalized.City(),
+		AddressLine1: normalized.AddressLine1(),
+		AddressLine2: normalized.AddressLine2(),
+		PhoneNumber:  normalized.PhoneNumber(),
+	}, nil
+}
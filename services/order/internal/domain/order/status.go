@@ -0,0 +1,52 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+)
+
+// ErrInvalidTransition is returned for a status change the order lifecycle does not allow
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// transitions lists the statuses an order may move to from each status.
+// Orders move forward one step at a time and can be cancelled until they
+// ship; delivered and cancelled orders are final. Customers can only cancel
+// pending orders, which the server checks on top of this table.
+var transitions = map[pb.OrderStatus][]pb.OrderStatus{
+	pb.OrderStatus_ORDER_STATUS_PENDING: {
+		pb.OrderStatus_ORDER_STATUS_CONFIRMED,
+		pb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	pb.OrderStatus_ORDER_STATUS_CONFIRMED: {
+		pb.OrderStatus_ORDER_STATUS_PROCESSING,
+		pb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	pb.OrderStatus_ORDER_STATUS_PROCESSING: {
+		pb.OrderStatus_ORDER_STATUS_SHIPPED,
+		pb.OrderStatus_ORDER_STATUS_CANCELLED,
+	},
+	pb.OrderStatus_ORDER_STATUS_SHIPPED: {
+		pb.OrderStatus_ORDER_STATUS_DELIVERED,
+	},
+}
+
+// CanTransition reports whether an order in status from may move to status to
+func CanTransition(from, to pb.OrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns ErrInvalidTransition, naming both statuses,
+// when an order in status from may not move to status to
+func ValidateTransition(from, to pb.OrderStatus) error {
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
@@ -7,9 +7,12 @@ import (
 	"net"
 	"os"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -32,9 +35,27 @@ func main() {
 
 	log.Println("Successfully connected to database")
 
+	// アクセストークンの検証（JWKS_URL が設定されていれば認証サービスの公開鍵、なければ共有シークレット）
+	verifier, err := auth.NewVerifier(os.Getenv("JWKS_URL"), os.Getenv("JWT_SECRET"))
+	if err != nil {
+		log.Fatalf("JWKS_URL or JWT_SECRET environment variable is required: %v", err)
+	}
+
+	// 商品サービスへの接続（価格の取得に使う GetProduct は認証不要）
+	productAddr := os.Getenv("PRODUCT_SERVICE_ADDR")
+	if productAddr == "" {
+		productAddr = "localhost:50053"
+	}
+
+	productConn, err := grpc.Dial(productAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to product service: %v", err)
+	}
+	defer productConn.Close()
+
 	// リポジトリとサーバーの初期化
 	repo := NewOrderRepository(db)
-	orderServer := NewOrderServer(repo)
+	orderServer := NewOrderServer(repo, productpb.NewProductServiceClient(productConn))
 
 	// gRPCサーバーの起動
 	port := os.Getenv("GRPC_PORT")
@@ -47,7 +68,28 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// 注文の作成・参照・キャンセル・住所の変更と履歴の参照はログインユーザー（本人の注文のみ）、ステータスの更新はスタッフのみ
+	permissions := auth.Permissions{
+		pb.OrderService_CreateOrder_FullMethodName:              {},
+		pb.OrderService_GetOrder_FullMethodName:                 {},
+		pb.OrderService_GetOrderByIdempotencyKey_FullMethodName: {},
+		pb.OrderService_ListOrders_FullMethodName:               {},
+		pb.OrderService_CancelOrder_FullMethodName:              {},
+		pb.OrderService_GetOrderHistory_FullMethodName:          {},
+		pb.OrderService_UpdateOrderAddress_FullMethodName:       {},
+		pb.OrderService_UpdateOrderStatus_FullMethodName:        {auth.RoleStaff},
+	}
+
+	// 他サービス（チェックアウトサービスなど）からの注文作成とその確認、ステータス更新はスコープで認可する
+	scopes := auth.Scopes{
+		pb.OrderService_CreateOrder_FullMethodName:              auth.ScopeOrderCreate,
+		pb.OrderService_GetOrderByIdempotencyKey_FullMethodName: auth.ScopeOrderCreate,
+		pb.OrderService_UpdateOrderStatus_FullMethodName:        auth.ScopeOrderStatus,
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(verifier, permissions, scopes)),
+	)
 	pb.RegisterOrderServiceServer(grpcServer, orderServer)
 
 	// リフレクションを有効化（開発用）
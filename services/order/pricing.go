@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxOrderItems 1 注文あたりの明細数の上限
+const maxOrderItems = 100
+
+// priceItems 商品カタログから各商品の名前と価格を取得して注文明細を作る
+// クライアントが送った名前と価格は使わない。明細には注文時点の価格を保存する。
+// 販売中でない商品と、通貨の異なる商品の組み合わせは注文できない
+func priceItems(ctx context.Context, products productpb.ProductServiceClient, requested []*pb.OrderItem) ([]*pb.OrderItem, *commonpb.Money, error) {
+	if len(requested) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "at least one item is required")
+	}
+	if len(requested) > maxOrderItems {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "at most %d items can be ordered at once", maxOrderItems)
+	}
+
+	items := make([]*pb.OrderItem, 0, len(requested))
+	var total *commonpb.Money
+
+	for _, item := range requested {
+		if item.ProductId == "" || item.Quantity <= 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "each item needs a product_id and a positive quantity")
+		}
+
+		product, err := products.GetProduct(ctx, &productpb.GetProductRequest{Id: item.ProductId})
+		if status.Code(err) == codes.NotFound {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "product %s not found", item.ProductId)
+		}
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Unavailable, "failed to get product %s: %v", item.ProductId, err)
+		}
+
+		if !product.IsActive || product.Price == nil {
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "product %s is not for sale", item.ProductId)
+		}
+		if total == nil {
+			total = &commonpb.Money{Currency: product.Price.Currency}
+		}
+		if product.Price.Currency != total.Currency {
+			return nil, nil, status.Errorf(codes.FailedPrecondition,
+				"product %s is priced in %s, not %s", item.ProductId, product.Price.Currency, total.Currency)
+		}
+
+		subtotal := product.Price.Amount * int64(item.Quantity)
+		total.Amount += subtotal
+		items = append(items, &pb.OrderItem{
+			ProductId:   product.Id,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			UnitPrice:   &commonpb.Money{Currency: product.Price.Currency, Amount: product.Price.Amount},
+			Subtotal:    &commonpb.Money{Currency: product.Price.Currency, Amount: subtotal},
+		})
+	}
+
+	return items, total, nil
+}
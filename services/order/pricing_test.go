@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeProductClient serves GetProduct from an in-memory catalog
+type fakeProductClient struct {
+	productpb.ProductServiceClient
+	products map[string]*productpb.Product
+	err      error
+}
+
+func (c *fakeProductClient) GetProduct(ctx context.Context, req *productpb.GetProductRequest, opts ...grpc.CallOption) (*productpb.Product, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	product, ok := c.products[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	return product, nil
+}
+
+func newFakeCatalog() *fakeProductClient {
+	return &fakeProductClient{products: map[string]*productpb.Product{
+		"tea": {
+			Id: "tea", Name: "Green Tea", IsActive: true,
+			Price: &commonpb.Money{Currency: "JPY", Amount: 500},
+		},
+		"cup": {
+			Id: "cup", Name: "Tea Cup", IsActive: true,
+			Price: &commonpb.Money{Currency: "JPY", Amount: 1200},
+		},
+		"retired": {
+			Id: "retired", Name: "Old Kettle", IsActive: false,
+			Price: &commonpb.Money{Currency: "JPY", Amount: 3000},
+		},
+		"imported": {
+			Id: "imported", Name: "Imported Teapot", IsActive: true,
+			Price: &commonpb.Money{Currency: "USD", Amount: 40},
+		},
+	}}
+}
+
+func TestPriceItems(t *testing.T) {
+	items, total, err := priceItems(context.Background(), newFakeCatalog(), []*pb.OrderItem{
+		{
+			ProductId:   "tea",
+			Quantity:    3,
+			ProductName: "Free Tea",
+			UnitPrice:   &commonpb.Money{Currency: "JPY", Amount: 1},
+			Subtotal:    &commonpb.Money{Currency: "JPY", Amount: 3},
+		},
+		{ProductId: "cup", Quantity: 2},
+	})
+	if err != nil {
+		t.Fatalf("priceItems() error = %v", err)
+	}
+
+	if total.Currency != "JPY" || total.Amount != 3*500+2*1200 {
+		t.Errorf("total = %s %d, want JPY %d", total.Currency, total.Amount, 3*500+2*1200)
+	}
+
+	want := []struct {
+		name      string
+		unitPrice int64
+		subtotal  int64
+	}{
+		{name: "Green Tea", unitPrice: 500, subtotal: 1500},
+		{name: "Tea Cup", unitPrice: 1200, subtotal: 2400},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("len(items) = %d, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		item := items[i]
+		if item.ProductName != w.name {
+			t.Errorf("items[%d].ProductName = %q, want %q", i, item.ProductName, w.name)
+		}
+		if item.UnitPrice.Amount != w.unitPrice || item.UnitPrice.Currency != "JPY" {
+			t.Errorf("items[%d].UnitPrice = %v, want JPY %d", i, item.UnitPrice, w.unitPrice)
+		}
+		if item.Subtotal.Amount != w.subtotal {
+			t.Errorf("items[%d].Subtotal = %d, want %d", i, item.Subtotal.Amount, w.subtotal)
+		}
+	}
+}
+
+func TestPriceItems_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []*pb.OrderItem
+		catalog  func(*fakeProductClient)
+		wantCode codes.Code
+	}{
+		{
+			name:     "no items",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing product id",
+			items:    []*pb.OrderItem{{Quantity: 1}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "non-positive quantity",
+			items:    []*pb.OrderItem{{ProductId: "tea", Quantity: 0}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown product",
+			items:    []*pb.OrderItem{{ProductId: "missing", Quantity: 1}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "inactive product",
+			items:    []*pb.OrderItem{{ProductId: "tea", Quantity: 1}, {ProductId: "retired", Quantity: 1}},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "mixed currencies",
+			items:    []*pb.OrderItem{{ProductId: "tea", Quantity: 1}, {ProductId: "imported", Quantity: 1}},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:  "product service down",
+			items: []*pb.OrderItem{{ProductId: "tea", Quantity: 1}},
+			catalog: func(c *fakeProductClient) {
+				c.err = status.Error(codes.Unavailable, "connection refused")
+			},
+			wantCode: codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := newFakeCatalog()
+			if tt.catalog != nil {
+				tt.catalog(catalog)
+			}
+
+			_, _, err := priceItems(context.Background(), catalog, tt.items)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("priceItems() error = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
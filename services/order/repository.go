@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
 	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	"github.com/lib/pq"
 )
 
 type OrderRepository struct {
@@ -19,86 +21,117 @@ func NewOrderRepository(db *sql.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-func (r *OrderRepository) Create(ctx context.Context, order *pb.Order) error {
+var (
+	// ErrStatusChanged 注文のステータスが読み込んだ後に変更された
+	ErrStatusChanged = errors.New("order status was changed by another request")
+	// ErrOrderNotFound 注文が存在しない
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrIdempotencyKeyUsed 同じユーザーの同じ冪等キーの注文がすでに存在する
+	ErrIdempotencyKeyUsed = errors.New("an order with the idempotency key already exists")
+)
+
+// Actor 注文を作成・変更した呼び出し元
+type Actor struct {
+	Type string // user または service
+	ID   string // ユーザーID、またはサービスのクライアントID
+}
+
+// StatusChange 注文ステータスの変更内容
+type StatusChange struct {
+	From      pb.OrderStatus
+	To        pb.OrderStatus
+	PaymentID string
+	Actor     Actor
+	Reason    string
+}
+
+const orderColumns = `id, user_id, items, total_currency, total_amount, status,
+		shipping_address, billing_address, payment_id, created_at, updated_at`
+
+// Create 注文を保存し、作成を履歴の最初の記録として残す
+// idempotencyKey が空でなく、同じユーザーの注文ですでに使われている場合は ErrIdempotencyKeyUsed を返す
+func (r *OrderRepository) Create(ctx context.Context, order *pb.Order, idempotencyKey string, actor Actor) error {
 	// OrderItemsをJSONに変換
 	itemsJSON, err := json.Marshal(order.Items)
 	if err != nil {
 		return fmt.Errorf("failed to marshal items: %w", err)
 	}
+	shippingJSON, billingJSON, err := marshalAddresses(order.ShippingAddress, order.BillingAddress)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
 	query := `
-		INSERT INTO orders (id, user_id, items, total_currency, total_amount, status, payment_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO orders (id, user_id, items, total_currency, total_amount, status, shipping_address, billing_address, payment_id, idempotency_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 	now := time.Now()
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		order.Id,
 		order.UserId,
 		itemsJSON,
 		order.TotalAmount.Currency,
 		order.TotalAmount.Amount,
 		order.Status.String(),
+		shippingJSON,
+		billingJSON,
 		order.PaymentId,
+		idempotencyKey,
 		now,
 		now,
 	)
-	return err
-}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_orders_user_id_idempotency_key" {
+		return ErrIdempotencyKeyUsed
+	}
+	if err != nil {
+		return err
+	}
 
-func (r *OrderRepository) GetByID(ctx context.Context, id string) (*pb.Order, error) {
-	query := `
-		SELECT id, user_id, items, total_currency, total_amount, status, payment_id, created_at, updated_at
-		FROM orders
-		WHERE id = $1
-	`
-	order := &pb.Order{
-		TotalAmount: &commonpb.Money{},
-		CreatedAt:   &commonpb.Timestamp{},
-		UpdatedAt:   &commonpb.Timestamp{},
+	if err := insertHistory(ctx, tx, order.Id, StatusChange{To: order.Status, Actor: actor}, now); err != nil {
+		return err
 	}
 
-	var itemsJSON []byte
-	var statusStr string
-	var createdAt, updatedAt time.Time
+	return tx.Commit()
+}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.Id,
-		&order.UserId,
-		&itemsJSON,
-		&order.TotalAmount.Currency,
-		&order.TotalAmount.Amount,
-		&statusStr,
-		&order.PaymentId,
-		&createdAt,
-		&updatedAt,
-	)
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*pb.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("order not found")
+		return nil, ErrOrderNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	// JSONをOrderItemsに変換
-	if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
-	}
+	return order, nil
+}
 
-	// ステータスの変換
-	order.Status = pb.OrderStatus(pb.OrderStatus_value[statusStr])
+// GetByIdempotencyKey ユーザーが冪等キーを付けて作成した注文を取得する
+func (r *OrderRepository) GetByIdempotencyKey(ctx context.Context, userID, idempotencyKey string) (*pb.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE user_id = $1 AND idempotency_key = $2 AND idempotency_key <> ''`
 
-	order.CreatedAt.Seconds = createdAt.Unix()
-	order.UpdatedAt.Seconds = updatedAt.Unix()
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, userID, idempotencyKey))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	return order, nil
 }
 
 func (r *OrderRepository) ListByUser(ctx context.Context, userID string, page, pageSize int32, status pb.OrderStatus) ([]*pb.Order, int32, error) {
-	baseQuery := `
-		SELECT id, user_id, items, total_currency, total_amount, status, payment_id, created_at, updated_at
-		FROM orders
-		WHERE user_id = $1
-	`
+	baseQuery := `SELECT ` + orderColumns + ` FROM orders WHERE user_id = $1`
 	countQuery := "SELECT COUNT(*) FROM orders WHERE user_id = $1"
 	args := []interface{}{userID}
 	argIdx := 2
@@ -130,50 +163,202 @@ func (r *OrderRepository) ListByUser(ctx context.Context, userID string, page, p
 
 	orders := []*pb.Order{}
 	for rows.Next() {
-		order := &pb.Order{
-			TotalAmount: &commonpb.Money{},
-			CreatedAt:   &commonpb.Timestamp{},
-			UpdatedAt:   &commonpb.Timestamp{},
-		}
-		var itemsJSON []byte
-		var statusStr string
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(
-			&order.Id,
-			&order.UserId,
-			&itemsJSON,
-			&order.TotalAmount.Currency,
-			&order.TotalAmount.Amount,
-			&statusStr,
-			&order.PaymentId,
-			&createdAt,
-			&updatedAt,
-		)
+		order, err := scanOrder(rows)
 		if err != nil {
 			return nil, 0, err
 		}
+		orders = append(orders, order)
+	}
 
-		// JSONをOrderItemsに変換
-		if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
-			return nil, 0, fmt.Errorf("failed to unmarshal items: %w", err)
-		}
+	return orders, totalCount, rows.Err()
+}
 
-		order.Status = pb.OrderStatus(pb.OrderStatus_value[statusStr])
-		order.CreatedAt.Seconds = createdAt.Unix()
-		order.UpdatedAt.Seconds = updatedAt.Unix()
-		orders = append(orders, order)
+// UpdateStatus 注文のステータスが change.From のままの場合のみ change.To に更新し、履歴に記録する
+// change.PaymentID が空でなければ同時に決済IDを紐付ける
+// 他のリクエストが先にステータスを変更していた場合は ErrStatusChanged を返す
+func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, change StatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	return orders, totalCount, nil
+	query := `
+		UPDATE orders
+		SET status = $3, payment_id = COALESCE(NULLIF($4, ''), payment_id), updated_at = $5
+		WHERE id = $1 AND status = $2
+	`
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query, id, change.From.String(), change.To.String(), change.PaymentID, now)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrStatusChanged
+	}
+
+	if err := insertHistory(ctx, tx, id, change, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *OrderRepository) UpdateStatus(ctx context.Context, id string, status pb.OrderStatus) error {
+// UpdateAddress 注文のステータスが from のままの場合のみ配送先と請求先を更新する
+// billing が nil の場合は配送先と同じ住所に請求する
+// 他のリクエストが先にステータスを変更していた場合は ErrStatusChanged を返す
+func (r *OrderRepository) UpdateAddress(ctx context.Context, id string, from pb.OrderStatus, shipping, billing *commonpb.Address) error {
+	shippingJSON, billingJSON, err := marshalAddresses(shipping, billing)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE orders
-		SET status = $2, updated_at = $3
-		WHERE id = $1
+		SET shipping_address = $3, billing_address = $4, updated_at = $5
+		WHERE id = $1 AND status = $2
+	`
+	result, err := r.db.ExecContext(ctx, query, id, from.String(), shippingJSON, billingJSON, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// History 注文のステータス変更履歴を古い順に返す
+func (r *OrderRepository) History(ctx context.Context, id string) ([]*pb.OrderStatusChange, error) {
+	query := `
+		SELECT from_status, to_status, actor_type, actor_id, reason, created_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*pb.OrderStatusChange
+	for rows.Next() {
+		var fromStatus, toStatus string
+		var changedAt time.Time
+		change := &pb.OrderStatusChange{}
+
+		if err := rows.Scan(&fromStatus, &toStatus, &change.ActorType, &change.ActorId, &change.Reason, &changedAt); err != nil {
+			return nil, err
+		}
+
+		change.FromStatus = pb.OrderStatus(pb.OrderStatus_value[fromStatus])
+		change.ToStatus = pb.OrderStatus(pb.OrderStatus_value[toStatus])
+		change.ChangedAt = &commonpb.Timestamp{Seconds: changedAt.Unix()}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// insertHistory ステータスの変更を履歴に記録する
+// 注文の作成では change.From は未指定で、空文字として保存する
+func insertHistory(ctx context.Context, tx *sql.Tx, orderID string, change StatusChange, changedAt time.Time) error {
+	var fromStatus string
+	if change.From != pb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		fromStatus = change.From.String()
+	}
+
+	query := `
+		INSERT INTO order_status_history (order_id, from_status, to_status, actor_type, actor_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := r.db.ExecContext(ctx, query, id, status.String(), time.Now())
+	_, err := tx.ExecContext(ctx, query,
+		orderID,
+		fromStatus,
+		change.To.String(),
+		change.Actor.Type,
+		change.Actor.ID,
+		change.Reason,
+		changedAt,
+	)
 	return err
 }
+
+// scanOrder orderColumns の順に読み込んだ行を注文に変換する
+func scanOrder(row interface{ Scan(dest ...any) error }) (*pb.Order, error) {
+	order := &pb.Order{
+		TotalAmount: &commonpb.Money{},
+		CreatedAt:   &commonpb.Timestamp{},
+		UpdatedAt:   &commonpb.Timestamp{},
+	}
+
+	var itemsJSON, shippingJSON, billingJSON []byte
+	var statusStr string
+	var createdAt, updatedAt time.Time
+
+	err := row.Scan(
+		&order.Id,
+		&order.UserId,
+		&itemsJSON,
+		&order.TotalAmount.Currency,
+		&order.TotalAmount.Amount,
+		&statusStr,
+		&shippingJSON,
+		&billingJSON,
+		&order.PaymentId,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSONをOrderItemsに変換
+	if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+	}
+	if err := json.Unmarshal(shippingJSON, &order.ShippingAddress); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal shipping address: %w", err)
+	}
+	// 請求先が NULL の場合は配送先と同じ
+	if billingJSON != nil {
+		if err := json.Unmarshal(billingJSON, &order.BillingAddress); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal billing address: %w", err)
+		}
+	}
+
+	// ステータスの変換
+	order.Status = pb.OrderStatus(pb.OrderStatus_value[statusStr])
+
+	order.CreatedAt.Seconds = createdAt.Unix()
+	order.UpdatedAt.Seconds = updatedAt.Unix()
+
+	return order, nil
+}
+
+// marshalAddresses 配送先と請求先をJSONに変換する
+// 請求先が nil の場合は NULL として保存する
+func marshalAddresses(shipping, billing *commonpb.Address) (shippingJSON, billingJSON []byte, err error) {
+	shippingJSON, err = json.Marshal(shipping)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal shipping address: %w", err)
+	}
+	if billing != nil {
+		billingJSON, err = json.Marshal(billing)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal billing address: %w", err)
+		}
+	}
+	return shippingJSON, billingJSON, nil
+}
@@ -2,75 +2,154 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
 	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	productpb "github.com/Riku-KANO/kube-ec/proto/product"
+	domain "github.com/Riku-KANO/kube-ec/services/order/internal/domain/order"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// maxIdempotencyKeyLength 冪等キーの最大長
+const maxIdempotencyKeyLength = 255
+
 type OrderServer struct {
 	pb.UnimplementedOrderServiceServer
-	repo *OrderRepository
+	repo     *OrderRepository
+	products productpb.ProductServiceClient
 }
 
-func NewOrderServer(repo *OrderRepository) *OrderServer {
+func NewOrderServer(repo *OrderRepository, products productpb.ProductServiceClient) *OrderServer {
 	return &OrderServer{
-		repo: repo,
+		repo:     repo,
+		products: products,
+	}
+}
+
+// canAccess 呼び出し元が指定ユーザーの注文を扱えるか判定する
+// スコープで認可済みのサービストークン、本人、スタッフと管理者のみ
+func canAccess(ctx context.Context, userID string) bool {
+	if canManage(ctx) {
+		return true
+	}
+	claims, ok := auth.FromContext(ctx)
+	return ok && claims.UserID == userID
+}
+
+// canManage 呼び出し元が他のユーザーの注文も管理できるか
+// スコープで認可済みのサービストークン、スタッフと管理者のみ
+func canManage(ctx context.Context) bool {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return false
 	}
+	return claims.TokenType == auth.TokenTypeService || auth.HasAnyRole(claims.Roles, auth.RoleStaff, auth.RoleAdmin)
+}
+
+// actorFromContext 呼び出し元を履歴に記録する形で返す
+func actorFromContext(ctx context.Context) Actor {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return Actor{}
+	}
+	if claims.TokenType == auth.TokenTypeService {
+		return Actor{Type: "service", ID: claims.ClientID}
+	}
+	return Actor{Type: "user", ID: claims.UserID}
 }
 
 func (s *OrderServer) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
-	if len(req.Items) == 0 {
-		return nil, status.Error(codes.InvalidArgument, "at least one item is required")
+	if !canAccess(ctx, req.UserId) {
+		return nil, status.Error(codes.PermissionDenied, "cannot place orders for another user")
+	}
+
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("idempotency_key must be at most %d bytes", maxIdempotencyKeyLength))
 	}
 
-	// 合計金額の計算
-	var totalAmount int64
-	currency := "JPY"
-	for _, item := range req.Items {
-		if item.Subtotal == nil {
-			item.Subtotal = &commonpb.Money{
-				Currency: currency,
-				Amount:   item.UnitPrice.Amount * int64(item.Quantity),
-			}
+	// 同じキーの再送は作成済みの注文を返す
+	if req.IdempotencyKey != "" {
+		order, err := s.repo.GetByIdempotencyKey(ctx, req.UserId, req.IdempotencyKey)
+		if err == nil {
+			return order, nil
 		}
-		totalAmount += item.Subtotal.Amount
+		if !errors.Is(err, ErrOrderNotFound) {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get order: %v", err))
+		}
+	}
+
+	shippingAddress, billingAddress, err := normalizeAddresses(req.ShippingAddress, req.BillingAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 価格は商品カタログから取得する
+	items, totalAmount, err := priceItems(ctx, s.products, req.Items)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &pb.Order{
-		Id:     uuid.New().String(),
-		UserId: req.UserId,
-		Items:  req.Items,
-		TotalAmount: &commonpb.Money{
-			Currency: currency,
-			Amount:   totalAmount,
-		},
+		Id:              uuid.New().String(),
+		UserId:          req.UserId,
+		Items:           items,
+		TotalAmount:     totalAmount,
 		Status:          pb.OrderStatus_ORDER_STATUS_PENDING,
-		ShippingAddress: req.ShippingAddress,
+		ShippingAddress: shippingAddress,
+		BillingAddress:  billingAddress,
 		CreatedAt:       &commonpb.Timestamp{},
 		UpdatedAt:       &commonpb.Timestamp{},
 	}
 
-	if err := s.repo.Create(ctx, order); err != nil {
+	err = s.repo.Create(ctx, order, req.IdempotencyKey, actorFromContext(ctx))
+	if errors.Is(err, ErrIdempotencyKeyUsed) {
+		// 同じキーの要求が同時に届き、先に作成された
+		order, err = s.repo.GetByIdempotencyKey(ctx, req.UserId, req.IdempotencyKey)
+	}
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to create order: %v", err))
 	}
 
 	return order, nil
 }
 
+// GetOrderByIdempotencyKey 冪等キーを付けて作成した注文を取得する
+// 作成要求の結果が分からない呼び出し元が、注文が作成されたかを確かめるために使う
+func (s *OrderServer) GetOrderByIdempotencyKey(ctx context.Context, req *pb.GetOrderByIdempotencyKeyRequest) (*pb.Order, error) {
+	if req.UserId == "" || req.IdempotencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and idempotency_key are required")
+	}
+	if !canAccess(ctx, req.UserId) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	order, err := s.repo.GetByIdempotencyKey(ctx, req.UserId, req.IdempotencyKey)
+	if errors.Is(err, ErrOrderNotFound) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get order: %v", err))
+	}
+
+	return order, nil
+}
+
 func (s *OrderServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
+	// 他のユーザーの注文は存在しないものとして扱う
 	order, err := s.repo.GetByID(ctx, req.Id)
-	if err != nil {
+	if err != nil || !canAccess(ctx, order.UserId) {
 		return nil, status.Error(codes.NotFound, "order not found")
 	}
 
@@ -81,6 +160,9 @@ func (s *OrderServer) ListOrders(ctx context.Context, req *pb.ListOrdersRequest)
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
+	if !canAccess(ctx, req.UserId) {
+		return nil, status.Error(codes.PermissionDenied, "cannot list orders of another user")
+	}
 
 	page := req.Pagination.Page
 	pageSize := req.Pagination.PageSize
@@ -117,11 +199,22 @@ func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrder
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
-	if err := s.repo.UpdateStatus(ctx, req.Id, req.Status); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update order status: %v", err))
+	order, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "order not found")
 	}
 
-	order, err := s.repo.GetByID(ctx, req.Id)
+	// 同じステータスへの更新は再試行とみなして何もしない
+	if order.Status == req.Status && (req.PaymentId == "" || req.PaymentId == order.PaymentId) {
+		return order, nil
+	}
+
+	// 決済完了による確定ではチェックアウトサービスが決済IDを渡す
+	if err := s.changeStatus(ctx, order, req.Status, req.PaymentId, req.Reason); err != nil {
+		return nil, err
+	}
+
+	order, err = s.repo.GetByID(ctx, req.Id)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to get updated order")
 	}
@@ -129,6 +222,28 @@ func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrder
 	return order, nil
 }
 
+// changeStatus 状態遷移表で許可されている場合のみ注文のステータスを変更し、呼び出し元と理由を履歴に残す
+func (s *OrderServer) changeStatus(ctx context.Context, order *pb.Order, to pb.OrderStatus, paymentID, reason string) error {
+	if err := domain.ValidateTransition(order.Status, to); err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	err := s.repo.UpdateStatus(ctx, order.Id, StatusChange{
+		From:      order.Status,
+		To:        to,
+		PaymentID: paymentID,
+		Actor:     actorFromContext(ctx),
+		Reason:    reason,
+	})
+	if errors.Is(err, ErrStatusChanged) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to update order status: %v", err))
+	}
+	return nil
+}
+
 func (s *OrderServer) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.Order, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
@@ -136,20 +251,18 @@ func (s *OrderServer) CancelOrder(ctx context.Context, req *pb.CancelOrderReques
 
 	// 現在の注文を取得
 	order, err := s.repo.GetByID(ctx, req.Id)
-	if err != nil {
+	if err != nil || !canAccess(ctx, order.UserId) {
 		return nil, status.Error(codes.NotFound, "order not found")
 	}
 
-	// キャンセル可能かチェック
-	if order.Status == pb.OrderStatus_ORDER_STATUS_SHIPPED ||
-		order.Status == pb.OrderStatus_ORDER_STATUS_DELIVERED ||
-		order.Status == pb.OrderStatus_ORDER_STATUS_CANCELLED {
-		return nil, status.Error(codes.FailedPrecondition, "order cannot be cancelled")
+	// 支払い済みの注文は返金と在庫の戻しが必要なため、顧客がキャンセルできるのは支払い前の注文のみ
+	if order.Status != pb.OrderStatus_ORDER_STATUS_PENDING && !canManage(ctx) {
+		return nil, status.Error(codes.FailedPrecondition, "only pending orders can be cancelled")
 	}
 
-	// ステータスをキャンセルに更新
-	if err := s.repo.UpdateStatus(ctx, req.Id, pb.OrderStatus_ORDER_STATUS_CANCELLED); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to cancel order: %v", err))
+	// 発送済み・配達済み・キャンセル済みの注文はキャンセルできない
+	if err := s.changeStatus(ctx, order, pb.OrderStatus_ORDER_STATUS_CANCELLED, "", req.Reason); err != nil {
+		return nil, err
 	}
 
 	order, err = s.repo.GetByID(ctx, req.Id)
@@ -159,3 +272,78 @@ func (s *OrderServer) CancelOrder(ctx context.Context, req *pb.CancelOrderReques
 
 	return order, nil
 }
+
+func (s *OrderServer) GetOrderHistory(ctx context.Context, req *pb.GetOrderHistoryRequest) (*pb.GetOrderHistoryResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	// 他のユーザーの注文は存在しないものとして扱う
+	order, err := s.repo.GetByID(ctx, req.OrderId)
+	if err != nil || !canAccess(ctx, order.UserId) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	changes, err := s.repo.History(ctx, req.OrderId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get order history: %v", err))
+	}
+
+	return &pb.GetOrderHistoryResponse{Changes: changes}, nil
+}
+
+// UpdateOrderAddress 発送準備に入る前（PENDING・CONFIRMED）の注文の配送先と請求先を変更する
+func (s *OrderServer) UpdateOrderAddress(ctx context.Context, req *pb.UpdateOrderAddressRequest) (*pb.Order, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	shippingAddress, billingAddress, err := normalizeAddresses(req.ShippingAddress, req.BillingAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 他のユーザーの注文は存在しないものとして扱う
+	order, err := s.repo.GetByID(ctx, req.Id)
+	if err != nil || !canAccess(ctx, order.UserId) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	if err := domain.ValidateAddressChange(order.Status); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	err = s.repo.UpdateAddress(ctx, order.Id, order.Status, shippingAddress, billingAddress)
+	if errors.Is(err, ErrStatusChanged) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update order address: %v", err))
+	}
+
+	order, err = s.repo.GetByID(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get updated order")
+	}
+
+	return order, nil
+}
+
+// normalizeAddresses 配送先と請求先を検証して正規化する
+// 配送先は必須。請求先は未指定の場合 nil のまま返し、配送先と同じ住所として扱う
+func normalizeAddresses(shipping, billing *commonpb.Address) (*commonpb.Address, *commonpb.Address, error) {
+	shippingAddress, err := domain.NormalizeAddress(shipping)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid shipping_address: %v", err)
+	}
+
+	if billing == nil {
+		return shippingAddress, nil, nil
+	}
+	billingAddress, err := domain.NormalizeAddress(billing)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid billing_address: %v", err)
+	}
+
+	return shippingAddress, billingAddress, nil
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Riku-KANO/kube-ec/pkg/address"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	"github.com/Riku-KANO/kube-ec/services/order/internal/domain/order"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	valid := func(modify func(a *commonpb.Address)) *commonpb.Address {
+		a := &commonpb.Address{
+			PostalCode:   "150-0001",
+			Prefecture:   "東京都",
+			City:         "渋谷区",
+			AddressLine1: "神宮前1-1-1",
+		}
+		modify(a)
+		return a
+	}
+
+	tests := []struct {
+		name           string
+		address        *commonpb.Address
+		wantErr        error
+		wantPostalCode string
+		wantPrefecture string
+	}{
+		{
+			name:           "valid address",
+			address:        valid(func(a *commonpb.Address) {}),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "東京都",
+		},
+		{
+			name:           "postal code without hyphen",
+			address:        valid(func(a *commonpb.Address) { a.PostalCode = "1500001" }),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "東京都",
+		},
+		{
+			name:           "full-width postal code with mark",
+			address:        valid(func(a *commonpb.Address) { a.PostalCode = "〒１５０－０００１" }),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "東京都",
+		},
+		{
+			name:           "romaji prefecture",
+			address:        valid(func(a *commonpb.Address) { a.Prefecture = "osaka" }),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "大阪府",
+		},
+		{
+			name:           "domestic phone number",
+			address:        valid(func(a *commonpb.Address) { a.PhoneNumber = "090-1234-5678" }),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "東京都",
+		},
+		{
+			name:           "E.164 phone number",
+			address:        valid(func(a *commonpb.Address) { a.PhoneNumber = "+819012345678" }),
+			wantPostalCode: "150-0001",
+			wantPrefecture: "東京都",
+		},
+		{
+			name:    "missing address",
+			wantErr: address.ErrRequired,
+		},
+		{
+			name:    "missing city",
+			address: valid(func(a *commonpb.Address) { a.City = " " }),
+			wantErr: address.ErrRequired,
+		},
+		{
+			name:    "postal code too short",
+			address: valid(func(a *commonpb.Address) { a.PostalCode = "150-001" }),
+			wantErr: address.ErrInvalidPostalCode,
+		},
+		{
+			name:    "postal code with misplaced hyphen",
+			address: valid(func(a *commonpb.Address) { a.PostalCode = "1500-001" }),
+			wantErr: address.ErrInvalidPostalCode,
+		},
+		{
+			name:    "unknown prefecture",
+			address: valid(func(a *commonpb.Address) { a.Prefecture = "California" }),
+			wantErr: address.ErrInvalidPrefecture,
+		},
+		{
+			name:    "prefecture without suffix",
+			address: valid(func(a *commonpb.Address) { a.Prefecture = "東京" }),
+			wantErr: address.ErrInvalidPrefecture,
+		},
+		{
+			name:    "international phone number",
+			address: valid(func(a *commonpb.Address) { a.PhoneNumber = "+14155552671" }),
+			wantErr: address.ErrInvalidPhoneNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := order.NormalizeAddress(tt.address)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizeAddress() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.PostalCode != tt.wantPostalCode {
+				t.Errorf("PostalCode = %q, want %q", got.PostalCode, tt.wantPostalCode)
+			}
+			if got.Prefecture != tt.wantPrefecture {
+				t.Errorf("Prefecture = %q, want %q", got.Prefecture, tt.wantPrefecture)
+			}
+		})
+	}
+}
+
+func TestCanChangeAddress(t *testing.T) {
+	tests := []struct {
+		status pb.OrderStatus
+		want   bool
+	}{
+		{pb.OrderStatus_ORDER_STATUS_PENDING, true},
+		{pb.OrderStatus_ORDER_STATUS_CONFIRMED, true},
+		{pb.OrderStatus_ORDER_STATUS_PROCESSING, false},
+		{pb.OrderStatus_ORDER_STATUS_SHIPPED, false},
+		{pb.OrderStatus_ORDER_STATUS_DELIVERED, false},
+		{pb.OrderStatus_ORDER_STATUS_CANCELLED, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			if got := order.CanChangeAddress(tt.status); got != tt.want {
+				t.Errorf("CanChangeAddress() = %v, want %v", got, tt.want)
+			}
+
+			err := order.ValidateAddressChange(tt.status)
+			if tt.want && err != nil {
+				t.Errorf("ValidateAddressChange() error = %v, want nil", err)
+			}
+			if !tt.want && !errors.Is(err, order.ErrAddressLocked) {
+				t.Errorf("ValidateAddressChange() error = %v, want %v", err, order.ErrAddressLocked)
+			}
+		})
+	}
+}
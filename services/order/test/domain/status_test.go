@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/order"
+	"github.com/Riku-KANO/kube-ec/services/order/internal/domain/order"
+)
+
+func TestCanTransition(t *testing.T) {
+	const (
+		pending    = pb.OrderStatus_ORDER_STATUS_PENDING
+		confirmed  = pb.OrderStatus_ORDER_STATUS_CONFIRMED
+		processing = pb.OrderStatus_ORDER_STATUS_PROCESSING
+		shipped    = pb.OrderStatus_ORDER_STATUS_SHIPPED
+		delivered  = pb.OrderStatus_ORDER_STATUS_DELIVERED
+		cancelled  = pb.OrderStatus_ORDER_STATUS_CANCELLED
+		none       = pb.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	)
+
+	// Every status pair not listed here must be rejected
+	allowed := map[pb.OrderStatus][]pb.OrderStatus{
+		pending:    {confirmed, cancelled},
+		confirmed:  {processing, cancelled},
+		processing: {shipped, cancelled},
+		shipped:    {delivered},
+	}
+
+	statuses := []pb.OrderStatus{none, pending, confirmed, processing, shipped, delivered, cancelled}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := false
+			for _, next := range allowed[from] {
+				if next == to {
+					want = true
+				}
+			}
+
+			t.Run(from.String()+" to "+to.String(), func(t *testing.T) {
+				if got := order.CanTransition(from, to); got != want {
+					t.Errorf("CanTransition() = %v, want %v", got, want)
+				}
+
+				err := order.ValidateTransition(from, to)
+				if want && err != nil {
+					t.Errorf("ValidateTransition() error = %v, want nil", err)
+				}
+				if !want && !errors.Is(err, order.ErrInvalidTransition) {
+					t.Errorf("ValidateTransition() error = %v, want %v", err, order.ErrInvalidTransition)
+				}
+			})
+		}
+	}
+}
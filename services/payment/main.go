@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	pb "github.com/Riku-KANO/kube-ec/proto/payment"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
@@ -32,6 +33,12 @@ func main() {
 
 	log.Println("Successfully connected to database")
 
+	// アクセストークンの検証（JWKS_URL が設定されていれば認証サービスの公開鍵、なければ共有シークレット）
+	verifier, err := auth.NewVerifier(os.Getenv("JWKS_URL"), os.Getenv("JWT_SECRET"))
+	if err != nil {
+		log.Fatalf("JWKS_URL or JWT_SECRET environment variable is required: %v", err)
+	}
+
 	// リポジトリとサーバーの初期化
 	repo := NewPaymentRepository(db)
 	paymentServer := NewPaymentServer(repo)
@@ -47,7 +54,27 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// 決済の開始・処理・参照はログインユーザー（本人の決済のみ）、返金は管理者のみ
+	permissions := auth.Permissions{
+		pb.PaymentService_CreatePayment_FullMethodName:    {},
+		pb.PaymentService_ProcessPayment_FullMethodName:   {},
+		pb.PaymentService_GetPayment_FullMethodName:       {},
+		pb.PaymentService_GetPaymentStatus_FullMethodName: {},
+		pb.PaymentService_RefundPayment_FullMethodName:    {auth.RoleAdmin},
+	}
+
+	// 他サービス（注文サービスなど）からの呼び出しはスコープで認可する
+	scopes := auth.Scopes{
+		pb.PaymentService_CreatePayment_FullMethodName:    auth.ScopePaymentProcess,
+		pb.PaymentService_ProcessPayment_FullMethodName:   auth.ScopePaymentProcess,
+		pb.PaymentService_RefundPayment_FullMethodName:    auth.ScopePaymentRefund,
+		pb.PaymentService_GetPayment_FullMethodName:       auth.ScopePaymentRead,
+		pb.PaymentService_GetPaymentStatus_FullMethodName: auth.ScopePaymentRead,
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(verifier, permissions, scopes)),
+	)
 	pb.RegisterPaymentServiceServer(grpcServer, paymentServer)
 
 	// リフレクションを有効化（開発用）
@@ -3,11 +3,18 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
 	pb "github.com/Riku-KANO/kube-ec/proto/payment"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrOrderAlreadyPaid 注文に処理中または完了した決済がすでにある
+	ErrOrderAlreadyPaid = errors.New("the order already has a pending or completed payment")
 )
 
 type PaymentRepository struct {
@@ -36,6 +43,9 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *pb.Payment) err
 		now,
 		now,
 	)
+	if isActivePaymentConflict(err) {
+		return ErrOrderAlreadyPaid
+	}
 	return err
 }
 
@@ -90,3 +100,9 @@ func (r *PaymentRepository) UpdateStatus(ctx context.Context, id string, status
 	_, err := r.db.ExecContext(ctx, query, id, status.String(), transactionID, time.Now())
 	return err
 }
+
+// isActivePaymentConflict 注文ごとに 1 件までの有効な決済の一意制約違反のエラーか
+func isActivePaymentConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_payments_order_id_active"
+}
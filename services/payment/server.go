@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	pb "github.com/Riku-KANO/kube-ec/proto/payment"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -21,10 +23,36 @@ func NewPaymentServer(repo *PaymentRepository) *PaymentServer {
 	}
 }
 
+// canAccess 呼び出し元が指定ユーザーの決済を扱えるか判定する
+// スコープで認可済みのサービストークン、本人、スタッフと管理者のみ
+func canAccess(ctx context.Context, userID string) bool {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	if claims.TokenType == auth.TokenTypeService {
+		return true
+	}
+	return claims.UserID == userID || auth.HasAnyRole(claims.Roles, auth.RoleStaff, auth.RoleAdmin)
+}
+
+// getPayment 呼び出し元が扱える決済を取得する
+// 他のユーザーの決済は存在しないものとして扱う
+func (s *PaymentServer) getPayment(ctx context.Context, id string) (*pb.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, id)
+	if err != nil || !canAccess(ctx, payment.UserId) {
+		return nil, status.Error(codes.NotFound, "payment not found")
+	}
+	return payment, nil
+}
+
 func (s *PaymentServer) CreatePayment(ctx context.Context, req *pb.CreatePaymentRequest) (*pb.Payment, error) {
 	if req.OrderId == "" || req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "order_id and user_id are required")
 	}
+	if !canAccess(ctx, req.UserId) {
+		return nil, status.Error(codes.PermissionDenied, "cannot create payments for another user")
+	}
 	if req.Amount == nil || req.Amount.Amount <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "valid amount is required")
 	}
@@ -38,7 +66,11 @@ func (s *PaymentServer) CreatePayment(ctx context.Context, req *pb.CreatePayment
 		Method:  req.Method,
 	}
 
-	if err := s.repo.Create(ctx, payment); err != nil {
+	err := s.repo.Create(ctx, payment)
+	if errors.Is(err, ErrOrderAlreadyPaid) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to create payment: %v", err))
 	}
 
@@ -50,9 +82,9 @@ func (s *PaymentServer) GetPayment(ctx context.Context, req *pb.GetPaymentReques
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
-	payment, err := s.repo.GetByID(ctx, req.Id)
+	payment, err := s.getPayment(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "payment not found")
+		return nil, err
 	}
 
 	return payment, nil
@@ -63,9 +95,9 @@ func (s *PaymentServer) ProcessPayment(ctx context.Context, req *pb.ProcessPayme
 		return nil, status.Error(codes.InvalidArgument, "payment_id is required")
 	}
 
-	payment, err := s.repo.GetByID(ctx, req.PaymentId)
+	payment, err := s.getPayment(ctx, req.PaymentId)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "payment not found")
+		return nil, err
 	}
 
 	if payment.Status != pb.PaymentStatus_PAYMENT_STATUS_PENDING {
@@ -128,9 +160,9 @@ func (s *PaymentServer) GetPaymentStatus(ctx context.Context, req *pb.GetPayment
 		return nil, status.Error(codes.InvalidArgument, "payment_id is required")
 	}
 
-	payment, err := s.repo.GetByID(ctx, req.PaymentId)
+	payment, err := s.getPayment(ctx, req.PaymentId)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, "payment not found")
+		return nil, err
 	}
 
 	return &pb.GetPaymentStatusResponse{
@@ -3,6 +3,7 @@ module github.com/Riku-KANO/kube-ec/services/product
 go 1.25
 
 require (
+	github.com/Riku-KANO/kube-ec/pkg v0.0.0
 	github.com/Riku-KANO/kube-ec/proto v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
@@ -10,6 +11,8 @@ require (
 )
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
+	golang.org/x/crypto v0.40.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
@@ -18,3 +21,5 @@ require (
 )
 
 replace github.com/Riku-KANO/kube-ec/proto => ../../proto
+
+replace github.com/Riku-KANO/kube-ec/pkg => ../../pkg
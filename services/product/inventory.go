@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+)
+
+// MovementReason 在庫数を変更した理由
+type MovementReason string
+
+const (
+	MovementSale       MovementReason = "sale"       // 販売
+	MovementRestock    MovementReason = "restock"    // 入荷
+	MovementAdjustment MovementReason = "adjustment" // 棚卸しなどによる調整
+	MovementReturn     MovementReason = "return"     // 返品・販売の取り消し
+)
+
+// Actor 在庫数を変更した呼び出し元
+type Actor struct {
+	Type string // user または service
+	ID   string // ユーザーID、またはサービスのクライアントID
+}
+
+// actorFromContext 呼び出し元を台帳に記録する形で返す
+func actorFromContext(ctx context.Context) Actor {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return Actor{}
+	}
+	if claims.TokenType == auth.TokenTypeService {
+		return Actor{Type: "service", ID: claims.ClientID}
+	}
+	return Actor{Type: "user", ID: claims.UserID}
+}
+
+// InventoryMovement 在庫台帳の1件
+type InventoryMovement struct {
+	ID             int64
+	ProductID      string
+	QuantityChange int32
+	StockAfter     int32 // 変更後の在庫数
+	Reason         MovementReason
+	ReferenceID    string // 注文IDや入荷伝票番号など
+	Actor          Actor
+	CreatedAt      time.Time
+}
+
+// MovementFilter 在庫台帳の絞り込み条件。空の項目では絞り込まない
+type MovementFilter struct {
+	ProductID   string
+	Reason      MovementReason
+	ReferenceID string
+}
+
+// changeStock 在庫数を movement.QuantityChange だけ変更し、同じトランザクションで台帳に記録する
+// 在庫数が確保中の数量を下回る場合は変更せず pkgerrors.ErrInsufficientStock を返す
+func changeStock(ctx context.Context, tx *sql.Tx, movement *InventoryMovement) error {
+	query := `
+		UPDATE products
+		SET stock_quantity = stock_quantity + $2, updated_at = $3
+		WHERE id = $1 AND stock_quantity + $2 >= reserved_quantity
+		RETURNING stock_quantity
+	`
+	err := tx.QueryRowContext(ctx, query, movement.ProductID, movement.QuantityChange, time.Now()).Scan(&movement.StockAfter)
+	if err == sql.ErrNoRows {
+		return stockShortage(ctx, tx, movement.ProductID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return recordMovement(ctx, tx, movement)
+}
+
+// recordMovement 在庫台帳に記録する。在庫数の変更と同じトランザクションで呼ぶ
+func recordMovement(ctx context.Context, tx *sql.Tx, movement *InventoryMovement) error {
+	query := `
+		INSERT INTO inventory_movements (product_id, quantity_change, stock_after, reason, reference_id, actor_type, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	movement.CreatedAt = time.Now()
+	return tx.QueryRowContext(ctx, query,
+		movement.ProductID,
+		movement.QuantityChange,
+		movement.StockAfter,
+		string(movement.Reason),
+		movement.ReferenceID,
+		movement.Actor.Type,
+		movement.Actor.ID,
+		movement.CreatedAt,
+	).Scan(&movement.ID)
+}
+
+// stockShortage 在庫数を変更・確保できなかった商品について、存在しないのか在庫が足りないのかを返す
+func stockShortage(ctx context.Context, q queryer, productID string) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, productID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrProductNotFound, productID)
+	}
+	return fmt.Errorf("%w: %s", pkgerrors.ErrInsufficientStock, productID)
+}
+
+// ListMovements 在庫台帳を新しい順に返す
+func (r *ProductRepository) ListMovements(ctx context.Context, page, pageSize int32, filter MovementFilter) ([]*InventoryMovement, int32, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.ProductID != "" {
+		where += fmt.Sprintf(" AND product_id = $%d", argIdx)
+		args = append(args, filter.ProductID)
+		argIdx++
+	}
+	if filter.Reason != "" {
+		where += fmt.Sprintf(" AND reason = $%d", argIdx)
+		args = append(args, string(filter.Reason))
+		argIdx++
+	}
+	if filter.ReferenceID != "" {
+		where += fmt.Sprintf(" AND reference_id = $%d", argIdx)
+		args = append(args, filter.ReferenceID)
+		argIdx++
+	}
+
+	var totalCount int32
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM inventory_movements"+where, args...).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, product_id, quantity_change, stock_after, reason, reference_id, actor_type, actor_id, created_at
+		FROM inventory_movements
+	` + where + fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, pageSize, (page-1)*pageSize)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []*InventoryMovement{}
+	for rows.Next() {
+		movement := &InventoryMovement{}
+		var reason string
+		err := rows.Scan(
+			&movement.ID,
+			&movement.ProductID,
+			&movement.QuantityChange,
+			&movement.StockAfter,
+			&reason,
+			&movement.ReferenceID,
+			&movement.Actor.Type,
+			&movement.Actor.ID,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		movement.Reason = MovementReason(reason)
+		movements = append(movements, movement)
+	}
+
+	return movements, totalCount, rows.Err()
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateStock_NeverNegative(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductRepository(db)
+	productID := createTestProduct(t, db, 10)
+
+	// 並行して減らしても在庫数は0を下回らず、成功した分だけ台帳に記録される
+	const updates = 30
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := repo.UpdateStock(context.Background(), &InventoryMovement{
+				ProductID:      productID,
+				QuantityChange: -1,
+				Reason:         MovementAdjustment,
+				Actor:          Actor{Type: "user", ID: "staff-1"},
+			})
+			if err != nil && !errors.Is(err, pkgerrors.ErrInsufficientStock) {
+				t.Errorf("UpdateStock: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertStockLevel(t, NewReservationRepository(db), productID, 0, 0)
+
+	movements, total, err := repo.ListMovements(context.Background(), 1, 100, MovementFilter{ProductID: productID})
+	if err != nil {
+		t.Fatalf("ListMovements: %v", err)
+	}
+	if total != 10 || len(movements) != 10 {
+		t.Fatalf("movements = %d (total %d), want 10", len(movements), total)
+	}
+	if movements[0].StockAfter != 0 || movements[len(movements)-1].StockAfter != 9 {
+		t.Errorf("stock after = %d..%d, want 9..0", movements[len(movements)-1].StockAfter, movements[0].StockAfter)
+	}
+}
+
+func TestUpdateStock_KeepsReservedStock(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductRepository(db)
+	reservations := NewReservationRepository(db)
+	ctx := context.Background()
+	productID := createTestProduct(t, db, 5)
+
+	if _, err := reservations.Reserve(ctx, uuid.New().String(), []StockHold{{ProductID: productID, Quantity: 3}}, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	// 確保中の3個を下回る調整は拒否する
+	err := repo.UpdateStock(ctx, &InventoryMovement{ProductID: productID, QuantityChange: -3, Reason: MovementAdjustment})
+	if !errors.Is(err, pkgerrors.ErrInsufficientStock) {
+		t.Errorf("UpdateStock error = %v, want pkgerrors.ErrInsufficientStock", err)
+	}
+	if err := repo.UpdateStock(ctx, &InventoryMovement{ProductID: productID, QuantityChange: -2, Reason: MovementAdjustment}); err != nil {
+		t.Errorf("UpdateStock: %v", err)
+	}
+	assertStockLevel(t, reservations, productID, 3, 3)
+
+	err = repo.UpdateStock(ctx, &InventoryMovement{ProductID: uuid.New().String(), QuantityChange: 1, Reason: MovementRestock})
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("UpdateStock of unknown product error = %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestInventoryLedger(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+
+	// 初期在庫は入荷として記録される
+	product, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name:          "Ledger product",
+		Price:         &commonpb.Money{Currency: "JPY", Amount: 1000},
+		StockQuantity: 10,
+		Sku:           uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	restock := &pb.UpdateStockRequest{
+		ProductId:      product.Id,
+		QuantityChange: 5,
+		Reason:         pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK,
+		ReferenceId:    "PO-1",
+	}
+	if _, err := server.UpdateStock(ctx, restock); err != nil {
+		t.Fatalf("UpdateStock: %v", err)
+	}
+
+	orderID := uuid.New().String()
+	if _, err := server.ReserveStock(ctx, &pb.ReserveStockRequest{
+		OrderId: orderID,
+		Items:   []*pb.StockHold{{ProductId: product.Id, Quantity: 4}},
+	}); err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	if _, err := server.CommitReservation(ctx, &pb.CommitReservationRequest{OrderId: orderID}); err != nil {
+		t.Fatalf("CommitReservation: %v", err)
+	}
+
+	_, err = server.UpdateStock(ctx, &pb.UpdateStockRequest{ProductId: product.Id, QuantityChange: -12})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("UpdateStock below zero = %v, want FailedPrecondition", err)
+	}
+
+	resp, err := server.ListInventoryMovements(ctx, &pb.ListInventoryMovementsRequest{ProductId: product.Id})
+	if err != nil {
+		t.Fatalf("ListInventoryMovements: %v", err)
+	}
+
+	want := []struct {
+		change      int32
+		stockAfter  int32
+		reason      pb.InventoryMovementReason
+		referenceID string
+	}{
+		{-4, 11, pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_SALE, orderID},
+		{5, 15, pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK, "PO-1"},
+		{10, 10, pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK, ""},
+	}
+	if len(resp.Movements) != len(want) {
+		t.Fatalf("movements = %v, want %d", resp.Movements, len(want))
+	}
+
+	var sum int32
+	for i, w := range want {
+		got := resp.Movements[i]
+		if got.QuantityChange != w.change || got.StockAfter != w.stockAfter || got.Reason != w.reason || got.ReferenceId != w.referenceID {
+			t.Errorf("movement %d = %+v, want %+v", i, got, w)
+		}
+		sum += got.QuantityChange
+	}
+	if sum != 11 {
+		t.Errorf("movements add up to %d, want the stock 11", sum)
+	}
+
+	sales, err := server.ListInventoryMovements(ctx, &pb.ListInventoryMovementsRequest{
+		ReferenceId: orderID,
+		Reason:      pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_SALE,
+	})
+	if err != nil {
+		t.Fatalf("ListInventoryMovements: %v", err)
+	}
+	if sales.Pagination.TotalCount != 1 {
+		t.Errorf("sales of the order = %d, want 1", sales.Pagination.TotalCount)
+	}
+}
+
+func TestUpdateProduct_KeepsStock(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+
+	product, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name:          "Renamed product",
+		Price:         &commonpb.Money{Currency: "JPY", Amount: 1000},
+		StockQuantity: 10,
+		Sku:           uuid.New().String(),
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if _, err := server.UpdateStock(ctx, &pb.UpdateStockRequest{ProductId: product.Id, QuantityChange: 5}); err != nil {
+		t.Fatalf("UpdateStock: %v", err)
+	}
+
+	// 商品の更新は在庫に触れず、現在の在庫を返す
+	updated, err := server.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		Id:       product.Id,
+		Name:     "Renamed",
+		Price:    product.Price,
+		Sku:      product.Sku,
+		IsActive: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if updated.StockQuantity != 15 {
+		t.Errorf("stock after UpdateProduct = %d, want 15", updated.StockQuantity)
+	}
+
+	resp, err := server.ListInventoryMovements(ctx, &pb.ListInventoryMovementsRequest{ProductId: product.Id})
+	if err != nil {
+		t.Fatalf("ListInventoryMovements: %v", err)
+	}
+	if len(resp.Movements) != 2 {
+		t.Errorf("movements = %v, want the initial stock and the adjustment only", resp.Movements)
+	}
+}
+
+func TestDeleteProduct_KeepsLedger(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+
+	create := func(stock int32) *pb.Product {
+		t.Helper()
+		product, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+			Name:          "Deleted product",
+			Price:         &commonpb.Money{Currency: "JPY", Amount: 1000},
+			StockQuantity: stock,
+			Sku:           uuid.New().String(),
+		})
+		if err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+		return product
+	}
+
+	// 在庫が動いた商品は台帳を残すため削除できない
+	stocked := create(10)
+	_, err := server.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: stocked.Id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteProduct with movements = %v, want FailedPrecondition", err)
+	}
+	resp, err := server.ListInventoryMovements(ctx, &pb.ListInventoryMovementsRequest{ProductId: stocked.Id})
+	if err != nil {
+		t.Fatalf("ListInventoryMovements: %v", err)
+	}
+	if len(resp.Movements) != 1 {
+		t.Errorf("movements = %v, want the initial stock", resp.Movements)
+	}
+
+	// 在庫が一度も動いていない商品は削除できる
+	empty := create(0)
+	if _, err := server.DeleteProduct(ctx, &pb.DeleteProductRequest{Id: empty.Id}); err != nil {
+		t.Errorf("DeleteProduct without movements: %v", err)
+	}
+}
+
+func TestUpdateStock_Validation(t *testing.T) {
+	server := NewProductServer(nil, nil)
+
+	tests := []struct {
+		name string
+		req  *pb.UpdateStockRequest
+	}{
+		{
+			name: "product id missing",
+			req:  &pb.UpdateStockRequest{QuantityChange: 1},
+		},
+		{
+			name: "zero change",
+			req:  &pb.UpdateStockRequest{ProductId: "p1"},
+		},
+		{
+			name: "sale that increases the stock",
+			req:  &pb.UpdateStockRequest{ProductId: "p1", QuantityChange: 1, Reason: pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_SALE},
+		},
+		{
+			name: "restock that decreases the stock",
+			req:  &pb.UpdateStockRequest{ProductId: "p1", QuantityChange: -1, Reason: pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK},
+		},
+		{
+			name: "return that decreases the stock",
+			req:  &pb.UpdateStockRequest{ProductId: "p1", QuantityChange: -1, Reason: pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RETURN},
+		},
+		{
+			name: "unknown reason",
+			req:  &pb.UpdateStockRequest{ProductId: "p1", QuantityChange: 1, Reason: pb.InventoryMovementReason(99)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.UpdateStock(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("UpdateStock error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 
+	"github.com/Riku-KANO/kube-ec/pkg/auth"
 	pb "github.com/Riku-KANO/kube-ec/proto/product"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
@@ -32,9 +33,22 @@ func main() {
 
 	log.Println("Successfully connected to database")
 
+	// アクセストークンの検証（JWKS_URL が設定されていれば認証サービスの公開鍵、なければ共有シークレット）
+	verifier, err := auth.NewVerifier(os.Getenv("JWKS_URL"), os.Getenv("JWT_SECRET"))
+	if err != nil {
+		log.Fatalf("JWKS_URL or JWT_SECRET environment variable is required: %v", err)
+	}
+
 	// リポジトリとサーバーの初期化
 	repo := NewProductRepository(db)
-	productServer := NewProductServer(repo)
+	reservations := NewReservationRepository(db)
+	productServer := NewProductServer(repo, reservations)
+
+	// 期限切れの在庫確保を解放する
+	go sweepLoop(reservations)
+
+	// 検索の修正候補に使う語の一覧を更新する
+	go refreshSearchTermsLoop(repo)
 
 	// gRPCサーバーの起動
 	port := os.Getenv("GRPC_PORT")
@@ -47,7 +61,32 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// 商品と画像の登録・更新・削除、在庫の更新・確保と在庫台帳の参照はスタッフのみ
+	permissions := auth.Permissions{
+		pb.ProductService_CreateProduct_FullMethodName:          {auth.RoleStaff},
+		pb.ProductService_UpdateProduct_FullMethodName:          {auth.RoleStaff},
+		pb.ProductService_DeleteProduct_FullMethodName:          {auth.RoleStaff},
+		pb.ProductService_UpdateStock_FullMethodName:            {auth.RoleStaff},
+		pb.ProductService_ReserveStock_FullMethodName:           {auth.RoleStaff},
+		pb.ProductService_CommitReservation_FullMethodName:      {auth.RoleStaff},
+		pb.ProductService_ReleaseReservation_FullMethodName:     {auth.RoleStaff},
+		pb.ProductService_ListInventoryMovements_FullMethodName: {auth.RoleStaff},
+		pb.ProductService_AddProductImage_FullMethodName:        {auth.RoleStaff},
+		pb.ProductService_ReorderProductImages_FullMethodName:   {auth.RoleStaff},
+		pb.ProductService_RemoveProductImage_FullMethodName:     {auth.RoleStaff},
+	}
+
+	// 他サービス（注文サービスなど）からの在庫更新・確保はスコープで認可する
+	scopes := auth.Scopes{
+		pb.ProductService_UpdateStock_FullMethodName:        auth.ScopeProductStock,
+		pb.ProductService_ReserveStock_FullMethodName:       auth.ScopeProductStock,
+		pb.ProductService_CommitReservation_FullMethodName:  auth.ScopeProductStock,
+		pb.ProductService_ReleaseReservation_FullMethodName: auth.ScopeProductStock,
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor(verifier, permissions, scopes)),
+	)
 	pb.RegisterProductServiceServer(grpcServer, productServer)
 
 	// リフレクションを有効化（開発用）
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"time"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// maxProductImages 1商品に登録できる画像の数
+	maxProductImages = 20
+	// maxImageURLLength 画像URLの最大長
+	maxImageURLLength = 2048
+)
+
+var (
+	// ErrImageNotFound 商品の画像が存在しない
+	ErrImageNotFound = errors.New("product image not found")
+	// ErrTooManyImages 商品の画像が多すぎる
+	ErrTooManyImages = errors.New("too many product images")
+	// ErrImageOrderMismatch 並べ替えの画像IDが商品の画像と一致しない
+	ErrImageOrderMismatch = errors.New("image ids must list every image of the product exactly once")
+	// ErrInvalidImageURL 画像URLが http(s) の絶対URLではない
+	ErrInvalidImageURL = errors.New("image url must be an absolute http or https url")
+)
+
+// validateImageURLs 画像URLの形式と数を確認する
+func validateImageURLs(urls []string) error {
+	if len(urls) > maxProductImages {
+		return ErrTooManyImages
+	}
+	for _, u := range urls {
+		if err := validateImageURL(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateImageURL(rawURL string) error {
+	if rawURL == "" || len(rawURL) > maxImageURLLength {
+		return ErrInvalidImageURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidImageURL
+	}
+	return nil
+}
+
+// setImages 商品に画像を設定し、image_urls を画像の表示順に揃える
+func setImages(product *pb.Product, images []*pb.ProductImage) {
+	product.Images = images
+	product.ImageUrls = make([]string, 0, len(images))
+	for _, image := range images {
+		product.ImageUrls = append(product.ImageUrls, image.Url)
+	}
+}
+
+// loadImages 商品ごとの画像を表示順に読み込む
+func loadImages(ctx context.Context, q queryer, productIDs []string) (map[string][]*pb.ProductImage, error) {
+	query := `
+		SELECT product_id, id, url, alt_text, position
+		FROM product_images
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make(map[string][]*pb.ProductImage, len(productIDs))
+	for rows.Next() {
+		var productID string
+		image := &pb.ProductImage{}
+		if err := rows.Scan(&productID, &image.Id, &image.Url, &image.AltText, &image.Position); err != nil {
+			return nil, err
+		}
+		images[productID] = append(images[productID], image)
+	}
+	return images, rows.Err()
+}
+
+// lockProduct 商品の行をロックし、同じ商品の画像の変更を順に行う
+func lockProduct(ctx context.Context, tx *sql.Tx, productID string) error {
+	var id string
+	err := tx.QueryRowContext(ctx, `SELECT id FROM products WHERE id = $1 FOR UPDATE`, productID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// replaceImages 商品の画像を urls の順に置き換えて返す
+// 残る URL の画像は ID と代替テキストを保持する
+func replaceImages(ctx context.Context, tx *sql.Tx, productID string, urls []string) ([]*pb.ProductImage, error) {
+	current, err := loadImages(ctx, tx, []string{productID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 同じ URL の画像が複数ある場合は前から順に再利用する
+	reusable := make(map[string][]*pb.ProductImage)
+	for _, image := range current[productID] {
+		reusable[image.Url] = append(reusable[image.Url], image)
+	}
+
+	images := make([]*pb.ProductImage, 0, len(urls))
+	kept := make(map[string]bool)
+	for i, u := range urls {
+		image := &pb.ProductImage{Id: uuid.New().String(), Url: u}
+		if candidates := reusable[u]; len(candidates) > 0 {
+			image = candidates[0]
+			reusable[u] = candidates[1:]
+			kept[image.Id] = true
+		}
+		image.Position = int32(i)
+		images = append(images, image)
+	}
+
+	for _, image := range current[productID] {
+		if kept[image.Id] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_images WHERE id = $1`, image.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for _, image := range images {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO product_images (id, product_id, url, alt_text, position, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE SET position = EXCLUDED.position
+		`, image.Id, productID, image.Url, image.AltText, image.Position, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return images, nil
+}
+
+// AddImage 商品の画像を最後に追加する
+func (r *ProductRepository) AddImage(ctx context.Context, productID, imageURL, altText string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockProduct(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	var count int32
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM product_images WHERE product_id = $1`, productID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxProductImages {
+		return ErrTooManyImages
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO product_images (id, product_id, url, alt_text, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, uuid.New().String(), productID, imageURL, altText, count, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReorderImages 商品の画像を imageIDs の順に並べ替える
+// imageIDs が商品のすべての画像を1回ずつ含まない場合は ErrImageOrderMismatch を返す
+func (r *ProductRepository) ReorderImages(ctx context.Context, productID string, imageIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockProduct(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	current, err := loadImages(ctx, tx, []string{productID})
+	if err != nil {
+		return err
+	}
+	if len(imageIDs) != len(current[productID]) {
+		return ErrImageOrderMismatch
+	}
+	remaining := make(map[string]bool, len(imageIDs))
+	for _, image := range current[productID] {
+		remaining[image.Id] = true
+	}
+	for _, id := range imageIDs {
+		if !remaining[id] {
+			return ErrImageOrderMismatch
+		}
+		delete(remaining, id)
+	}
+
+	// 表示順の一意制約はコミット時に検査されるため、入れ替えの途中で重複してもよい
+	for position, id := range imageIDs {
+		if _, err := tx.ExecContext(ctx, `UPDATE product_images SET position = $2 WHERE id = $1`, id, position); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RemoveImage 商品の画像を削除し、後ろの画像の表示順を詰める
+func (r *ProductRepository) RemoveImage(ctx context.Context, productID, imageID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockProduct(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	var position int32
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM product_images WHERE id = $1 AND product_id = $2 RETURNING position
+	`, imageID, productID).Scan(&position)
+	if err == sql.ErrNoRows {
+		return ErrImageNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE product_images SET position = position - 1 WHERE product_id = $1 AND position > $2
+	`, productID, position)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func imageURLs(product *pb.Product) []string {
+	urls := make([]string, 0, len(product.Images))
+	for _, image := range product.Images {
+		urls = append(urls, image.Url)
+	}
+	return urls
+}
+
+func assertImages(t *testing.T, product *pb.Product, want ...string) {
+	t.Helper()
+
+	got := imageURLs(product)
+	if len(got) != len(want) || len(product.ImageUrls) != len(want) {
+		t.Fatalf("images = %v, image_urls = %v, want %v", got, product.ImageUrls, want)
+	}
+	for i := range want {
+		if got[i] != want[i] || product.ImageUrls[i] != want[i] || product.Images[i].Position != int32(i) {
+			t.Errorf("image %d = %s at %d, want %s at %d", i, got[i], product.Images[i].Position, want[i], i)
+		}
+	}
+}
+
+func TestProductImages(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+
+	created, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name:      "Camera",
+		Price:     &commonpb.Money{Currency: "JPY", Amount: 50000},
+		Sku:       uuid.New().String(),
+		ImageUrls: []string{"https://cdn.example.com/front.jpg", "https://cdn.example.com/back.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	assertImages(t, created, "https://cdn.example.com/front.jpg", "https://cdn.example.com/back.jpg")
+
+	product, err := server.GetProduct(ctx, &pb.GetProductRequest{Id: created.Id})
+	if err != nil {
+		t.Fatalf("GetProduct: %v", err)
+	}
+	assertImages(t, product, "https://cdn.example.com/front.jpg", "https://cdn.example.com/back.jpg")
+
+	product, err = server.AddProductImage(ctx, &pb.AddProductImageRequest{
+		ProductId: created.Id,
+		Url:       "https://cdn.example.com/side.jpg",
+		AltText:   "Side view",
+	})
+	if err != nil {
+		t.Fatalf("AddProductImage: %v", err)
+	}
+	assertImages(t, product, "https://cdn.example.com/front.jpg", "https://cdn.example.com/back.jpg", "https://cdn.example.com/side.jpg")
+	side := product.Images[2]
+
+	product, err = server.ReorderProductImages(ctx, &pb.ReorderProductImagesRequest{
+		ProductId: created.Id,
+		ImageIds:  []string{side.Id, product.Images[0].Id, product.Images[1].Id},
+	})
+	if err != nil {
+		t.Fatalf("ReorderProductImages: %v", err)
+	}
+	assertImages(t, product, "https://cdn.example.com/side.jpg", "https://cdn.example.com/front.jpg", "https://cdn.example.com/back.jpg")
+
+	// 画像を置き換えても残る画像は代替テキストを保持する
+	updated, err := server.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		Id:        created.Id,
+		Name:      "Camera",
+		Price:     product.Price,
+		ImageUrls: []string{"https://cdn.example.com/top.jpg", "https://cdn.example.com/side.jpg"},
+		IsActive:  true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	assertImages(t, updated, "https://cdn.example.com/top.jpg", "https://cdn.example.com/side.jpg")
+	if updated.Images[1].Id != side.Id || updated.Images[1].AltText != "Side view" {
+		t.Errorf("side image = %+v, want id %s with its alt text", updated.Images[1], side.Id)
+	}
+
+	product, err = server.RemoveProductImage(ctx, &pb.RemoveProductImageRequest{ProductId: created.Id, ImageId: updated.Images[0].Id})
+	if err != nil {
+		t.Fatalf("RemoveProductImage: %v", err)
+	}
+	assertImages(t, product, "https://cdn.example.com/side.jpg")
+
+	list, err := server.ListProducts(ctx, &pb.ListProductsRequest{Pagination: &commonpb.Pagination{PageSize: 100}})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	for _, listed := range list.Products {
+		if listed.Id == created.Id {
+			assertImages(t, listed, "https://cdn.example.com/side.jpg")
+		}
+	}
+}
+
+func TestProductImages_Rejected(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+	productID := createTestProduct(t, db, 0)
+
+	product, err := server.AddProductImage(ctx, &pb.AddProductImageRequest{ProductId: productID, Url: "https://cdn.example.com/a.jpg"})
+	if err != nil {
+		t.Fatalf("AddProductImage: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "add to unknown product",
+			call: func() error {
+				_, err := server.AddProductImage(ctx, &pb.AddProductImageRequest{ProductId: uuid.New().String(), Url: "https://cdn.example.com/a.jpg"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "reorder with a missing image",
+			call: func() error {
+				_, err := server.ReorderProductImages(ctx, &pb.ReorderProductImagesRequest{ProductId: productID})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "reorder with a duplicate image",
+			call: func() error {
+				id := product.Images[0].Id
+				_, err := server.ReorderProductImages(ctx, &pb.ReorderProductImagesRequest{ProductId: productID, ImageIds: []string{id, id}})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "remove unknown image",
+			call: func() error {
+				_, err := server.RemoveProductImage(ctx, &pb.RemoveProductImageRequest{ProductId: productID, ImageId: uuid.New().String()})
+				return err
+			},
+			want: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); status.Code(err) != tt.want {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for i := 1; i < maxProductImages; i++ {
+		if _, err := server.AddProductImage(ctx, &pb.AddProductImageRequest{ProductId: productID, Url: "https://cdn.example.com/a.jpg"}); err != nil {
+			t.Fatalf("AddProductImage: %v", err)
+		}
+	}
+	_, err = server.AddProductImage(ctx, &pb.AddProductImageRequest{ProductId: productID, Url: "https://cdn.example.com/a.jpg"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("AddProductImage over the limit = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestValidateImageURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		urls []string
+		want error
+	}{
+		{name: "none", urls: nil},
+		{name: "https", urls: []string{"https://cdn.example.com/a.jpg"}},
+		{name: "http", urls: []string{"http://cdn.example.com/a.jpg?w=200"}},
+		{name: "empty", urls: []string{""}, want: ErrInvalidImageURL},
+		{name: "relative", urls: []string{"/images/a.jpg"}, want: ErrInvalidImageURL},
+		{name: "other scheme", urls: []string{"javascript:alert(1)"}, want: ErrInvalidImageURL},
+		{name: "too many", urls: make([]string, maxProductImages+1), want: ErrTooManyImages},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateImageURLs(tt.urls); !errors.Is(err, tt.want) {
+				t.Errorf("validateImageURLs() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
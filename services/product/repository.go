@@ -3,11 +3,20 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
 	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrSkuAlreadyExists 同じ SKU の商品がすでに存在する
+	ErrSkuAlreadyExists = errors.New("a product with the sku already exists")
+	// ErrProductHasMovements 在庫の台帳に記録がある商品は削除できない
+	ErrProductHasMovements = errors.New("product has inventory movements, deactivate it instead")
 )
 
 type ProductRepository struct {
@@ -18,34 +27,65 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (r *ProductRepository) Create(ctx context.Context, product *pb.Product) error {
+// Create 商品を登録する。初期在庫は入荷として台帳に記録し、画像は image_urls の順に登録する
+func (r *ProductRepository) Create(ctx context.Context, product *pb.Product, actor Actor) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO products (id, name, description, price_currency, price_amount, stock_quantity, category, sku, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 	now := time.Now()
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		product.Id,
 		product.Name,
 		product.Description,
 		product.Price.Currency,
 		product.Price.Amount,
-		product.StockQuantity,
+		0,
 		product.Category,
 		product.Sku,
 		product.IsActive,
 		now,
 		now,
 	)
-	return err
+	if isSkuConflict(err) {
+		return ErrSkuAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if product.StockQuantity != 0 {
+		err := changeStock(ctx, tx, &InventoryMovement{
+			ProductID:      product.Id,
+			QuantityChange: product.StockQuantity,
+			Reason:         MovementRestock,
+			Actor:          actor,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	images, err := replaceImages(ctx, tx, product.Id, product.ImageUrls)
+	if err != nil {
+		return err
+	}
+	setImages(product, images)
+
+	return tx.Commit()
 }
 
-func (r *ProductRepository) GetByID(ctx context.Context, id string) (*pb.Product, error) {
-	query := `
-		SELECT id, name, description, price_currency, price_amount, stock_quantity, category, sku, is_active, created_at, updated_at
-		FROM products
-		WHERE id = $1
-	`
+// productColumns 商品を読み込む列。scanProduct と同じ順に並べる
+const productColumns = `id, name, description, price_currency, price_amount, stock_quantity, category, sku, is_active, created_at, updated_at`
+
+// scanProduct productColumns の1行を読み込む。続く列は extra に読み込む
+func scanProduct(row interface{ Scan(dest ...any) error }, extra ...any) (*pb.Product, error) {
 	product := &pb.Product{
 		Price:     &commonpb.Money{},
 		CreatedAt: &commonpb.Timestamp{},
@@ -53,7 +93,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*pb.Product
 	}
 
 	var createdAt, updatedAt time.Time
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	dest := []any{
 		&product.Id,
 		&product.Name,
 		&product.Description,
@@ -65,129 +105,189 @@ func (r *ProductRepository) GetByID(ctx context.Context, id string) (*pb.Product
 		&product.IsActive,
 		&createdAt,
 		&updatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("product not found")
 	}
-	if err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
 	product.CreatedAt.Seconds = createdAt.Unix()
 	product.UpdatedAt.Seconds = updatedAt.Unix()
-
 	return product, nil
 }
 
-func (r *ProductRepository) List(ctx context.Context, page, pageSize int32, category, searchQuery string) ([]*pb.Product, int32, error) {
-	baseQuery := `
-		SELECT id, name, description, price_currency, price_amount, stock_quantity, category, sku, is_active, created_at, updated_at
-		FROM products
-		WHERE 1=1
-	`
-	countQuery := "SELECT COUNT(*) FROM products WHERE 1=1"
-	args := []interface{}{}
-	argIdx := 1
+// queryProducts 商品を読み込み、画像をまとめて読み込んで設定する
+func (r *ProductRepository) queryProducts(ctx context.Context, query string, args ...interface{}) ([]*pb.Product, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*pb.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	if category != "" {
-		baseQuery += fmt.Sprintf(" AND category = $%d", argIdx)
-		countQuery += fmt.Sprintf(" AND category = $%d", argIdx)
-		args = append(args, category)
-		argIdx++
+	if err := r.attachImages(ctx, products); err != nil {
+		return nil, err
 	}
+	return products, nil
+}
 
-	if searchQuery != "" {
-		baseQuery += fmt.Sprintf(" AND name ILIKE $%d", argIdx)
-		countQuery += fmt.Sprintf(" AND name ILIKE $%d", argIdx)
-		args = append(args, "%"+searchQuery+"%")
-		argIdx++
+// attachImages 商品の画像をまとめて読み込んで設定する
+func (r *ProductRepository) attachImages(ctx context.Context, products []*pb.Product) error {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.Id)
+	}
+	images, err := loadImages(ctx, r.db, ids)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		setImages(product, images[product.Id])
 	}
+	return nil
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*pb.Product, error) {
+	products, err := r.queryProducts(ctx, `SELECT `+productColumns+` FROM products WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, ErrProductNotFound
+	}
+	return products[0], nil
+}
+
+// GetBySku SKU で商品を取得する
+func (r *ProductRepository) GetBySku(ctx context.Context, sku string) (*pb.Product, error) {
+	products, err := r.queryProducts(ctx, `SELECT `+productColumns+` FROM products WHERE sku = $1 AND sku <> ''`, sku)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, ErrProductNotFound
+	}
+	return products[0], nil
+}
+
+// ListBySkus SKU で商品をまとめて取得する。存在しない SKU の商品は含まない
+func (r *ProductRepository) ListBySkus(ctx context.Context, skus []string) ([]*pb.Product, error) {
+	return r.queryProducts(ctx, `SELECT `+productColumns+` FROM products WHERE sku = ANY($1) AND sku <> ''`, pq.Array(skus))
+}
+
+// List 商品を新しい順に返す。検索語があれば一致する商品を関連度順に返す
+func (r *ProductRepository) List(ctx context.Context, page, pageSize int32, category, searchQuery string) ([]*pb.Product, int32, error) {
+	f := newProductFilter(category, searchQuery)
 
 	// カウント取得
 	var totalCount int32
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+f.where, f.args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// ページネーション
 	offset := (page - 1) * pageSize
-	baseQuery += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, pageSize, offset)
+	query := `SELECT ` + productColumns + ` FROM products` + f.where + f.orderBy() +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(f.args)+1, len(f.args)+2)
+	args := append(f.args, pageSize, offset)
 
-	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
+	products, err := r.queryProducts(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
-
-	products := []*pb.Product{}
-	for rows.Next() {
-		product := &pb.Product{
-			Price:     &commonpb.Money{},
-			CreatedAt: &commonpb.Timestamp{},
-			UpdatedAt: &commonpb.Timestamp{},
-		}
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(
-			&product.Id,
-			&product.Name,
-			&product.Description,
-			&product.Price.Currency,
-			&product.Price.Amount,
-			&product.StockQuantity,
-			&product.Category,
-			&product.Sku,
-			&product.IsActive,
-			&createdAt,
-			&updatedAt,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		product.CreatedAt.Seconds = createdAt.Unix()
-		product.UpdatedAt.Seconds = updatedAt.Unix()
-		products = append(products, product)
-	}
 
 	return products, totalCount, nil
 }
 
+// Update 商品を更新し、画像は image_urls で置き換える
+// 在庫は UpdateStock と引当でのみ変わるため、product.StockQuantity には現在の在庫を読み戻す
 func (r *ProductRepository) Update(ctx context.Context, product *pb.Product) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE products
 		SET name = $2, description = $3, price_currency = $4, price_amount = $5,
-		    stock_quantity = $6, category = $7, is_active = $8, updated_at = $9
+		    category = $6, sku = $7, is_active = $8, updated_at = $9
 		WHERE id = $1
+		RETURNING stock_quantity
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		product.Id,
 		product.Name,
 		product.Description,
 		product.Price.Currency,
 		product.Price.Amount,
-		product.StockQuantity,
 		product.Category,
+		product.Sku,
 		product.IsActive,
 		time.Now(),
-	)
-	return err
+	).Scan(&product.StockQuantity)
+	if err == sql.ErrNoRows {
+		return ErrProductNotFound
+	}
+	if isSkuConflict(err) {
+		return ErrSkuAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	images, err := replaceImages(ctx, tx, product.Id, product.ImageUrls)
+	if err != nil {
+		return err
+	}
+	setImages(product, images)
+
+	return tx.Commit()
 }
 
 func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 	query := "DELETE FROM products WHERE id = $1"
 	_, err := r.db.ExecContext(ctx, query, id)
+	if isMovementReference(err) {
+		return ErrProductHasMovements
+	}
 	return err
 }
 
-func (r *ProductRepository) UpdateStock(ctx context.Context, productID string, quantityChange int32) error {
-	query := `
-		UPDATE products
-		SET stock_quantity = stock_quantity + $2, updated_at = $3
-		WHERE id = $1
-	`
-	_, err := r.db.ExecContext(ctx, query, productID, quantityChange, time.Now())
-	return err
+// UpdateStock 在庫数を movement.QuantityChange だけ変更し、台帳に記録する
+// 在庫数が確保中の数量を下回る場合は変更せず pkgerrors.ErrInsufficientStock を返す
+func (r *ProductRepository) UpdateStock(ctx context.Context, movement *InventoryMovement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := changeStock(ctx, tx, movement); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isSkuConflict SKU の一意制約違反のエラーか
+func isSkuConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_products_sku"
+}
+
+// isMovementReference 在庫の台帳から参照されている商品を削除しようとしたエラーか
+func isMovementReference(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Table == "inventory_movements"
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+const (
+	// defaultReservationTTL 有効期間が指定されなかった在庫確保の有効期間
+	defaultReservationTTL = 15 * time.Minute
+	// maxReservationTTL 在庫確保の有効期間の上限
+	maxReservationTTL = time.Hour
+	// sweepInterval 期限切れの在庫確保を解放する間隔
+	sweepInterval = 30 * time.Second
+)
+
+var (
+	// ErrProductNotFound 商品が存在しない
+	ErrProductNotFound = errors.New("product not found")
+	// ErrReservationNotFound 注文の在庫確保が存在しない
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationExists 注文の在庫確保がすでに存在する
+	ErrReservationExists = errors.New("reservation already exists for the order")
+	// ErrReservationClosed 在庫確保が確定・解放済みで変更できない
+	ErrReservationClosed = errors.New("reservation is already closed")
+)
+
+// ReservationStatus 在庫確保の状態
+type ReservationStatus string
+
+const (
+	ReservationHeld      ReservationStatus = "held"      // 確保中
+	ReservationCommitted ReservationStatus = "committed" // 確定済み
+	ReservationReleased  ReservationStatus = "released"  // 解放済み
+	ReservationExpired   ReservationStatus = "expired"   // 期限切れで解放済み
+)
+
+// StockHold 1商品分の確保数量
+type StockHold struct {
+	ProductID string
+	Quantity  int32
+}
+
+// Reservation 注文に対する在庫確保
+// 注文ごとに1つで、確定・解放されるか期限切れになるまで在庫を確保する
+type Reservation struct {
+	OrderID   string
+	Status    ReservationStatus
+	Items     []StockHold
+	ExpiresAt time.Time
+}
+
+// mergeHolds 同じ商品の確保をまとめ、商品ID順に並べる
+// 商品の行を常に同じ順でロックし、並行する確保同士のデッドロックを防ぐ
+func mergeHolds(items []StockHold) []StockHold {
+	quantities := make(map[string]int32, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	merged := make([]StockHold, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, StockHold{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+	return merged
+}
+
+// sweepLoop 期限切れの在庫確保を定期的に解放する
+func sweepLoop(reservations *ReservationRepository) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		released, err := reservations.ReleaseExpired(context.Background(), time.Now())
+		if err != nil {
+			log.Printf("Failed to release expired reservations: %v", err)
+			continue
+		}
+		if released > 0 {
+			log.Printf("Released %d expired reservations", released)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ReservationRepository stock_reservations テーブルと商品の確保数量を扱う
+// 販売可能数（在庫数 - 確保数量）の確認と確保を1つの UPDATE で行い、並行する確保でも売り越さない
+type ReservationRepository struct {
+	db *sql.DB
+}
+
+func NewReservationRepository(db *sql.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// StockLevel 商品の在庫数と確保中の数量を返す
+func (r *ReservationRepository) StockLevel(ctx context.Context, productID string) (onHand, reserved int32, err error) {
+	query := `SELECT stock_quantity, reserved_quantity FROM products WHERE id = $1`
+
+	err = r.db.QueryRowContext(ctx, query, productID).Scan(&onHand, &reserved)
+	if err == sql.ErrNoRows {
+		return 0, 0, ErrProductNotFound
+	}
+	return onHand, reserved, err
+}
+
+// Reserve 注文のために全商品の在庫を確保する。1商品でも足りなければ何も確保しない
+// 足りない商品があれば pkgerrors.ErrInsufficientStock、存在しない商品があれば ErrProductNotFound、
+// 同じ注文の確保がすでにあれば ErrReservationExists を返す
+func (r *ReservationRepository) Reserve(ctx context.Context, orderID string, items []StockHold, expiresAt time.Time) (*Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	holds := mergeHolds(items)
+
+	for _, hold := range holds {
+		// 販売可能数が足りる場合のみ確保数量を増やす。行ロックにより並行する確保は順に評価される
+		result, err := tx.ExecContext(ctx, `
+			UPDATE products
+			SET reserved_quantity = reserved_quantity + $2, updated_at = $3
+			WHERE id = $1 AND stock_quantity - reserved_quantity >= $2
+		`, hold.ProductID, hold.Quantity, now)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rows == 0 {
+			return nil, stockShortage(ctx, tx, hold.ProductID)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stock_reservations (order_id, product_id, quantity, status, expires_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, orderID, hold.ProductID, hold.Quantity, string(ReservationHeld), expiresAt, now, now)
+		if isUniqueViolation(err) {
+			return nil, ErrReservationExists
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrReservationExists
+		}
+		return nil, err
+	}
+
+	return &Reservation{
+		OrderID:   orderID,
+		Status:    ReservationHeld,
+		Items:     holds,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Commit 確保中の在庫を確定し、在庫数から差し引いて販売として台帳に記録する
+// 期限を過ぎていても期限切れとして解放される前であれば確定できる
+// 確定済みの場合はそのまま返し、解放済みの場合は ErrReservationClosed を返す
+func (r *ReservationRepository) Commit(ctx context.Context, orderID string, actor Actor) (*Reservation, error) {
+	return r.close(ctx, orderID, ReservationCommitted, func(tx *sql.Tx, hold StockHold, now time.Time) error {
+		movement := &InventoryMovement{
+			ProductID:      hold.ProductID,
+			QuantityChange: -hold.Quantity,
+			Reason:         MovementSale,
+			ReferenceID:    orderID,
+			Actor:          actor,
+		}
+		err := tx.QueryRowContext(ctx, `
+			UPDATE products
+			SET stock_quantity = stock_quantity - $2, reserved_quantity = reserved_quantity - $2, updated_at = $3
+			WHERE id = $1
+			RETURNING stock_quantity
+		`, hold.ProductID, hold.Quantity, now).Scan(&movement.StockAfter)
+		if err != nil {
+			return err
+		}
+		return recordMovement(ctx, tx, movement)
+	})
+}
+
+// Release 確保中の在庫を解放する。在庫数は変わらないため台帳には記録しない
+// 解放済み・期限切れの場合はそのまま返し、確定済みの場合は ErrReservationClosed を返す
+func (r *ReservationRepository) Release(ctx context.Context, orderID string) (*Reservation, error) {
+	return r.close(ctx, orderID, ReservationReleased, func(tx *sql.Tx, hold StockHold, now time.Time) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE products
+			SET reserved_quantity = reserved_quantity - $2, updated_at = $3
+			WHERE id = $1
+		`, hold.ProductID, hold.Quantity, now)
+		return err
+	})
+}
+
+// close 確保中の在庫確保を to の状態にし、商品ごとに apply で確保数量を戻す
+func (r *ReservationRepository) close(ctx context.Context, orderID string, to ReservationStatus, apply func(tx *sql.Tx, hold StockHold, now time.Time) error) (*Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 確保をロックし、同じ注文への確定・解放や期限切れの解放と重ならないようにする
+	reservation, err := getReservation(ctx, tx, orderID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case ReservationHeld:
+	case to, ReservationExpired:
+		// 再試行。期限切れは解放済みと同じ扱い
+		if to == ReservationReleased || reservation.Status == to {
+			return reservation, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrReservationClosed, reservation.Status)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrReservationClosed, reservation.Status)
+	}
+
+	now := time.Now()
+	for _, hold := range reservation.Items {
+		if err := apply(tx, hold, now); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stock_reservations SET status = $2, updated_at = $3 WHERE order_id = $1
+	`, orderID, string(to), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	reservation.Status = to
+	return reservation, nil
+}
+
+// ReleaseExpired now までに期限を過ぎた確保中の在庫を解放し、解放した注文の数を返す
+func (r *ReservationRepository) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	// 期限切れにした確保の数量を商品ごとに合計して確保数量から差し引く
+	query := `
+		WITH expired AS (
+			UPDATE stock_reservations
+			SET status = $2, updated_at = $1
+			WHERE status = $3 AND expires_at <= $1
+			RETURNING order_id, product_id, quantity
+		), totals AS (
+			SELECT product_id, SUM(quantity) AS quantity FROM expired GROUP BY product_id
+		), released AS (
+			UPDATE products p
+			SET reserved_quantity = p.reserved_quantity - t.quantity, updated_at = $1
+			FROM totals t
+			WHERE p.id = t.product_id
+		)
+		SELECT COUNT(DISTINCT order_id) FROM expired
+	`
+	var released int
+	err := r.db.QueryRowContext(ctx, query, now, string(ReservationExpired), string(ReservationHeld)).Scan(&released)
+	return released, err
+}
+
+// Get 注文の在庫確保を返す
+func (r *ReservationRepository) Get(ctx context.Context, orderID string) (*Reservation, error) {
+	return getReservation(ctx, r.db, orderID, false)
+}
+
+// queryer *sql.DB と *sql.Tx の共通部分
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// getReservation 注文の在庫確保を読み込む。forUpdate の場合は行をロックする
+func getReservation(ctx context.Context, q queryer, orderID string, forUpdate bool) (*Reservation, error) {
+	query := `
+		SELECT product_id, quantity, status, expires_at
+		FROM stock_reservations
+		WHERE order_id = $1
+		ORDER BY product_id
+	`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservation := &Reservation{OrderID: orderID}
+	for rows.Next() {
+		var hold StockHold
+		var status string
+		if err := rows.Scan(&hold.ProductID, &hold.Quantity, &status, &reservation.ExpiresAt); err != nil {
+			return nil, err
+		}
+		reservation.Status = ReservationStatus(status)
+		reservation.Items = append(reservation.Items, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(reservation.Items) == 0 {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// isUniqueViolation 一意制約違反のエラーか
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// openTestDB TEST_DATABASE_URL のデータベースに scripts/init.sql を適用して返す
+// 設定されていなければテストをスキップする
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("../../scripts/init.sql")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	return db
+}
+
+// createTestProduct 在庫数 stock の商品を作成してIDを返す
+func createTestProduct(t *testing.T, db *sql.DB, stock int32) string {
+	t.Helper()
+
+	id := uuid.New().String()
+	_, err := db.Exec(`
+		INSERT INTO products (id, name, price_currency, price_amount, stock_quantity, category, sku, is_active, created_at, updated_at)
+		VALUES ($1, 'Test product', 'JPY', 1000, $2, 'test', $1, true, NOW(), NOW())
+	`, id, stock)
+	if err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	return id
+}
+
+func assertStockLevel(t *testing.T, reservations *ReservationRepository, productID string, wantOnHand, wantReserved int32) {
+	t.Helper()
+
+	onHand, reserved, err := reservations.StockLevel(context.Background(), productID)
+	if err != nil {
+		t.Fatalf("StockLevel: %v", err)
+	}
+	if onHand != wantOnHand || reserved != wantReserved {
+		t.Errorf("stock = %d on hand, %d reserved, want %d on hand, %d reserved", onHand, reserved, wantOnHand, wantReserved)
+	}
+}
+
+func TestReserveStock_NoOversell(t *testing.T) {
+	db := openTestDB(t)
+	reservations := NewReservationRepository(db)
+	productID := createTestProduct(t, db, 10)
+
+	const orders = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := reservations.Reserve(context.Background(), uuid.New().String(),
+				[]StockHold{{ProductID: productID, Quantity: 1}}, time.Now().Add(time.Minute))
+			if err != nil && !errors.Is(err, pkgerrors.ErrInsufficientStock) {
+				t.Errorf("Reserve: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 10 {
+		t.Errorf("%d reservations succeeded, want 10", succeeded)
+	}
+	assertStockLevel(t, reservations, productID, 10, 10)
+}
+
+func TestReserveStock_NoOversellAcrossProducts(t *testing.T) {
+	db := openTestDB(t)
+	reservations := NewReservationRepository(db)
+	first := createTestProduct(t, db, 5)
+	second := createTestProduct(t, db, 5)
+
+	// 商品の順番を入れ替えた注文を並行して確保してもデッドロックせず、どちらの商品も売り越さない
+	const orders = 20
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		items := []StockHold{{ProductID: first, Quantity: 1}, {ProductID: second, Quantity: 1}}
+		if i%2 == 0 {
+			items[0], items[1] = items[1], items[0]
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := reservations.Reserve(context.Background(), uuid.New().String(), items, time.Now().Add(time.Minute))
+			if err != nil && !errors.Is(err, pkgerrors.ErrInsufficientStock) {
+				t.Errorf("Reserve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertStockLevel(t, reservations, first, 5, 5)
+	assertStockLevel(t, reservations, second, 5, 5)
+}
+
+func TestReserveStock_AllOrNothing(t *testing.T) {
+	db := openTestDB(t)
+	reservations := NewReservationRepository(db)
+	plenty := createTestProduct(t, db, 10)
+	scarce := createTestProduct(t, db, 1)
+
+	_, err := reservations.Reserve(context.Background(), uuid.New().String(),
+		[]StockHold{{ProductID: plenty, Quantity: 3}, {ProductID: scarce, Quantity: 2}}, time.Now().Add(time.Minute))
+	if !errors.Is(err, pkgerrors.ErrInsufficientStock) {
+		t.Fatalf("Reserve error = %v, want pkgerrors.ErrInsufficientStock", err)
+	}
+
+	assertStockLevel(t, reservations, plenty, 10, 0)
+	assertStockLevel(t, reservations, scarce, 1, 0)
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+	productID := createTestProduct(t, db, 10)
+
+	reserve := &pb.ReserveStockRequest{
+		OrderId: uuid.New().String(),
+		Items: []*pb.StockHold{
+			{ProductId: productID, Quantity: 2},
+			{ProductId: productID, Quantity: 1},
+		},
+	}
+	reservation, err := server.ReserveStock(ctx, reserve)
+	if err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+	if reservation.Status != pb.ReservationStatus_RESERVATION_STATUS_HELD {
+		t.Errorf("status = %v, want HELD", reservation.Status)
+	}
+	if len(reservation.Items) != 1 || reservation.Items[0].Quantity != 3 {
+		t.Errorf("items = %v, want one hold of 3", reservation.Items)
+	}
+
+	// 再試行しても二重に確保しない
+	if _, err := server.ReserveStock(ctx, reserve); err != nil {
+		t.Fatalf("ReserveStock retry: %v", err)
+	}
+
+	stock, err := server.CheckStock(ctx, &pb.CheckStockRequest{ProductId: productID, RequiredQuantity: 8})
+	if err != nil {
+		t.Fatalf("CheckStock: %v", err)
+	}
+	if stock.CurrentStock != 10 || stock.ReservedQuantity != 3 || stock.AvailableQuantity != 7 || stock.Available {
+		t.Errorf("CheckStock = %+v, want 10 on hand, 3 reserved, 7 available", stock)
+	}
+
+	commit := &pb.CommitReservationRequest{OrderId: reserve.OrderId}
+	for i := 0; i < 2; i++ {
+		committed, err := server.CommitReservation(ctx, commit)
+		if err != nil {
+			t.Fatalf("CommitReservation: %v", err)
+		}
+		if committed.Status != pb.ReservationStatus_RESERVATION_STATUS_COMMITTED {
+			t.Errorf("status = %v, want COMMITTED", committed.Status)
+		}
+	}
+
+	stock, err = server.CheckStock(ctx, &pb.CheckStockRequest{ProductId: productID, RequiredQuantity: 7})
+	if err != nil {
+		t.Fatalf("CheckStock: %v", err)
+	}
+	if stock.CurrentStock != 7 || stock.ReservedQuantity != 0 || !stock.Available {
+		t.Errorf("CheckStock = %+v, want 7 on hand, 0 reserved", stock)
+	}
+
+	_, err = server.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{OrderId: reserve.OrderId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ReleaseReservation after commit = %v, want FailedPrecondition", err)
+	}
+	_, err = server.ReserveStock(ctx, reserve)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ReserveStock after commit = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestReleaseReservation(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+	productID := createTestProduct(t, db, 5)
+	orderID := uuid.New().String()
+
+	_, err := server.ReserveStock(ctx, &pb.ReserveStockRequest{
+		OrderId: orderID,
+		Items:   []*pb.StockHold{{ProductId: productID, Quantity: 5}},
+	})
+	if err != nil {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		released, err := server.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{OrderId: orderID})
+		if err != nil {
+			t.Fatalf("ReleaseReservation: %v", err)
+		}
+		if released.Status != pb.ReservationStatus_RESERVATION_STATUS_RELEASED {
+			t.Errorf("status = %v, want RELEASED", released.Status)
+		}
+	}
+	assertStockLevel(t, server.reservations, productID, 5, 0)
+
+	_, err = server.CommitReservation(ctx, &pb.CommitReservationRequest{OrderId: orderID})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CommitReservation after release = %v, want FailedPrecondition", err)
+	}
+	_, err = server.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{OrderId: uuid.New().String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ReleaseReservation of unknown order = %v, want NotFound", err)
+	}
+}
+
+func TestReleaseExpired(t *testing.T) {
+	db := openTestDB(t)
+	reservations := NewReservationRepository(db)
+	ctx := context.Background()
+	productID := createTestProduct(t, db, 5)
+	now := time.Now()
+
+	expiring := uuid.New().String()
+	if _, err := reservations.Reserve(ctx, expiring, []StockHold{{ProductID: productID, Quantity: 2}}, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	active := uuid.New().String()
+	if _, err := reservations.Reserve(ctx, active, []StockHold{{ProductID: productID, Quantity: 1}}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	// 期限前の確保は解放しない
+	if _, err := reservations.ReleaseExpired(ctx, now); err != nil {
+		t.Fatalf("ReleaseExpired: %v", err)
+	}
+	assertStockLevel(t, reservations, productID, 5, 3)
+
+	if _, err := reservations.ReleaseExpired(ctx, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("ReleaseExpired: %v", err)
+	}
+	assertStockLevel(t, reservations, productID, 5, 1)
+
+	reservation, err := reservations.Get(ctx, expiring)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if reservation.Status != ReservationExpired {
+		t.Errorf("status = %s, want expired", reservation.Status)
+	}
+	if _, err := reservations.Commit(ctx, expiring, Actor{}); !errors.Is(err, ErrReservationClosed) {
+		t.Errorf("Commit of expired reservation error = %v, want ErrReservationClosed", err)
+	}
+	if _, err := reservations.Commit(ctx, active, Actor{}); err != nil {
+		t.Errorf("Commit of active reservation: %v", err)
+	}
+	assertStockLevel(t, reservations, productID, 4, 0)
+}
+
+func TestReserveStock_Validation(t *testing.T) {
+	server := NewProductServer(nil, nil)
+
+	tests := []struct {
+		name string
+		req  *pb.ReserveStockRequest
+	}{
+		{
+			name: "order id missing",
+			req:  &pb.ReserveStockRequest{Items: []*pb.StockHold{{ProductId: "p1", Quantity: 1}}},
+		},
+		{
+			name: "no items",
+			req:  &pb.ReserveStockRequest{OrderId: "o1"},
+		},
+		{
+			name: "product id missing",
+			req:  &pb.ReserveStockRequest{OrderId: "o1", Items: []*pb.StockHold{{Quantity: 1}}},
+		},
+		{
+			name: "non-positive quantity",
+			req:  &pb.ReserveStockRequest{OrderId: "o1", Items: []*pb.StockHold{{ProductId: "p1"}}},
+		},
+		{
+			name: "negative ttl",
+			req:  &pb.ReserveStockRequest{OrderId: "o1", Items: []*pb.StockHold{{ProductId: "p1", Quantity: 1}}, TtlSeconds: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.ReserveStock(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ReserveStock error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestMergeHolds(t *testing.T) {
+	merged := mergeHolds([]StockHold{
+		{ProductID: "b", Quantity: 1},
+		{ProductID: "a", Quantity: 2},
+		{ProductID: "b", Quantity: 3},
+	})
+
+	want := []StockHold{{ProductID: "a", Quantity: 2}, {ProductID: "b", Quantity: 4}}
+	if len(merged) != len(want) {
+		t.Fatalf("mergeHolds = %v, want %v", merged, want)
+	}
+	for i := range want {
+		if merged[i] != want[i] {
+			t.Errorf("mergeHolds = %v, want %v", merged, want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+)
+
+const (
+	// maxSearchQueryLength 検索語の最大長
+	maxSearchQueryLength = 200
+	// searchTermsRefreshInterval 修正候補に使う語の一覧を更新する間隔
+	searchTermsRefreshInterval = 5 * time.Minute
+	// snippetLength 部分一致で作る説明の抜粋の文字数
+	snippetLength = 80
+
+	highlightStart = "<mark>"
+	highlightStop  = "</mark>"
+	// headlineOptions ts_headline で一致した語を囲む
+	headlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+)
+
+// SearchHit 検索に一致した商品
+// HighlightedName と Snippet は HTML エスケープ済みで、一致した語を <mark> で囲む
+type SearchHit struct {
+	Product         *pb.Product
+	Rank            float64
+	HighlightedName string
+	Snippet         string
+}
+
+// productFilter 商品一覧と検索の絞り込み条件
+type productFilter struct {
+	where   string
+	args    []interface{}
+	tsquery string // 検索語の tsquery の式。検索語がなければ空
+	rank    string // 関連度の式。検索語がなければ空
+}
+
+// newProductFilter カテゴリと検索語の条件を作る
+// 検索語は全文検索のほか、単語に分かれない日本語のために商品名・SKU・説明の部分一致でも探す
+func newProductFilter(category, searchQuery string) productFilter {
+	f := productFilter{where: " WHERE 1=1"}
+
+	if category != "" {
+		f.args = append(f.args, category)
+		f.where += fmt.Sprintf(" AND category = $%d", len(f.args))
+	}
+
+	if searchQuery != "" {
+		f.args = append(f.args, searchQuery, "%"+escapeLike(searchQuery)+"%")
+		query, pattern := len(f.args)-1, len(f.args)
+		f.tsquery = fmt.Sprintf("websearch_to_tsquery('simple', $%d)", query)
+		f.where += fmt.Sprintf(" AND (search_vector @@ %s OR name ILIKE $%d OR sku ILIKE $%d OR description ILIKE $%d)",
+			f.tsquery, pattern, pattern, pattern)
+		f.rank = fmt.Sprintf("ts_rank(search_vector, %s) + similarity(name, $%d)", f.tsquery, query)
+	}
+
+	return f
+}
+
+// orderBy 検索語があれば関連度順、なければ新しい順に並べる
+func (f productFilter) orderBy() string {
+	if f.rank == "" {
+		return " ORDER BY created_at DESC"
+	}
+	return " ORDER BY " + f.rank + " DESC, created_at DESC"
+}
+
+// escapeLike LIKE のワイルドカードをエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Search 検索語に一致する商品を関連度順に返す。searchQuery は空でないこと
+func (r *ProductRepository) Search(ctx context.Context, page, pageSize int32, category, searchQuery string) ([]*SearchHit, int32, error) {
+	f := newProductFilter(category, searchQuery)
+
+	var totalCount int32
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+f.where, f.args...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	// 抜粋はページ内の商品だけ作る
+	offset := (page - 1) * pageSize
+	query := fmt.Sprintf(`
+		SELECT %[1]s, rank,
+		       ts_headline('simple', name, %[2]s, '%[3]s, HighlightAll=true'),
+		       ts_headline('simple', description, %[2]s, '%[3]s, MaxFragments=2, MaxWords=20, MinWords=5')
+		FROM (
+			SELECT *, %[4]s AS rank FROM products%[5]s%[6]s LIMIT $%[7]d OFFSET $%[8]d
+		) AS page
+		ORDER BY rank DESC, created_at DESC
+	`, productColumns, f.tsquery, headlineOptions, f.rank, f.where, f.orderBy(), len(f.args)+1, len(f.args)+2)
+	args := append(f.args, pageSize, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	hits := []*SearchHit{}
+	for rows.Next() {
+		hit := &SearchHit{}
+		var name, snippet string
+		hit.Product, err = scanProduct(rows, &hit.Rank, &name, &snippet)
+		if err != nil {
+			return nil, 0, err
+		}
+		hit.HighlightedName = highlight(name, hit.Product.Name, searchQuery, 0)
+		hit.Snippet = highlight(snippet, hit.Product.Description, searchQuery, snippetLength)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	products := make([]*pb.Product, 0, len(hits))
+	for _, hit := range hits {
+		products = append(products, hit.Product)
+	}
+	if err := r.attachImages(ctx, products); err != nil {
+		return nil, 0, err
+	}
+
+	return hits, totalCount, nil
+}
+
+// highlight ts_headline の結果を HTML エスケープする
+// 全文検索で一致した語がなければ、部分一致した箇所を text から抜き出して囲む
+func highlight(headline, text, searchQuery string, maxLength int) string {
+	if strings.Contains(headline, highlightStart) {
+		escaped := html.EscapeString(headline)
+		return strings.NewReplacer(
+			html.EscapeString(highlightStart), highlightStart,
+			html.EscapeString(highlightStop), highlightStop,
+		).Replace(escaped)
+	}
+
+	runes, query := []rune(text), []rune(searchQuery)
+	start := indexFold(runes, query, 0)
+	if start < 0 {
+		return html.EscapeString(headline)
+	}
+
+	// 最初に一致した箇所が抜粋の中央に来るようにする
+	from, to := 0, len(runes)
+	if maxLength > 0 && len(runes) > maxLength {
+		from = max(0, min(start-(maxLength-len(query))/2, len(runes)-maxLength))
+		to = from + maxLength
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	for i := from; i < to; {
+		match := indexFold(runes[:to], query, i)
+		if match < 0 {
+			b.WriteString(html.EscapeString(string(runes[i:to])))
+			break
+		}
+		b.WriteString(html.EscapeString(string(runes[i:match])))
+		b.WriteString(highlightStart + html.EscapeString(string(runes[match:match+len(query)])) + highlightStop)
+		i = match + len(query)
+	}
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// indexFold from 以降で query が大文字小文字を区別せずに最初に現れる位置を返す。なければ -1
+func indexFold(runes, query []rune, from int) int {
+	if len(query) == 0 {
+		return -1
+	}
+	for i := from; i+len(query) <= len(runes); i++ {
+		matched := true
+		for j, q := range query {
+			if unicode.ToLower(runes[i+j]) != unicode.ToLower(q) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// Suggest 検索語の各語を商品に含まれる最も近い語に置き換えた修正候補を返す
+// 置き換える語がなければ空文字を返す
+func (r *ProductRepository) Suggest(ctx context.Context, searchQuery string) (string, error) {
+	words := strings.Fields(strings.ToLower(searchQuery))
+	corrected := false
+
+	for i, word := range words {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if word == "" {
+			continue
+		}
+
+		// % は pg_trgm の類似度が similarity_threshold（既定 0.3）以上の語に一致する
+		var term string
+		err := r.db.QueryRowContext(ctx, `
+			SELECT word FROM product_search_terms
+			WHERE word % $1
+			ORDER BY word = $1 DESC, similarity(word, $1) DESC, ndoc DESC, word
+			LIMIT 1
+		`, word).Scan(&term)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if term != word {
+			words[i] = term
+			corrected = true
+		}
+	}
+
+	if !corrected {
+		return "", nil
+	}
+	return strings.Join(words, " "), nil
+}
+
+// RefreshSearchTerms 修正候補に使う語の一覧を商品から作り直す
+func (r *ProductRepository) RefreshSearchTerms(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY product_search_terms`)
+	return err
+}
+
+// refreshSearchTermsLoop 修正候補に使う語の一覧を定期的に更新する
+func refreshSearchTermsLoop(repo *ProductRepository) {
+	ticker := time.NewTicker(searchTermsRefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := repo.RefreshSearchTerms(context.Background()); err != nil {
+			log.Printf("Failed to refresh search terms: %v", err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// searchToken 他の商品に含まれない英字だけの語を返す
+func searchToken() string {
+	return "qz" + strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return 'g' + (r - '0')
+		}
+		return r
+	}, strings.ReplaceAll(uuid.New().String(), "-", "")[:10])
+}
+
+func findHit(hits []*pb.ProductSearchHit, productID string) (int, *pb.ProductSearchHit) {
+	for i, hit := range hits {
+		if hit.Product.Id == productID {
+			return i, hit
+		}
+	}
+	return -1, nil
+}
+
+func TestSearchProducts(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductRepository(db)
+	server := NewProductServer(repo, NewReservationRepository(db))
+	ctx := context.Background()
+
+	token := searchToken()
+	create := func(name, description string) *pb.Product {
+		t.Helper()
+		product, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+			Name:        name,
+			Description: description,
+			Price:       &commonpb.Money{Currency: "JPY", Amount: 1000},
+			Category:    "kitchen",
+		})
+		if err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+		return product
+	}
+
+	mug := create("Ceramic "+token+" <Mug>", "A sturdy mug for coffee")
+	bottle := create("Travel Bottle", "Keeps drinks cold, unlike the "+token+" mug")
+	cup := create("陶器マグカップ", "電子レンジで使える陶器のマグカップです")
+
+	resp, err := server.SearchProducts(ctx, &pb.SearchProductsRequest{
+		Query:      token,
+		Pagination: &commonpb.Pagination{PageSize: 100},
+	})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if resp.Pagination.TotalCount != 2 {
+		t.Fatalf("total = %d, want 2", resp.Pagination.TotalCount)
+	}
+
+	// 商品名の一致は説明の一致より上に並ぶ
+	if resp.Hits[0].Product.Id != mug.Id || resp.Hits[1].Product.Id != bottle.Id {
+		t.Errorf("hits = %s, %s, want the mug before the bottle", resp.Hits[0].Product.Name, resp.Hits[1].Product.Name)
+	}
+	if resp.Hits[0].Rank <= resp.Hits[1].Rank {
+		t.Errorf("ranks = %v, %v, want descending", resp.Hits[0].Rank, resp.Hits[1].Rank)
+	}
+	if want := "Ceramic <mark>" + token + "</mark> &lt;Mug&gt;"; resp.Hits[0].HighlightedName != want {
+		t.Errorf("highlighted name = %q, want %q", resp.Hits[0].HighlightedName, want)
+	}
+	if !strings.Contains(resp.Hits[1].Snippet, "<mark>"+token+"</mark>") {
+		t.Errorf("snippet = %q, want the token highlighted", resp.Hits[1].Snippet)
+	}
+
+	// 日本語は部分一致で探す
+	resp, err = server.SearchProducts(ctx, &pb.SearchProductsRequest{
+		Query:      "マグカップ",
+		Category:   "kitchen",
+		Pagination: &commonpb.Pagination{PageSize: 100},
+	})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	_, hit := findHit(resp.Hits, cup.Id)
+	if hit == nil {
+		t.Fatalf("hits = %v, want the cup", resp.Hits)
+	}
+	if hit.HighlightedName != "陶器<mark>マグカップ</mark>" {
+		t.Errorf("highlighted name = %q", hit.HighlightedName)
+	}
+	if !strings.Contains(hit.Snippet, "<mark>マグカップ</mark>") {
+		t.Errorf("snippet = %q, want the match highlighted", hit.Snippet)
+	}
+
+	list, err := server.ListProducts(ctx, &pb.ListProductsRequest{
+		Pagination:  &commonpb.Pagination{PageSize: 100},
+		SearchQuery: token + " coffee",
+	})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if len(list.Products) != 1 || list.Products[0].Id != mug.Id {
+		t.Errorf("ListProducts = %v, want the mug", list.Products)
+	}
+
+	// 一致する商品がなければ、商品に含まれる近い語を提案する
+	if err := repo.RefreshSearchTerms(ctx); err != nil {
+		t.Fatalf("RefreshSearchTerms: %v", err)
+	}
+	misspelled := token[:len(token)-1] + "x"
+	resp, err = server.SearchProducts(ctx, &pb.SearchProductsRequest{Query: misspelled + " Mug"})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if len(resp.Hits) != 0 {
+		t.Fatalf("hits = %v, want none", resp.Hits)
+	}
+	if want := token + " mug"; resp.Suggestion != want {
+		t.Errorf("suggestion = %q, want %q", resp.Suggestion, want)
+	}
+}
+
+func TestSearchProducts_Validation(t *testing.T) {
+	server := NewProductServer(nil, nil)
+	ctx := context.Background()
+
+	if _, err := server.SearchProducts(ctx, &pb.SearchProductsRequest{Query: "  "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SearchProducts with an empty query = %v, want InvalidArgument", err)
+	}
+	long := strings.Repeat("a", maxSearchQueryLength+1)
+	if _, err := server.SearchProducts(ctx, &pb.SearchProductsRequest{Query: long}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SearchProducts with a long query = %v, want InvalidArgument", err)
+	}
+	if _, err := server.ListProducts(ctx, &pb.ListProductsRequest{SearchQuery: long}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListProducts with a long query = %v, want InvalidArgument", err)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		headline  string
+		text      string
+		query     string
+		maxLength int
+		want      string
+	}{
+		{
+			name:     "full-text match",
+			headline: "Blue <mark>Mug</mark> & <b>Saucer</b>",
+			text:     "Blue Mug & <b>Saucer</b>",
+			query:    "mug",
+			want:     "Blue <mark>Mug</mark> &amp; &lt;b&gt;Saucer&lt;/b&gt;",
+		},
+		{
+			name:     "substring match",
+			headline: "青いマグとマグの<蓋>",
+			text:     "青いマグとマグの<蓋>",
+			query:    "マグ",
+			want:     "青い<mark>マグ</mark>と<mark>マグ</mark>の&lt;蓋&gt;",
+		},
+		{
+			name:     "case-insensitive substring match",
+			headline: "SKU MUG-350",
+			text:     "SKU MUG-350",
+			query:    "mug-3",
+			want:     "SKU <mark>MUG-3</mark>50",
+		},
+		{
+			name:      "substring match in a long text",
+			headline:  "あいうえおかきくけこ",
+			text:      "あいうえおかきくけこさしすせそたちつてと",
+			query:     "さし",
+			maxLength: 6,
+			want:      "…けこ<mark>さし</mark>すせ…",
+		},
+		{
+			name:     "no match",
+			headline: "A <plain> description",
+			text:     "A <plain> description",
+			query:    "mug",
+			want:     "A &lt;plain&gt; description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.headline, tt.text, tt.query, tt.maxLength); got != tt.want {
+				t.Errorf("highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got := escapeLike(`100%_off\`); got != `100\%\_off\\` {
+		t.Errorf("escapeLike() = %q", got)
+	}
+}
@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	pkgerrors "github.com/Riku-KANO/kube-ec/pkg/errors"
 	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
 	pb "github.com/Riku-KANO/kube-ec/proto/product"
 	"github.com/google/uuid"
@@ -11,14 +16,23 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// maxSkuLength SKU の最大長
+	maxSkuLength = 100
+	// maxBatchSkus BatchGetProductsBySku で一度に取得できる SKU の数
+	maxBatchSkus = 100
+)
+
 type ProductServer struct {
 	pb.UnimplementedProductServiceServer
-	repo *ProductRepository
+	repo         *ProductRepository
+	reservations *ReservationRepository
 }
 
-func NewProductServer(repo *ProductRepository) *ProductServer {
+func NewProductServer(repo *ProductRepository, reservations *ReservationRepository) *ProductServer {
 	return &ProductServer{
-		repo: repo,
+		repo:         repo,
+		reservations: reservations,
 	}
 }
 
@@ -29,6 +43,16 @@ func (s *ProductServer) CreateProduct(ctx context.Context, req *pb.CreateProduct
 	if req.Price == nil || req.Price.Amount <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "valid price is required")
 	}
+	if req.StockQuantity < 0 {
+		return nil, status.Error(codes.InvalidArgument, "stock_quantity must not be negative")
+	}
+	if err := validateImageURLs(req.ImageUrls); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sku, err := normalizeSku(req.Sku)
+	if err != nil {
+		return nil, err
+	}
 
 	product := &pb.Product{
 		Id:            uuid.New().String(),
@@ -38,13 +62,17 @@ func (s *ProductServer) CreateProduct(ctx context.Context, req *pb.CreateProduct
 		StockQuantity: req.StockQuantity,
 		Category:      req.Category,
 		ImageUrls:     req.ImageUrls,
-		Sku:           req.Sku,
+		Sku:           sku,
 		IsActive:      true,
 		CreatedAt:     &commonpb.Timestamp{},
 		UpdatedAt:     &commonpb.Timestamp{},
 	}
 
-	if err := s.repo.Create(ctx, product); err != nil {
+	err = s.repo.Create(ctx, product, actorFromContext(ctx))
+	if errors.Is(err, ErrSkuAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to create product: %v", err))
 	}
 
@@ -64,9 +92,81 @@ func (s *ProductServer) GetProduct(ctx context.Context, req *pb.GetProductReques
 	return product, nil
 }
 
-func (s *ProductServer) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
-	page := req.Pagination.Page
-	pageSize := req.Pagination.PageSize
+// GetProductBySku 倉庫・基幹システム連携用に SKU で商品を取得する
+func (s *ProductServer) GetProductBySku(ctx context.Context, req *pb.GetProductBySkuRequest) (*pb.Product, error) {
+	sku, err := normalizeSku(req.Sku)
+	if err != nil {
+		return nil, err
+	}
+	if sku == "" {
+		return nil, status.Error(codes.InvalidArgument, "sku is required")
+	}
+
+	product, err := s.repo.GetBySku(ctx, sku)
+	if errors.Is(err, ErrProductNotFound) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get product: %v", err))
+	}
+
+	return product, nil
+}
+
+// BatchGetProductsBySku SKU で商品をまとめて取得する
+// 商品は指定された順に返し、存在しない SKU は missing_skus で返す
+func (s *ProductServer) BatchGetProductsBySku(ctx context.Context, req *pb.BatchGetProductsBySkuRequest) (*pb.BatchGetProductsBySkuResponse, error) {
+	if len(req.Skus) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one sku is required")
+	}
+	if len(req.Skus) > maxBatchSkus {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d skus can be requested at once", maxBatchSkus)
+	}
+
+	skus := make([]string, 0, len(req.Skus))
+	for _, raw := range req.Skus {
+		sku, err := normalizeSku(raw)
+		if err != nil {
+			return nil, err
+		}
+		if sku == "" {
+			return nil, status.Error(codes.InvalidArgument, "sku must not be empty")
+		}
+		skus = append(skus, sku)
+	}
+
+	products, err := s.repo.ListBySkus(ctx, skus)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get products: %v", err))
+	}
+
+	bySku := make(map[string]*pb.Product, len(products))
+	for _, product := range products {
+		bySku[product.Sku] = product
+	}
+
+	resp := &pb.BatchGetProductsBySkuResponse{}
+	seen := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		if seen[sku] {
+			continue
+		}
+		seen[sku] = true
+
+		if product, ok := bySku[sku]; ok {
+			resp.Products = append(resp.Products, product)
+		} else {
+			resp.MissingSkus = append(resp.MissingSkus, sku)
+		}
+	}
+
+	return resp, nil
+}
+
+// pageParams ページ番号とページサイズを既定値と上限に揃える
+func pageParams(pagination *commonpb.Pagination) (int32, int32) {
+	page := pagination.GetPage()
+	pageSize := pagination.GetPageSize()
 
 	if page <= 0 {
 		page = 1
@@ -77,8 +177,18 @@ func (s *ProductServer) ListProducts(ctx context.Context, req *pb.ListProductsRe
 	if pageSize > 100 {
 		pageSize = 100
 	}
+	return page, pageSize
+}
+
+func (s *ProductServer) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	page, pageSize := pageParams(req.Pagination)
+
+	searchQuery := strings.TrimSpace(req.SearchQuery)
+	if len(searchQuery) > maxSearchQueryLength {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("search_query must be at most %d bytes", maxSearchQueryLength))
+	}
 
-	products, totalCount, err := s.repo.List(ctx, page, pageSize, req.Category, req.SearchQuery)
+	products, totalCount, err := s.repo.List(ctx, page, pageSize, req.Category, searchQuery)
 	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list products: %v", err))
 	}
@@ -95,10 +205,63 @@ func (s *ProductServer) ListProducts(ctx context.Context, req *pb.ListProductsRe
 	}, nil
 }
 
+// SearchProducts 商品を関連度順に検索し、一致した語を強調した商品名と説明の抜粋を返す
+// 一致する商品がなければ修正候補を返す
+func (s *ProductServer) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if len(query) > maxSearchQueryLength {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("query must be at most %d bytes", maxSearchQueryLength))
+	}
+	page, pageSize := pageParams(req.Pagination)
+
+	hits, totalCount, err := s.repo.Search(ctx, page, pageSize, req.Category, query)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to search products: %v", err))
+	}
+
+	resp := &pb.SearchProductsResponse{
+		Hits: make([]*pb.ProductSearchHit, 0, len(hits)),
+		Pagination: &commonpb.PaginationResponse{
+			TotalCount:  totalCount,
+			TotalPages:  (totalCount + pageSize - 1) / pageSize,
+			CurrentPage: page,
+		},
+	}
+	for _, hit := range hits {
+		resp.Hits = append(resp.Hits, &pb.ProductSearchHit{
+			Product:         hit.Product,
+			Rank:            hit.Rank,
+			HighlightedName: hit.HighlightedName,
+			Snippet:         hit.Snippet,
+		})
+	}
+
+	if totalCount == 0 {
+		// 修正候補は補助的な情報なので、作れなくても検索結果は返す
+		suggestion, err := s.repo.Suggest(ctx, query)
+		if err != nil {
+			log.Printf("Failed to suggest a search query for %q: %v", query, err)
+		}
+		resp.Suggestion = suggestion
+	}
+
+	return resp, nil
+}
+
 func (s *ProductServer) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.Product, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	if err := validateImageURLs(req.ImageUrls); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sku, err := normalizeSku(req.Sku)
+	if err != nil {
+		return nil, err
+	}
 
 	existing, err := s.repo.GetByID(ctx, req.Id)
 	if err != nil {
@@ -108,12 +271,18 @@ func (s *ProductServer) UpdateProduct(ctx context.Context, req *pb.UpdateProduct
 	existing.Name = req.Name
 	existing.Description = req.Description
 	existing.Price = req.Price
-	existing.StockQuantity = req.StockQuantity
 	existing.Category = req.Category
 	existing.ImageUrls = req.ImageUrls
+	existing.Sku = sku
 	existing.IsActive = req.IsActive
 
-	if err := s.repo.Update(ctx, existing); err != nil {
+	err = s.repo.Update(ctx, existing)
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		return nil, status.Error(codes.NotFound, "product not found")
+	case errors.Is(err, ErrSkuAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update product: %v", err))
 	}
 
@@ -126,18 +295,115 @@ func (s *ProductServer) DeleteProduct(ctx context.Context, req *pb.DeleteProduct
 	}
 
 	if err := s.repo.Delete(ctx, req.Id); err != nil {
+		if errors.Is(err, ErrProductHasMovements) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to delete product: %v", err))
 	}
 
 	return &pb.DeleteProductResponse{Success: true}, nil
 }
 
+// AddProductImage 商品の画像を最後に追加する
+func (s *ProductServer) AddProductImage(ctx context.Context, req *pb.AddProductImageRequest) (*pb.Product, error) {
+	if req.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if err := validateImageURL(req.Url); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.repo.AddImage(ctx, req.ProductId, req.Url, req.AltText); err != nil {
+		return nil, imageError("add", err)
+	}
+
+	return s.getUpdatedProduct(ctx, req.ProductId)
+}
+
+// ReorderProductImages 商品の画像を指定された順に並べ替える
+func (s *ProductServer) ReorderProductImages(ctx context.Context, req *pb.ReorderProductImagesRequest) (*pb.Product, error) {
+	if req.ProductId == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+
+	if err := s.repo.ReorderImages(ctx, req.ProductId, req.ImageIds); err != nil {
+		return nil, imageError("reorder", err)
+	}
+
+	return s.getUpdatedProduct(ctx, req.ProductId)
+}
+
+// RemoveProductImage 商品の画像を削除する
+func (s *ProductServer) RemoveProductImage(ctx context.Context, req *pb.RemoveProductImageRequest) (*pb.Product, error) {
+	if req.ProductId == "" || req.ImageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id and image_id are required")
+	}
+
+	if err := s.repo.RemoveImage(ctx, req.ProductId, req.ImageId); err != nil {
+		return nil, imageError("remove", err)
+	}
+
+	return s.getUpdatedProduct(ctx, req.ProductId)
+}
+
+// imageError 商品の画像の変更のエラーを gRPC のステータスに変換する
+func imageError(action string, err error) error {
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		return status.Error(codes.NotFound, "product not found")
+	case errors.Is(err, ErrImageNotFound):
+		return status.Error(codes.NotFound, "product image not found")
+	case errors.Is(err, ErrImageOrderMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrTooManyImages):
+		return status.Errorf(codes.FailedPrecondition, "a product can have at most %d images", maxProductImages)
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("failed to %s product image: %v", action, err))
+	}
+}
+
+func (s *ProductServer) getUpdatedProduct(ctx context.Context, id string) (*pb.Product, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get updated product")
+	}
+	return product, nil
+}
+
+// UpdateStock 在庫数を増減し、理由とともに台帳に記録する
+// 在庫数が確保中の数量を下回る変更は FailedPrecondition で拒否する
 func (s *ProductServer) UpdateStock(ctx context.Context, req *pb.UpdateStockRequest) (*pb.Product, error) {
 	if req.ProductId == "" {
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
+	if req.QuantityChange == 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity_change must not be zero")
+	}
 
-	if err := s.repo.UpdateStock(ctx, req.ProductId, req.QuantityChange); err != nil {
+	reason := MovementAdjustment
+	if req.Reason != pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED {
+		var ok bool
+		if reason, ok = movementReasons[req.Reason]; !ok {
+			return nil, status.Error(codes.InvalidArgument, "unknown reason")
+		}
+	}
+	if err := validateMovement(reason, req.QuantityChange); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.UpdateStock(ctx, &InventoryMovement{
+		ProductID:      req.ProductId,
+		QuantityChange: req.QuantityChange,
+		Reason:         reason,
+		ReferenceID:    req.ReferenceId,
+		Actor:          actorFromContext(ctx),
+	})
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		return nil, status.Error(codes.NotFound, "product not found")
+	case errors.Is(err, pkgerrors.ErrInsufficientStock):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update stock: %v", err))
 	}
 
@@ -149,20 +415,224 @@ func (s *ProductServer) UpdateStock(ctx context.Context, req *pb.UpdateStockRequ
 	return product, nil
 }
 
+// validateMovement 理由と増減の向きが合っているか確認する
+// 販売は減少、入荷と返品は増加のみで、調整はどちらも認める
+func validateMovement(reason MovementReason, quantityChange int32) error {
+	switch {
+	case reason == MovementSale && quantityChange > 0:
+		return status.Error(codes.InvalidArgument, "a sale must decrease the stock")
+	case (reason == MovementRestock || reason == MovementReturn) && quantityChange < 0:
+		return status.Errorf(codes.InvalidArgument, "a %s must increase the stock", reason)
+	}
+	return nil
+}
+
+// ListInventoryMovements 在庫台帳を新しい順に返す
+func (s *ProductServer) ListInventoryMovements(ctx context.Context, req *pb.ListInventoryMovementsRequest) (*pb.ListInventoryMovementsResponse, error) {
+	page, pageSize := pageParams(req.Pagination)
+
+	filter := MovementFilter{ProductID: req.ProductId, ReferenceID: req.ReferenceId}
+	if req.Reason != pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_UNSPECIFIED {
+		reason, ok := movementReasons[req.Reason]
+		if !ok {
+			return nil, status.Error(codes.InvalidArgument, "unknown reason")
+		}
+		filter.Reason = reason
+	}
+
+	movements, totalCount, err := s.repo.ListMovements(ctx, page, pageSize, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list inventory movements: %v", err))
+	}
+
+	pbMovements := make([]*pb.InventoryMovement, 0, len(movements))
+	for _, movement := range movements {
+		pbMovements = append(pbMovements, toPBMovement(movement))
+	}
+
+	return &pb.ListInventoryMovementsResponse{
+		Movements: pbMovements,
+		Pagination: &commonpb.PaginationResponse{
+			TotalCount:  totalCount,
+			TotalPages:  (totalCount + pageSize - 1) / pageSize,
+			CurrentPage: page,
+		},
+	}, nil
+}
+
+// CheckStock 販売可能数（在庫数 - 確保中の数量）で在庫の有無を返す
 func (s *ProductServer) CheckStock(ctx context.Context, req *pb.CheckStockRequest) (*pb.CheckStockResponse, error) {
 	if req.ProductId == "" {
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
 
-	product, err := s.repo.GetByID(ctx, req.ProductId)
-	if err != nil {
+	onHand, reserved, err := s.reservations.StockLevel(ctx, req.ProductId)
+	if errors.Is(err, ErrProductNotFound) {
 		return nil, status.Error(codes.NotFound, "product not found")
 	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check stock: %v", err))
+	}
 
-	available := product.StockQuantity >= req.RequiredQuantity
+	availableQuantity := onHand - reserved
 
 	return &pb.CheckStockResponse{
-		Available:    available,
-		CurrentStock: product.StockQuantity,
+		Available:         availableQuantity >= req.RequiredQuantity,
+		CurrentStock:      onHand,
+		ReservedQuantity:  reserved,
+		AvailableQuantity: availableQuantity,
 	}, nil
 }
+
+// ReserveStock 注文のために在庫を確保する。全商品を確保できなければ何も確保しない
+// 同じ注文で再度呼ばれた場合は確保中の内容をそのまま返す
+func (s *ProductServer) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.Reservation, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	if len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one item is required")
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl_seconds must not be negative")
+	}
+
+	items := make([]StockHold, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductId == "" {
+			return nil, status.Error(codes.InvalidArgument, "product_id is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+		}
+		items = append(items, StockHold{ProductID: item.ProductId, Quantity: item.Quantity})
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	if ttl == 0 {
+		ttl = defaultReservationTTL
+	}
+	if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+
+	reservation, err := s.reservations.Reserve(ctx, req.OrderId, items, time.Now().Add(ttl))
+	switch {
+	case errors.Is(err, ErrReservationExists):
+		// 再試行。確保中であれば同じ確保を返す
+		existing, err := s.reservations.Get(ctx, req.OrderId)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get reservation: %v", err))
+		}
+		if existing.Status != ReservationHeld {
+			return nil, status.Errorf(codes.FailedPrecondition, "reservation for the order is already %s", existing.Status)
+		}
+		return toPBReservation(existing), nil
+	case errors.Is(err, ErrProductNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pkgerrors.ErrInsufficientStock):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to reserve stock: %v", err))
+	}
+
+	return toPBReservation(reservation), nil
+}
+
+// CommitReservation 確保中の在庫を確定し、在庫数から差し引く
+func (s *ProductServer) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.Reservation, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	reservation, err := s.reservations.Commit(ctx, req.OrderId, actorFromContext(ctx))
+	if err != nil {
+		return nil, reservationError("commit", err)
+	}
+
+	return toPBReservation(reservation), nil
+}
+
+// ReleaseReservation 確保中の在庫を解放する
+func (s *ProductServer) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.Reservation, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	reservation, err := s.reservations.Release(ctx, req.OrderId)
+	if err != nil {
+		return nil, reservationError("release", err)
+	}
+
+	return toPBReservation(reservation), nil
+}
+
+// reservationError 在庫確保の確定・解放のエラーを gRPC のステータスに変換する
+func reservationError(action string, err error) error {
+	switch {
+	case errors.Is(err, ErrReservationNotFound):
+		return status.Error(codes.NotFound, "reservation not found")
+	case errors.Is(err, ErrReservationClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("failed to %s reservation: %v", action, err))
+	}
+}
+
+func toPBReservation(reservation *Reservation) *pb.Reservation {
+	items := make([]*pb.StockHold, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		items = append(items, &pb.StockHold{ProductId: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return &pb.Reservation{
+		OrderId:   reservation.OrderID,
+		Status:    reservationStatuses[reservation.Status],
+		Items:     items,
+		ExpiresAt: &commonpb.Timestamp{Seconds: reservation.ExpiresAt.Unix()},
+	}
+}
+
+var reservationStatuses = map[ReservationStatus]pb.ReservationStatus{
+	ReservationHeld:      pb.ReservationStatus_RESERVATION_STATUS_HELD,
+	ReservationCommitted: pb.ReservationStatus_RESERVATION_STATUS_COMMITTED,
+	ReservationReleased:  pb.ReservationStatus_RESERVATION_STATUS_RELEASED,
+	ReservationExpired:   pb.ReservationStatus_RESERVATION_STATUS_EXPIRED,
+}
+
+var movementReasons = map[pb.InventoryMovementReason]MovementReason{
+	pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_SALE:       MovementSale,
+	pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RESTOCK:    MovementRestock,
+	pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_ADJUSTMENT: MovementAdjustment,
+	pb.InventoryMovementReason_INVENTORY_MOVEMENT_REASON_RETURN:     MovementReturn,
+}
+
+func toPBMovement(movement *InventoryMovement) *pb.InventoryMovement {
+	var reason pb.InventoryMovementReason
+	for pbReason, r := range movementReasons {
+		if r == movement.Reason {
+			reason = pbReason
+		}
+	}
+
+	return &pb.InventoryMovement{
+		Id:             movement.ID,
+		ProductId:      movement.ProductID,
+		QuantityChange: movement.QuantityChange,
+		StockAfter:     movement.StockAfter,
+		Reason:         reason,
+		ReferenceId:    movement.ReferenceID,
+		ActorType:      movement.Actor.Type,
+		ActorId:        movement.Actor.ID,
+		CreatedAt:      &commonpb.Timestamp{Seconds: movement.CreatedAt.Unix()},
+	}
+}
+
+// normalizeSku 前後の空白を除いた SKU を返す
+func normalizeSku(sku string) (string, error) {
+	sku = strings.TrimSpace(sku)
+	if len(sku) > maxSkuLength {
+		return "", status.Errorf(codes.InvalidArgument, "sku must be at most %d characters", maxSkuLength)
+	}
+	return sku, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	commonpb "github.com/Riku-KANO/kube-ec/proto/common"
+	pb "github.com/Riku-KANO/kube-ec/proto/product"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func createSkuProduct(t *testing.T, server *ProductServer, sku string) *pb.Product {
+	t.Helper()
+
+	product, err := server.CreateProduct(context.Background(), &pb.CreateProductRequest{
+		Name:  "Product " + sku,
+		Price: &commonpb.Money{Currency: "JPY", Amount: 1000},
+		Sku:   sku,
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct(%q): %v", sku, err)
+	}
+	return product
+}
+
+func TestProductSku(t *testing.T) {
+	db := openTestDB(t)
+	server := NewProductServer(NewProductRepository(db), NewReservationRepository(db))
+	ctx := context.Background()
+
+	prefix := strings.ToUpper(uuid.New().String()[:8])
+	mug := createSkuProduct(t, server, " "+prefix+"-MUG ")
+	if mug.Sku != prefix+"-MUG" {
+		t.Errorf("sku = %q, want it trimmed", mug.Sku)
+	}
+	plate := createSkuProduct(t, server, prefix+"-PLATE")
+
+	// SKU のない商品は複数登録できる
+	createSkuProduct(t, server, "")
+	createSkuProduct(t, server, "")
+
+	_, err := server.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name:  "Duplicate",
+		Price: &commonpb.Money{Currency: "JPY", Amount: 1000},
+		Sku:   prefix + "-MUG",
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("CreateProduct with a taken sku = %v, want AlreadyExists", err)
+	}
+
+	update := &pb.UpdateProductRequest{
+		Id:       plate.Id,
+		Name:     plate.Name,
+		Price:    plate.Price,
+		Sku:      prefix + "-MUG",
+		IsActive: true,
+	}
+	if _, err := server.UpdateProduct(ctx, update); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("UpdateProduct to a taken sku = %v, want AlreadyExists", err)
+	}
+	update.Sku = prefix + "-DISH"
+	if _, err := server.UpdateProduct(ctx, update); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+
+	product, err := server.GetProductBySku(ctx, &pb.GetProductBySkuRequest{Sku: prefix + "-DISH"})
+	if err != nil {
+		t.Fatalf("GetProductBySku: %v", err)
+	}
+	if product.Id != plate.Id {
+		t.Errorf("GetProductBySku = %s, want %s", product.Id, plate.Id)
+	}
+	if _, err := server.GetProductBySku(ctx, &pb.GetProductBySkuRequest{Sku: prefix + "-PLATE"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetProductBySku of the old sku = %v, want NotFound", err)
+	}
+
+	batch, err := server.BatchGetProductsBySku(ctx, &pb.BatchGetProductsBySkuRequest{
+		Skus: []string{prefix + "-DISH", prefix + "-NONE", prefix + "-MUG", prefix + "-DISH"},
+	})
+	if err != nil {
+		t.Fatalf("BatchGetProductsBySku: %v", err)
+	}
+	if len(batch.Products) != 2 || batch.Products[0].Id != plate.Id || batch.Products[1].Id != mug.Id {
+		t.Errorf("products = %v, want the dish and the mug in request order", batch.Products)
+	}
+	if len(batch.MissingSkus) != 1 || batch.MissingSkus[0] != prefix+"-NONE" {
+		t.Errorf("missing skus = %v, want [%s-NONE]", batch.MissingSkus, prefix)
+	}
+
+	list, err := server.ListProducts(ctx, &pb.ListProductsRequest{
+		Pagination:  &commonpb.Pagination{PageSize: 100},
+		SearchQuery: prefix + "-MU",
+	})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	if len(list.Products) != 1 || list.Products[0].Id != mug.Id {
+		t.Errorf("search by sku = %v, want the mug", list.Products)
+	}
+}
+
+func TestProductSku_Validation(t *testing.T) {
+	server := NewProductServer(nil, nil)
+	ctx := context.Background()
+
+	if _, err := server.GetProductBySku(ctx, &pb.GetProductBySkuRequest{Sku: " "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetProductBySku with an empty sku = %v, want InvalidArgument", err)
+	}
+	if _, err := server.GetProductBySku(ctx, &pb.GetProductBySkuRequest{Sku: strings.Repeat("A", maxSkuLength+1)}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetProductBySku with a long sku = %v, want InvalidArgument", err)
+	}
+
+	tests := []struct {
+		name string
+		skus []string
+	}{
+		{name: "none", skus: nil},
+		{name: "empty sku", skus: []string{"A-1", ""}},
+		{name: "too many", skus: make([]string, maxBatchSkus+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.BatchGetProductsBySku(ctx, &pb.BatchGetProductsBySkuRequest{Skus: tt.skus})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("BatchGetProductsBySku error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}